@@ -3,19 +3,37 @@ package blockchain
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
+	"fmt"
 	"math/big"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	// "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/inventedsarawak/ledgera/internal/config"
 )
 
+// DefaultReceiptTimeout bounds how long we wait for a transaction to be mined.
+const DefaultReceiptTimeout = 2 * time.Minute
+
+// Backend is the subset of the Ethereum RPC surface the contract layer needs.
+// It is satisfied by *ethclient.Client as well as the go-ethereum simulated
+// backend (ethclient/simulated), which is what the tests run against.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	ethereum.BlockNumberReader
+}
+
 type Client struct {
-	Eth             *ethclient.Client
-	Cfg             config.BlockchainConfig
-	privateKey      *ecdsa.PrivateKey
+	Eth        Backend
+	Cfg        config.BlockchainConfig
+	privateKey *ecdsa.PrivateKey
 }
 
 func NewClient(cfg config.BlockchainConfig) (*Client, error) {
@@ -24,19 +42,21 @@ func NewClient(cfg config.BlockchainConfig) (*Client, error) {
 		return nil, err
 	}
 
+	return NewClientWithBackend(cfg, client)
+}
+
+// NewClientWithBackend builds a Client on top of an already connected backend.
+func NewClientWithBackend(cfg config.BlockchainConfig, backend Backend) (*Client, error) {
 	// Parse private key
-	privateKey, err := crypto.HexToECDSA(cfg.AdminPrivateKey[2:]) // Remove 0x prefix
+	privateKey, err := crypto.HexToECDSA(trimHexPrefix(cfg.AdminPrivateKey))
 	if err != nil {
 		return nil, err
 	}
 
-	// Load Contract Here
-	
-
 	return &Client{
-		Eth:             client,
-		Cfg:             cfg,
-		privateKey:      privateKey,
+		Eth:        backend,
+		Cfg:        cfg,
+		privateKey: privateKey,
 	}, nil
 }
 
@@ -48,20 +68,16 @@ func (c *Client) GetTransactOpts(ctx context.Context) (*bind.TransactOpts, error
 	}
 
 	// Get nonce
-	publicKey := c.privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, err
-	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	nonce, err := c.Eth.PendingNonceAt(ctx, fromAddress)
+	nonce, err := c.Eth.PendingNonceAt(ctx, c.Address())
 	if err != nil {
 		return nil, err
 	}
 
 	auth.Nonce = big.NewInt(int64(nonce))
 	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(300000)
+	// Left at zero so the gas limit is estimated per call: createAsset deploys
+	// a whole ERC20 and does not fit in a fixed budget.
+	auth.GasLimit = 0
 	auth.Context = ctx
 
 	return auth, nil
@@ -72,3 +88,69 @@ func (c *Client) GetCallOpts(ctx context.Context) *bind.CallOpts {
 		Context: ctx,
 	}
 }
+
+// Address returns the admin account used to sign transactions.
+func (c *Client) Address() common.Address {
+	return crypto.PubkeyToAddress(c.privateKey.PublicKey)
+}
+
+// RegistryAddress returns the configured AssetRegistry (factory) address.
+func (c *Client) RegistryAddress() (common.Address, error) {
+	if !common.IsHexAddress(c.Cfg.FactoryAddress) {
+		return common.Address{}, errors.New("blockchain: factory_address is not a valid address")
+	}
+	return common.HexToAddress(c.Cfg.FactoryAddress), nil
+}
+
+// WaitMined waits for a submitted transaction and returns its receipt, or
+// ErrTransactionReverted if it was mined but failed.
+func (c *Client) WaitMined(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, DefaultReceiptTimeout)
+	defer cancel()
+
+	receipt, err := bind.WaitMinedHash(waitCtx, c.Eth, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for transaction %s: %w", txHash.Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionReverted, txHash.Hex())
+	}
+
+	return receipt, nil
+}
+
+// Receipt returns the receipt of a mined transaction without waiting for it.
+// A transaction that is unknown or still pending returns
+// ErrTransactionNotFound. Reverted transactions are returned as is, so callers
+// can report them.
+func (c *Client) Receipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := c.Eth.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, txHash.Hex())
+		}
+		return nil, fmt.Errorf("failed to fetch receipt %s: %w", txHash.Hex(), err)
+	}
+	return receipt, nil
+}
+
+// SignText signs data as an EIP-191 personal message with the admin key and
+// returns the 65 byte [R || S || V] signature, V being 27 or 28. Anyone can
+// check it against Address() with the usual personal_sign recovery
+// (ethers.verifyMessage, viem's verifyMessage, ...).
+func (c *Client) SignText(data []byte) ([]byte, error) {
+	sig, err := crypto.Sign(accounts.TextHash(data), c.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// trimHexPrefix strips an optional 0x prefix from a hex encoded key.
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+	return s
+}
@@ -0,0 +1,348 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// AssetRegistryMetaData contains all meta data concerning the AssetRegistry contract.
+var AssetRegistryMetaData = &bind.MetaData{
+	ABI: "[{\"type\":\"function\",\"name\":\"createAsset\",\"inputs\":[{\"name\":\"name\",\"type\":\"string\",\"internalType\":\"string\"},{\"name\":\"symbol\",\"type\":\"string\",\"internalType\":\"string\"}],\"outputs\":[{\"name\":\"\",\"type\":\"address\",\"internalType\":\"address\"}],\"stateMutability\":\"nonpayable\"},{\"type\":\"event\",\"name\":\"AssetCreated\",\"inputs\":[{\"name\":\"assetAddress\",\"type\":\"address\",\"indexed\":true,\"internalType\":\"address\"},{\"name\":\"name\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"},{\"name\":\"symbol\",\"type\":\"string\",\"indexed\":false,\"internalType\":\"string\"}],\"anonymous\":false}]",
+}
+
+// AssetRegistryABI is the input ABI used to generate the binding from.
+// Deprecated: Use AssetRegistryMetaData.ABI instead.
+var AssetRegistryABI = AssetRegistryMetaData.ABI
+
+// AssetRegistry is an auto generated Go binding around an Ethereum contract.
+type AssetRegistry struct {
+	AssetRegistryCaller     // Read-only binding to the contract
+	AssetRegistryTransactor // Write-only binding to the contract
+	AssetRegistryFilterer   // Log filterer for contract events
+}
+
+// AssetRegistryCaller is an auto generated read-only Go binding around an Ethereum contract.
+type AssetRegistryCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AssetRegistryTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type AssetRegistryTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AssetRegistryFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type AssetRegistryFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AssetRegistrySession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type AssetRegistrySession struct {
+	Contract     *AssetRegistry    // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// AssetRegistryCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type AssetRegistryCallerSession struct {
+	Contract *AssetRegistryCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts        // Call options to use throughout this session
+}
+
+// AssetRegistryTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type AssetRegistryTransactorSession struct {
+	Contract     *AssetRegistryTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// AssetRegistryRaw is an auto generated low-level Go binding around an Ethereum contract.
+type AssetRegistryRaw struct {
+	Contract *AssetRegistry // Generic contract binding to access the raw methods on
+}
+
+// AssetRegistryCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type AssetRegistryCallerRaw struct {
+	Contract *AssetRegistryCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// AssetRegistryTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type AssetRegistryTransactorRaw struct {
+	Contract *AssetRegistryTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewAssetRegistry creates a new instance of AssetRegistry, bound to a specific deployed contract.
+func NewAssetRegistry(address common.Address, backend bind.ContractBackend) (*AssetRegistry, error) {
+	contract, err := bindAssetRegistry(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetRegistry{AssetRegistryCaller: AssetRegistryCaller{contract: contract}, AssetRegistryTransactor: AssetRegistryTransactor{contract: contract}, AssetRegistryFilterer: AssetRegistryFilterer{contract: contract}}, nil
+}
+
+// NewAssetRegistryCaller creates a new read-only instance of AssetRegistry, bound to a specific deployed contract.
+func NewAssetRegistryCaller(address common.Address, caller bind.ContractCaller) (*AssetRegistryCaller, error) {
+	contract, err := bindAssetRegistry(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetRegistryCaller{contract: contract}, nil
+}
+
+// NewAssetRegistryTransactor creates a new write-only instance of AssetRegistry, bound to a specific deployed contract.
+func NewAssetRegistryTransactor(address common.Address, transactor bind.ContractTransactor) (*AssetRegistryTransactor, error) {
+	contract, err := bindAssetRegistry(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetRegistryTransactor{contract: contract}, nil
+}
+
+// NewAssetRegistryFilterer creates a new log filterer instance of AssetRegistry, bound to a specific deployed contract.
+func NewAssetRegistryFilterer(address common.Address, filterer bind.ContractFilterer) (*AssetRegistryFilterer, error) {
+	contract, err := bindAssetRegistry(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetRegistryFilterer{contract: contract}, nil
+}
+
+// bindAssetRegistry binds a generic wrapper to an already deployed contract.
+func bindAssetRegistry(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := AssetRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AssetRegistry *AssetRegistryRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AssetRegistry.Contract.AssetRegistryCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AssetRegistry *AssetRegistryRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AssetRegistry.Contract.AssetRegistryTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AssetRegistry *AssetRegistryRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AssetRegistry.Contract.AssetRegistryTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AssetRegistry *AssetRegistryCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _AssetRegistry.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AssetRegistry *AssetRegistryTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AssetRegistry.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AssetRegistry *AssetRegistryTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AssetRegistry.Contract.contract.Transact(opts, method, params...)
+}
+
+// CreateAsset is a paid mutator transaction binding the contract method 0xa2a55b68.
+//
+// Solidity: function createAsset(string name, string symbol) returns(address)
+func (_AssetRegistry *AssetRegistryTransactor) CreateAsset(opts *bind.TransactOpts, name string, symbol string) (*types.Transaction, error) {
+	return _AssetRegistry.contract.Transact(opts, "createAsset", name, symbol)
+}
+
+// CreateAsset is a paid mutator transaction binding the contract method 0xa2a55b68.
+//
+// Solidity: function createAsset(string name, string symbol) returns(address)
+func (_AssetRegistry *AssetRegistrySession) CreateAsset(name string, symbol string) (*types.Transaction, error) {
+	return _AssetRegistry.Contract.CreateAsset(&_AssetRegistry.TransactOpts, name, symbol)
+}
+
+// CreateAsset is a paid mutator transaction binding the contract method 0xa2a55b68.
+//
+// Solidity: function createAsset(string name, string symbol) returns(address)
+func (_AssetRegistry *AssetRegistryTransactorSession) CreateAsset(name string, symbol string) (*types.Transaction, error) {
+	return _AssetRegistry.Contract.CreateAsset(&_AssetRegistry.TransactOpts, name, symbol)
+}
+
+// AssetRegistryAssetCreatedIterator is returned from FilterAssetCreated and is used to iterate over the raw logs and unpacked data for AssetCreated events raised by the AssetRegistry contract.
+type AssetRegistryAssetCreatedIterator struct {
+	Event *AssetRegistryAssetCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AssetRegistryAssetCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AssetRegistryAssetCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AssetRegistryAssetCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AssetRegistryAssetCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AssetRegistryAssetCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AssetRegistryAssetCreated represents a AssetCreated event raised by the AssetRegistry contract.
+type AssetRegistryAssetCreated struct {
+	AssetAddress common.Address
+	Name         string
+	Symbol       string
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterAssetCreated is a free log retrieval operation binding the contract event 0xa87566419658c36d7bd865066c1afb99599c60288ef6a0e107d517ecf739a182.
+//
+// Solidity: event AssetCreated(address indexed assetAddress, string name, string symbol)
+func (_AssetRegistry *AssetRegistryFilterer) FilterAssetCreated(opts *bind.FilterOpts, assetAddress []common.Address) (*AssetRegistryAssetCreatedIterator, error) {
+
+	var assetAddressRule []interface{}
+	for _, assetAddressItem := range assetAddress {
+		assetAddressRule = append(assetAddressRule, assetAddressItem)
+	}
+
+	logs, sub, err := _AssetRegistry.contract.FilterLogs(opts, "AssetCreated", assetAddressRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AssetRegistryAssetCreatedIterator{contract: _AssetRegistry.contract, event: "AssetCreated", logs: logs, sub: sub}, nil
+}
+
+// WatchAssetCreated is a free log subscription operation binding the contract event 0xa87566419658c36d7bd865066c1afb99599c60288ef6a0e107d517ecf739a182.
+//
+// Solidity: event AssetCreated(address indexed assetAddress, string name, string symbol)
+func (_AssetRegistry *AssetRegistryFilterer) WatchAssetCreated(opts *bind.WatchOpts, sink chan<- *AssetRegistryAssetCreated, assetAddress []common.Address) (event.Subscription, error) {
+
+	var assetAddressRule []interface{}
+	for _, assetAddressItem := range assetAddress {
+		assetAddressRule = append(assetAddressRule, assetAddressItem)
+	}
+
+	logs, sub, err := _AssetRegistry.contract.WatchLogs(opts, "AssetCreated", assetAddressRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AssetRegistryAssetCreated)
+				if err := _AssetRegistry.contract.UnpackLog(event, "AssetCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAssetCreated is a log parse operation binding the contract event 0xa87566419658c36d7bd865066c1afb99599c60288ef6a0e107d517ecf739a182.
+//
+// Solidity: event AssetCreated(address indexed assetAddress, string name, string symbol)
+func (_AssetRegistry *AssetRegistryFilterer) ParseAssetCreated(log types.Log) (*AssetRegistryAssetCreated, error) {
+	event := new(AssetRegistryAssetCreated)
+	if err := _AssetRegistry.contract.UnpackLog(event, "AssetCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
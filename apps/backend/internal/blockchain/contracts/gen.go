@@ -0,0 +1,7 @@
+// Package contracts holds the Go bindings for the Foundry contracts in
+// contracts/src. The .abi files are extracted from the forge build output
+// (see the contracts:bind task); do not edit the generated files by hand.
+package contracts
+
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi AssetRegistry.abi --pkg contracts --type AssetRegistry --out asset_registry.go
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen --abi AssetToken.abi --pkg contracts --type AssetToken --out asset_token.go
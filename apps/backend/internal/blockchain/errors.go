@@ -0,0 +1,96 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/inventedsarawak/ledgera/internal/blockchain/contracts"
+)
+
+var (
+	ErrTransactionReverted   = errors.New("blockchain: transaction reverted")
+	ErrTransactionNotFound   = errors.New("blockchain: transaction not found or not yet mined")
+	ErrNoContract            = errors.New("blockchain: no contract deployed at address")
+	ErrNotOwner              = errors.New("blockchain: signer is not the contract owner")
+	ErrInsufficientBalance   = errors.New("blockchain: insufficient token balance")
+	ErrInsufficientAllowance = errors.New("blockchain: insufficient token allowance")
+	ErrInvalidAddress        = errors.New("blockchain: invalid address")
+	ErrInvalidAmount         = errors.New("blockchain: amount must be positive")
+)
+
+// revertErrors maps the OpenZeppelin custom errors AssetToken can revert with
+// to the sentinels above.
+var revertErrors = map[string]error{
+	"OwnableUnauthorizedAccount": ErrNotOwner,
+	"OwnableInvalidOwner":        ErrInvalidAddress,
+	"ERC20InsufficientBalance":   ErrInsufficientBalance,
+	"ERC20InsufficientAllowance": ErrInsufficientAllowance,
+	"ERC20InvalidSender":         ErrInvalidAddress,
+	"ERC20InvalidReceiver":       ErrInvalidAddress,
+	"ERC20InvalidApprover":       ErrInvalidAddress,
+	"ERC20InvalidSpender":        ErrInvalidAddress,
+}
+
+// contractErrors is every custom error declared by the bound contracts,
+// keyed by selector.
+var contractErrors = func() map[[4]byte]abi.Error {
+	out := make(map[[4]byte]abi.Error)
+	for _, meta := range []*bind.MetaData{contracts.AssetRegistryMetaData, contracts.AssetTokenMetaData} {
+		parsed, err := meta.GetAbi()
+		if err != nil {
+			panic(fmt.Sprintf("blockchain: invalid contract ABI: %v", err))
+		}
+		for _, e := range parsed.Errors {
+			out[[4]byte(e.ID[:4])] = e
+		}
+	}
+	return out
+}()
+
+// mapError wraps an error returned by a contract binding with the operation
+// that failed and, when the node returned revert data for a known custom
+// error, with the matching sentinel so callers can use errors.Is.
+func mapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, bind.ErrNoCode) {
+		return fmt.Errorf("%s: %w", op, ErrNoContract)
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := revertData(dataErr.ErrorData()); ok && len(data) >= 4 {
+			if e, found := contractErrors[[4]byte(data[:4])]; found {
+				sentinel, known := revertErrors[e.Name]
+				if !known {
+					sentinel = ErrTransactionReverted
+				}
+				if args, unpackErr := e.Unpack(data); unpackErr == nil {
+					return fmt.Errorf("%s: %w: %s%v", op, sentinel, e.Name, args)
+				}
+				return fmt.Errorf("%s: %w: %s", op, sentinel, e.Name)
+			}
+			return fmt.Errorf("%s: %w: %v", op, ErrTransactionReverted, err)
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+func revertData(data any) ([]byte, bool) {
+	switch v := data.(type) {
+	case string:
+		decoded, err := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+		return decoded, err == nil
+	case []byte:
+		return v, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,180 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/inventedsarawak/ledgera/internal/blockchain/contracts"
+)
+
+var transferTopic = func() common.Hash {
+	parsed, err := contracts.AssetTokenMetaData.GetAbi()
+	if err != nil {
+		panic(fmt.Sprintf("blockchain: invalid AssetToken ABI: %v", err))
+	}
+	return parsed.Events["Transfer"].ID
+}()
+
+// EventPosition locates a log on chain.
+type EventPosition struct {
+	TxHash      common.Hash
+	LogIndex    uint
+	BlockNumber uint64
+	BlockHash   common.Hash
+}
+
+func positionOf(log types.Log) EventPosition {
+	return EventPosition{
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		BlockNumber: log.BlockNumber,
+		BlockHash:   log.BlockHash,
+	}
+}
+
+// AssetCreatedLog is an AssetCreated event read from the log index.
+type AssetCreatedLog struct {
+	EventPosition
+	AssetAddress common.Address
+	Name         string
+	Symbol       string
+}
+
+// TransferLog is an ERC20 Transfer event of an AssetToken.
+type TransferLog struct {
+	EventPosition
+	Token common.Address
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+}
+
+// BlockNumber returns the current head of the chain.
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.Eth.BlockNumber(ctx)
+}
+
+// BlockHash returns the hash of the canonical block at number.
+func (c *Client) BlockHash(ctx context.Context, number uint64) (common.Hash, error) {
+	header, err := c.Eth.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to fetch header %d: %w", number, err)
+	}
+	return header.Hash(), nil
+}
+
+// AssetCreatedLogs returns the AssetCreated events the registry emitted in
+// the inclusive block range [from, to].
+func (r *Registry) AssetCreatedLogs(ctx context.Context, from uint64, to uint64) ([]AssetCreatedLog, error) {
+	logs, err := r.client.Eth.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{r.address},
+		Topics:    [][]common.Hash{{assetCreatedTopic}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter AssetCreated logs: %w", err)
+	}
+
+	events := make([]AssetCreatedLog, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		event, err := r.contract.ParseAssetCreated(log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode AssetCreated log %s/%d: %w", log.TxHash.Hex(), log.Index, err)
+		}
+		events = append(events, AssetCreatedLog{
+			EventPosition: positionOf(log),
+			AssetAddress:  event.AssetAddress,
+			Name:          event.Name,
+			Symbol:        event.Symbol,
+		})
+	}
+	return events, nil
+}
+
+// TransferLogs returns the Transfer events emitted by any of tokens in the
+// inclusive block range [from, to].
+func (c *Client) TransferLogs(ctx context.Context, tokens []common.Address, from uint64, to uint64) ([]TransferLog, error) {
+	if len(tokens) == 0 {
+		return []TransferLog{}, nil
+	}
+
+	logs, err := c.Eth.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: tokens,
+		Topics:    [][]common.Hash{{transferTopic}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter Transfer logs: %w", err)
+	}
+
+	// Any AssetToken binding can decode the log, the address is not checked.
+	decoder, err := contracts.NewAssetTokenFilterer(common.Address{}, c.Eth)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]TransferLog, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		event, err := decoder.ParseTransfer(log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Transfer log %s/%d: %w", log.TxHash.Hex(), log.Index, err)
+		}
+		events = append(events, TransferLog{
+			EventPosition: positionOf(log),
+			Token:         log.Address,
+			From:          event.From,
+			To:            event.To,
+			Value:         event.Value,
+		})
+	}
+	return events, nil
+}
+
+// TransfersInTx returns the Transfer events emitted by token in a mined
+// transaction. It does not wait: a transaction that is unknown or still
+// pending returns ErrTransactionNotFound.
+func (c *Client) TransfersInTx(ctx context.Context, txHash common.Hash, token common.Address) ([]TransferLog, error) {
+	receipt, err := c.Receipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionReverted, txHash.Hex())
+	}
+
+	decoder, err := contracts.NewAssetTokenFilterer(token, c.Eth)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []TransferLog{}
+	for _, log := range receipt.Logs {
+		if log.Address != token || len(log.Topics) == 0 || log.Topics[0] != transferTopic {
+			continue
+		}
+		event, err := decoder.ParseTransfer(*log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Transfer log %s/%d: %w", log.TxHash.Hex(), log.Index, err)
+		}
+		events = append(events, TransferLog{
+			EventPosition: positionOf(*log),
+			Token:         log.Address,
+			From:          event.From,
+			To:            event.To,
+			Value:         event.Value,
+		})
+	}
+	return events, nil
+}
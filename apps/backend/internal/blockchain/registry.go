@@ -0,0 +1,116 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/inventedsarawak/ledgera/internal/blockchain/contracts"
+)
+
+var assetCreatedTopic = func() common.Hash {
+	parsed, err := contracts.AssetRegistryMetaData.GetAbi()
+	if err != nil {
+		panic(fmt.Sprintf("blockchain: invalid AssetRegistry ABI: %v", err))
+	}
+	return parsed.Events["AssetCreated"].ID
+}()
+
+// AssetCreated is the decoded AssetCreated event of a createAsset transaction.
+type AssetCreated struct {
+	AssetAddress common.Address
+	Name         string
+	Symbol       string
+	TxHash       common.Hash
+	BlockNumber  uint64
+}
+
+// Registry is the typed API over the configured AssetRegistry contract.
+type Registry struct {
+	client   *Client
+	address  common.Address
+	contract *contracts.AssetRegistry
+}
+
+// Registry binds the AssetRegistry at the configured factory address.
+func (c *Client) Registry() (*Registry, error) {
+	address, err := c.RegistryAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := contracts.NewAssetRegistry(address, c.Eth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Registry{client: c, address: address, contract: contract}, nil
+}
+
+func (r *Registry) Address() common.Address {
+	return r.address
+}
+
+// CreateAsset calls AssetRegistry.createAsset and blocks until the
+// transaction is mined, returning the token address from the emitted event.
+func (r *Registry) CreateAsset(ctx context.Context, name string, symbol string) (*AssetCreated, error) {
+	txHash, err := r.SendCreateAsset(ctx, name, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.WaitAssetCreated(ctx, txHash)
+}
+
+// SendCreateAsset submits AssetRegistry.createAsset without waiting for it to
+// be mined.
+func (r *Registry) SendCreateAsset(ctx context.Context, name string, symbol string) (common.Hash, error) {
+	opts, err := r.client.GetTransactOpts(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to build transact opts: %w", err)
+	}
+
+	tx, err := r.contract.CreateAsset(opts, name, symbol)
+	if err != nil {
+		return common.Hash{}, mapError("createAsset", err)
+	}
+
+	return tx.Hash(), nil
+}
+
+// WaitAssetCreated waits for an already submitted createAsset transaction and
+// decodes its AssetCreated event. It lets callers resume after a restart.
+func (r *Registry) WaitAssetCreated(ctx context.Context, txHash common.Hash) (*AssetCreated, error) {
+	receipt, err := r.client.WaitMined(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ParseAssetCreated(receipt)
+}
+
+// ParseAssetCreated extracts the AssetCreated event this registry emitted in
+// the given receipt.
+func (r *Registry) ParseAssetCreated(receipt *types.Receipt) (*AssetCreated, error) {
+	for _, log := range receipt.Logs {
+		if log.Address != r.address || len(log.Topics) == 0 || log.Topics[0] != assetCreatedTopic {
+			continue
+		}
+
+		event, err := r.contract.ParseAssetCreated(*log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode AssetCreated event: %w", err)
+		}
+
+		return &AssetCreated{
+			AssetAddress: event.AssetAddress,
+			Name:         event.Name,
+			Symbol:       event.Symbol,
+			TxHash:       receipt.TxHash,
+			BlockNumber:  receipt.BlockNumber.Uint64(),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no AssetCreated event in transaction %s", receipt.TxHash.Hex())
+}
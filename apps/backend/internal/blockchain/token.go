@@ -0,0 +1,126 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/inventedsarawak/ledgera/internal/blockchain/contracts"
+)
+
+// Token is the typed API over a deployed AssetToken. Transactions are signed
+// by the client's admin key, which owns every token the registry creates.
+type Token struct {
+	client   *Client
+	address  common.Address
+	contract *contracts.AssetToken
+}
+
+// Token binds the AssetToken deployed at address.
+func (c *Client) Token(address common.Address) (*Token, error) {
+	if address == (common.Address{}) {
+		return nil, ErrInvalidAddress
+	}
+
+	contract, err := contracts.NewAssetToken(address, c.Eth)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{client: c, address: address, contract: contract}, nil
+}
+
+func (t *Token) Address() common.Address {
+	return t.address
+}
+
+func (t *Token) BalanceOf(ctx context.Context, account common.Address) (*big.Int, error) {
+	balance, err := t.contract.BalanceOf(t.client.GetCallOpts(ctx), account)
+	if err != nil {
+		return nil, mapError("balanceOf", err)
+	}
+	return balance, nil
+}
+
+func (t *Token) TotalSupply(ctx context.Context) (*big.Int, error) {
+	supply, err := t.contract.TotalSupply(t.client.GetCallOpts(ctx))
+	if err != nil {
+		return nil, mapError("totalSupply", err)
+	}
+	return supply, nil
+}
+
+func (t *Token) Decimals(ctx context.Context) (uint8, error) {
+	decimals, err := t.contract.Decimals(t.client.GetCallOpts(ctx))
+	if err != nil {
+		return 0, mapError("decimals", err)
+	}
+	return decimals, nil
+}
+
+// Mint mints amount base units to the given account and waits for the receipt.
+func (t *Token) Mint(ctx context.Context, to common.Address, amount *big.Int) (*types.Receipt, error) {
+	txHash, err := t.SendMint(ctx, to, amount)
+	if err != nil {
+		return nil, err
+	}
+	return t.client.WaitMined(ctx, txHash)
+}
+
+// SendMint submits AssetToken.mint without waiting for it to be mined.
+func (t *Token) SendMint(ctx context.Context, to common.Address, amount *big.Int) (common.Hash, error) {
+	if err := checkTransfer(to, amount); err != nil {
+		return common.Hash{}, err
+	}
+
+	opts, err := t.client.GetTransactOpts(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to build transact opts: %w", err)
+	}
+
+	tx, err := t.contract.Mint(opts, to, amount)
+	if err != nil {
+		return common.Hash{}, mapError("mint", err)
+	}
+	return tx.Hash(), nil
+}
+
+// Transfer moves amount base units from the admin account and waits for the
+// receipt.
+func (t *Token) Transfer(ctx context.Context, to common.Address, amount *big.Int) (*types.Receipt, error) {
+	txHash, err := t.SendTransfer(ctx, to, amount)
+	if err != nil {
+		return nil, err
+	}
+	return t.client.WaitMined(ctx, txHash)
+}
+
+// SendTransfer submits AssetToken.transfer without waiting for it to be mined.
+func (t *Token) SendTransfer(ctx context.Context, to common.Address, amount *big.Int) (common.Hash, error) {
+	if err := checkTransfer(to, amount); err != nil {
+		return common.Hash{}, err
+	}
+
+	opts, err := t.client.GetTransactOpts(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to build transact opts: %w", err)
+	}
+
+	tx, err := t.contract.Transfer(opts, to, amount)
+	if err != nil {
+		return common.Hash{}, mapError("transfer", err)
+	}
+	return tx.Hash(), nil
+}
+
+func checkTransfer(to common.Address, amount *big.Int) error {
+	if to == (common.Address{}) {
+		return ErrInvalidAddress
+	}
+	if amount == nil || amount.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+	return nil
+}
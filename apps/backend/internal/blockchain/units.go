@@ -0,0 +1,37 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ToBaseUnits converts a decimal quantity such as "12.50" into the integer
+// base units of a token with the given decimals. Quantities that need more
+// precision than the token has are rejected rather than rounded.
+func ToBaseUnits(amount string, decimals uint8) (*big.Int, error) {
+	r, ok := new(big.Rat).SetString(amount)
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	r.Mul(r, new(big.Rat).SetInt(scale))
+	if !r.IsInt() {
+		return nil, fmt.Errorf("%w: %s does not fit in %d decimals", ErrInvalidAmount, amount, decimals)
+	}
+
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// FromBaseUnits converts integer token base units back into a decimal
+// quantity, without trailing zeros: 12500000000000000000 with 18 decimals is
+// "12.5".
+func FromBaseUnits(amount *big.Int, decimals uint8) string {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	s := new(big.Rat).SetFrac(amount, scale).FloatString(int(decimals))
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
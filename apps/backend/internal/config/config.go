@@ -21,6 +21,7 @@ type Config struct {
 	Observability *ObservabilityConfig `koanf:"observability"`
 	StorageBucket StorageBucketConfig  `koanf:"storage_bucket" validate:"required"`
 	Blockhain     BlockchainConfig     `koanf:"blockchain" validate:"required"`
+	Marketplace   MarketplaceConfig    `koanf:"marketplace"`
 }
 
 type Primary struct {
@@ -33,6 +34,10 @@ type ServerConfig struct {
 	WriteTimeout       int      `koanf:"write_timeout" validate:"required"`
 	IdleTimeout        int      `koanf:"idle_timeout" validate:"required"`
 	CORSAllowedOrigins []string `koanf:"cors_allowed_origins" validate:"required"`
+
+	// PublicURL is the externally reachable base URL of the API, used for
+	// links printed on documents. Optional.
+	PublicURL string `koanf:"public_url" validate:"omitempty,url"`
 }
 
 type DatabaseConfig struct {
@@ -62,8 +67,22 @@ type BlockchainConfig struct {
 	RpcUrl          string `koanf:"rpc_url" validate:"required,url"`
 	ChainID         int    `koanf:"chain_id" validate:"required"`
 	FactoryAddress  string `koanf:"factory_address" validate:"required"`
-	CounterAddress  string `koanf:"counter_address" validate:"required"`
 	AdminPrivateKey string `koanf:"admin_private_key" validate:"required"`
+
+	// TokenDecimals is the precision used to convert tonnes into token units;
+	// zero means use the token's own decimals().
+	TokenDecimals uint8 `koanf:"token_decimals"`
+
+	// Indexer settings; zero values fall back to the indexer defaults.
+	IndexerStartBlock uint64 `koanf:"indexer_start_block"`
+	IndexerBatchSize  uint64 `koanf:"indexer_batch_size"`
+	ReorgDepth        uint64 `koanf:"reorg_depth"`
+}
+
+type MarketplaceConfig struct {
+	// ReservationTTL is how many seconds a placed order holds its quantity
+	// before it expires; zero means the order service default.
+	ReservationTTL int `koanf:"reservation_ttl"`
 }
 
 type RedisConfig struct {
@@ -72,10 +91,36 @@ type RedisConfig struct {
 type AuthConfig struct {
 	SecretKey string `koanf:"secret_key" validate:"required"`
 	MockUserID string `koanf:"mock_user_id"`
+	// WebhookSigningSecret is the "whsec_..." secret of the Clerk webhook
+	// endpoint. /webhooks/clerk is disabled while it is empty.
+	WebhookSigningSecret string `koanf:"webhook_signing_secret"`
+	// OrganizationID is the Clerk organization whose memberships grant
+	// roles. Memberships of any other organization are ignored, as are all
+	// of them while it is empty.
+	OrganizationID string `koanf:"organization_id"`
+	// AccountRetentionDays is how long a deleted account is kept before it
+	// is purged; zero means the user service default.
+	AccountRetentionDays int `koanf:"account_retention_days" validate:"omitempty,min=1"`
 }
 
 type IntegrationConfig struct {
-	ResendAPIKey string `koanf:"resend_api_key" validate:"required"`
+	// EmailTransport selects how emails are delivered: "resend" (default),
+	// "smtp" or "file", which writes .eml files to EmailOutboxDir.
+	EmailTransport string `koanf:"email_transport" validate:"omitempty,oneof=resend smtp file"`
+
+	ResendAPIKey string `koanf:"resend_api_key" validate:"required_if=EmailTransport resend"`
+
+	SMTPHost     string `koanf:"smtp_host" validate:"required_if=EmailTransport smtp"`
+	SMTPPort     int    `koanf:"smtp_port" validate:"required_if=EmailTransport smtp"`
+	SMTPUsername string `koanf:"smtp_username"`
+	SMTPPassword string `koanf:"smtp_password"`
+
+	EmailOutboxDir string `koanf:"email_outbox_dir" validate:"required_if=EmailTransport file"`
+
+	// Sender identity; empty values fall back to the email package defaults.
+	EmailFromName    string `koanf:"email_from_name"`
+	EmailFromAddress string `koanf:"email_from_address" validate:"omitempty,email"`
+	EmailReplyTo     string `koanf:"email_reply_to" validate:"omitempty,email"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -96,6 +141,10 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if mainConfig.Integration.EmailTransport == "" {
+		mainConfig.Integration.EmailTransport = "resend"
+	}
+
 	validate := validator.New()
 	if err = validate.Struct(mainConfig); err != nil {
 		logger.Fatal().Err(err).Msg("configuration validation failed")
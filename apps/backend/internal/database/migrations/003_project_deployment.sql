@@ -0,0 +1,9 @@
+-- Write your migrate up statements here
+
+-- Hash of the AssetRegistry.createAsset transaction, persisted before we wait
+-- for the receipt so a retried deploy job resumes instead of deploying twice.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS deploy_tx_hash TEXT UNIQUE;
+
+---- create above / drop below ----
+
+ALTER TABLE projects DROP COLUMN IF EXISTS deploy_tx_hash;
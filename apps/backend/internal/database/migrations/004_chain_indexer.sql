@@ -0,0 +1,56 @@
+-- Write your migrate up statements here
+
+-- Progress of the chain indexer. last_block_hash lets the next run detect a
+-- reorg below the checkpoint and rewind.
+CREATE TABLE IF NOT EXISTS indexer_checkpoints (
+    name TEXT PRIMARY KEY,
+    last_block BIGINT NOT NULL,
+    last_block_hash TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Every ERC20 Transfer of a project token, amounts in token base units.
+CREATE TABLE IF NOT EXISTS token_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID REFERENCES projects(id),
+    contract_address TEXT NOT NULL,
+
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    amount NUMERIC(78, 0) NOT NULL,
+
+    tx_hash TEXT NOT NULL,
+    log_index INTEGER NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_hash TEXT NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    UNIQUE (tx_hash, log_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_transfers_contract ON token_transfers(contract_address);
+CREATE INDEX IF NOT EXISTS idx_token_transfers_block ON token_transfers(block_number);
+
+-- Current balances, derived from token_transfers.
+CREATE TABLE IF NOT EXISTS token_holdings (
+    contract_address TEXT NOT NULL,
+    holder_address TEXT NOT NULL,
+    project_id UUID REFERENCES projects(id),
+    balance NUMERIC(78, 0) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (contract_address, holder_address)
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_holdings_holder ON token_holdings(holder_address);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_token_holdings_holder;
+DROP INDEX IF EXISTS idx_token_transfers_block;
+DROP INDEX IF EXISTS idx_token_transfers_contract;
+
+DROP TABLE IF EXISTS token_holdings;
+DROP TABLE IF EXISTS token_transfers;
+DROP TABLE IF EXISTS indexer_checkpoints;
@@ -0,0 +1,42 @@
+-- Write your migrate up statements here
+
+-- One row per mint of a project's verified carbon credits. PENDING and
+-- CONFIRMED rows count towards carbon_amount_total, so a project can never be
+-- issued more tonnes than were verified; FAILED rows release their tonnes.
+CREATE TABLE IF NOT EXISTS token_issuances (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id),
+    issued_by TEXT REFERENCES users(clerk_id),
+
+    recipient_address TEXT NOT NULL,
+    tonnes NUMERIC(20, 2) NOT NULL CHECK (tonnes > 0),
+    amount NUMERIC(78, 0) NOT NULL,
+    decimals SMALLINT NOT NULL,
+
+    tx_hash TEXT UNIQUE,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'CONFIRMED', 'FAILED')),
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_trigger
+        WHERE tgname = 'set_timestamp_token_issuances' AND tgrelid = 'token_issuances'::regclass
+    ) THEN
+        CREATE TRIGGER set_timestamp_token_issuances
+        BEFORE UPDATE ON token_issuances
+        FOR EACH ROW
+        EXECUTE PROCEDURE trigger_set_updated_at();
+    END IF;
+END
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_token_issuances_project ON token_issuances(project_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_token_issuances_project;
+DROP TABLE IF EXISTS token_issuances;
@@ -0,0 +1,17 @@
+-- Write your migrate up statements here
+
+-- Listings are created off-chain until a marketplace contract assigns them an
+-- on-chain id, so the id can no longer be required up front.
+ALTER TABLE listings ALTER COLUMN listing_id_on_chain DROP NOT NULL;
+ALTER TABLE listings ALTER COLUMN project_id SET NOT NULL;
+ALTER TABLE listings ALTER COLUMN active SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_listings_seller ON listings(seller_address);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_listings_seller;
+
+ALTER TABLE listings ALTER COLUMN active DROP NOT NULL;
+ALTER TABLE listings ALTER COLUMN project_id DROP NOT NULL;
+ALTER TABLE listings ALTER COLUMN listing_id_on_chain SET NOT NULL;
@@ -0,0 +1,69 @@
+-- Write your migrate up statements here
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_type t
+        JOIN pg_namespace n ON n.oid = t.typnamespace
+        WHERE t.typname = 'order_status' AND n.nspname = 'public'
+    ) THEN
+        CREATE TYPE order_status AS ENUM ('RESERVED', 'PAID', 'SETTLED', 'CANCELLED', 'EXPIRED');
+    END IF;
+END
+$$;
+
+-- A buyer's purchase against a listing. Placing an order moves its quantity
+-- out of listings.quantity_available; cancelling or expiring puts it back.
+CREATE TABLE IF NOT EXISTS orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    listing_id UUID NOT NULL REFERENCES listings(id),
+    project_id UUID NOT NULL REFERENCES projects(id),
+    buyer_id TEXT NOT NULL REFERENCES users(clerk_id),
+
+    buyer_address TEXT NOT NULL,
+    seller_address TEXT NOT NULL,
+
+    quantity NUMERIC(36, 18) NOT NULL CHECK (quantity > 0),
+    price_per_token NUMERIC(36, 18) NOT NULL,
+    total_price NUMERIC(36, 18) NOT NULL,
+
+    status order_status NOT NULL DEFAULT 'RESERVED',
+    expires_at TIMESTAMP NOT NULL,
+
+    payment_reference TEXT,
+    settlement_tx_hash TEXT UNIQUE,
+
+    paid_at TIMESTAMP,
+    settled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_trigger
+        WHERE tgname = 'set_timestamp_orders' AND tgrelid = 'orders'::regclass
+    ) THEN
+        CREATE TRIGGER set_timestamp_orders
+        BEFORE UPDATE ON orders
+        FOR EACH ROW
+        EXECUTE PROCEDURE trigger_set_updated_at();
+    END IF;
+END
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_orders_buyer ON orders(buyer_id);
+CREATE INDEX IF NOT EXISTS idx_orders_listing ON orders(listing_id);
+CREATE INDEX IF NOT EXISTS idx_orders_reserved_expiry ON orders(expires_at) WHERE status = 'RESERVED';
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_orders_reserved_expiry;
+DROP INDEX IF EXISTS idx_orders_listing;
+DROP INDEX IF EXISTS idx_orders_buyer;
+
+DROP TABLE IF EXISTS orders;
+
+DROP TYPE IF EXISTS order_status;
@@ -0,0 +1,34 @@
+-- Write your migrate up statements here
+
+-- A certificate is the off-chain record of a retirement: tx_hash is the burn
+-- or lock transaction, wallet_address the holder it retired from, and pdf_url
+-- is filled in once the certificate has been rendered and uploaded.
+ALTER TABLE certificates
+    ADD COLUMN IF NOT EXISTS wallet_address TEXT,
+    ADD COLUMN IF NOT EXISTS beneficiary_name TEXT,
+    ADD COLUMN IF NOT EXISTS block_number BIGINT,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_trigger
+        WHERE tgname = 'set_timestamp_certificates' AND tgrelid = 'certificates'::regclass
+    ) THEN
+        CREATE TRIGGER set_timestamp_certificates
+        BEFORE UPDATE ON certificates
+        FOR EACH ROW
+        EXECUTE PROCEDURE trigger_set_updated_at();
+    END IF;
+END
+$$;
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS set_timestamp_certificates ON certificates;
+
+ALTER TABLE certificates
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS block_number,
+    DROP COLUMN IF EXISTS beneficiary_name,
+    DROP COLUMN IF EXISTS wallet_address;
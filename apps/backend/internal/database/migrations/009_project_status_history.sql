@@ -0,0 +1,21 @@
+-- Write your migrate up statements here
+
+-- Every change of projects.status is appended here. actor_id is the user who
+-- made the change, or NULL when the system did (e.g. a deployment seen on
+-- chain). It is not a foreign key so the trail outlives deleted users.
+CREATE TABLE IF NOT EXISTS project_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    actor_id TEXT,
+    from_status project_status NOT NULL,
+    to_status project_status NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_status_history_project
+    ON project_status_history (project_id, created_at);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS project_status_history;
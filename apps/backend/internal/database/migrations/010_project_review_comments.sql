@@ -0,0 +1,44 @@
+-- Write your migrate up statements here
+
+-- Rejections carry a reason code next to the free-text reason.
+ALTER TABLE project_status_history
+    ADD COLUMN IF NOT EXISTS reason_code TEXT;
+
+-- Review feedback exchanged between admins and the project's supplier while
+-- it is PENDING or REJECTED. A comment with a parent_id is a reply; field
+-- optionally anchors the comment to a project attribute such as "area".
+CREATE TABLE IF NOT EXISTS project_review_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES project_review_comments(id) ON DELETE CASCADE,
+    author_id TEXT NOT NULL,
+    author_role user_role NOT NULL,
+    field TEXT,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_review_comments_project
+    ON project_review_comments (project_id, created_at);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_trigger
+        WHERE tgname = 'set_timestamp_project_review_comments' AND tgrelid = 'project_review_comments'::regclass
+    ) THEN
+        CREATE TRIGGER set_timestamp_project_review_comments
+        BEFORE UPDATE ON project_review_comments
+        FOR EACH ROW
+        EXECUTE PROCEDURE trigger_set_updated_at();
+    END IF;
+END
+$$;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS project_review_comments;
+
+ALTER TABLE project_status_history
+    DROP COLUMN IF EXISTS reason_code;
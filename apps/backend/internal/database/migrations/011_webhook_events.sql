@@ -0,0 +1,16 @@
+-- Write your migrate up statements here
+
+-- Deliveries from webhook providers that have been handled, keyed on the
+-- provider's message ID. Providers retry and can be replayed, so a delivery
+-- whose ID is already here is acknowledged without being processed again.
+CREATE TABLE IF NOT EXISTS webhook_events (
+    source TEXT NOT NULL,
+    id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source, id)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS webhook_events;
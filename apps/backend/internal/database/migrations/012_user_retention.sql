@@ -0,0 +1,27 @@
+-- Write your migrate up statements here
+
+-- Deleted accounts are purged after a retention period, but the records they
+-- took part in are kept: projects, orders, certificates and token issuances
+-- keep the Clerk ID of a purged user, so they no longer reference users.
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_supplier_id_fkey;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_buyer_id_fkey;
+ALTER TABLE certificates DROP CONSTRAINT IF EXISTS certificates_owner_id_fkey;
+ALTER TABLE token_issuances DROP CONSTRAINT IF EXISTS token_issuances_issued_by_fkey;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at
+    ON users (deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+-- NOT VALID: rows of already purged users would fail the check
+ALTER TABLE token_issuances ADD CONSTRAINT token_issuances_issued_by_fkey
+    FOREIGN KEY (issued_by) REFERENCES users(clerk_id) NOT VALID;
+ALTER TABLE certificates ADD CONSTRAINT certificates_owner_id_fkey
+    FOREIGN KEY (owner_id) REFERENCES users(clerk_id) NOT VALID;
+ALTER TABLE orders ADD CONSTRAINT orders_buyer_id_fkey
+    FOREIGN KEY (buyer_id) REFERENCES users(clerk_id) NOT VALID;
+ALTER TABLE projects ADD CONSTRAINT projects_supplier_id_fkey
+    FOREIGN KEY (supplier_id) REFERENCES users(clerk_id) NOT VALID;
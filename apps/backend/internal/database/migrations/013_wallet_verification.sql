@@ -0,0 +1,9 @@
+-- Write your migrate up statements here
+
+-- Set when the user proved ownership of wallet_address by signing a
+-- challenge; cleared whenever the address changes.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS wallet_verified_at TIMESTAMPTZ;
+
+---- create above / drop below ----
+
+ALTER TABLE users DROP COLUMN IF EXISTS wallet_verified_at;
@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+
+-- Full-text search over the public project listing; titles rank above
+-- descriptions.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_projects_search_vector
+    ON projects USING GIN (search_vector);
+
+-- The browse listing filters on status and pages by newest first
+CREATE INDEX IF NOT EXISTS idx_projects_status_created_at
+    ON projects (status, created_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_projects_status_created_at;
+DROP INDEX IF EXISTS idx_projects_search_vector;
+ALTER TABLE projects DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+
+-- Cursor-paginated feeds walk (created_at, id) newest first
+DROP INDEX IF EXISTS idx_projects_status_created_at;
+
+CREATE INDEX IF NOT EXISTS idx_projects_status_created_at_id
+    ON projects (status, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_projects_supplier_created_at_id
+    ON projects (supplier_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_listings_open_created_at_id
+    ON listings (created_at DESC, id DESC)
+    WHERE active AND quantity_available > 0;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_listings_open_created_at_id;
+DROP INDEX IF EXISTS idx_projects_supplier_created_at_id;
+DROP INDEX IF EXISTS idx_projects_status_created_at_id;
+
+CREATE INDEX IF NOT EXISTS idx_projects_status_created_at
+    ON projects (status, created_at DESC);
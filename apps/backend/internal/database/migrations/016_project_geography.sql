@@ -0,0 +1,26 @@
+-- Write your migrate up statements here
+
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+-- location follows location_lat/location_lng, which stay the editable source
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS location geography(Point, 4326)
+    GENERATED ALWAYS AS (
+        CASE
+            WHEN location_lat IS NULL OR location_lng IS NULL THEN NULL
+            ELSE ST_SetSRID(ST_MakePoint(location_lng::float8, location_lat::float8), 4326)::geography
+        END
+    ) STORED;
+
+-- Radius searches use the geography; bounding boxes are planar, so they use
+-- its geometry.
+CREATE INDEX IF NOT EXISTS idx_projects_location
+    ON projects USING GIST (location);
+
+CREATE INDEX IF NOT EXISTS idx_projects_location_geometry
+    ON projects USING GIST ((location::geometry));
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_projects_location_geometry;
+DROP INDEX IF EXISTS idx_projects_location;
+ALTER TABLE projects DROP COLUMN IF EXISTS location;
@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+
+-- The boundary a supplier uploads, and the area the backend measured for it.
+-- location stays the marker point; the boundary is drawn and overlap-checked.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS boundary geography(Polygon, 4326);
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS boundary_area_ha DOUBLE PRECISION;
+
+-- Flags a declared area more than 10% away from the measured one, so
+-- reviewers see it whichever side is edited last.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS area_mismatch BOOLEAN NOT NULL
+    GENERATED ALWAYS AS (
+        COALESCE(abs(area::float8 - boundary_area_ha) > 0.1 * boundary_area_ha, FALSE)
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_projects_boundary
+    ON projects USING GIST (boundary);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_projects_boundary;
+ALTER TABLE projects DROP COLUMN IF EXISTS area_mismatch;
+ALTER TABLE projects DROP COLUMN IF EXISTS boundary_area_ha;
+ALTER TABLE projects DROP COLUMN IF EXISTS boundary;
@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_projects_title_trgm
+    ON projects USING GIN (title gin_trgm_ops);
+
+-- Possible double counting found when a project was submitted: reviewed
+-- projects whose land or title it shares. Replaced on every submission.
+CREATE TABLE IF NOT EXISTS project_review_warnings (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    conflicting_project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, conflicting_project_id, kind)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS project_review_warnings;
+DROP INDEX IF EXISTS idx_projects_title_trgm;
@@ -0,0 +1,73 @@
+-- Write your migrate up statements here
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1
+        FROM pg_type t
+        JOIN pg_namespace n ON n.oid = t.typnamespace
+        WHERE t.typname = 'project_document_type' AND n.nspname = 'public'
+    ) THEN
+        CREATE TYPE project_document_type AS ENUM (
+            'IMAGE', 'AUDIT_REPORT', 'LAND_TITLE', 'METHODOLOGY', 'MONITORING_REPORT'
+        );
+    END IF;
+END
+$$;
+
+-- Files attached to a project. object_key is the bucket key, kept so the
+-- object can be deleted with its row. Documents of a type are shown in
+-- position order; the first IMAGE and AUDIT_REPORT are mirrored into
+-- projects.image_url and projects.audit_report_url.
+CREATE TABLE IF NOT EXISTS project_documents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    type project_document_type NOT NULL,
+    object_key TEXT NOT NULL,
+    url TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT,
+    size_bytes BIGINT,
+    uploaded_by TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_documents_project
+    ON project_documents (project_id, type, position);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_trigger
+        WHERE tgname = 'set_timestamp_project_documents' AND tgrelid = 'project_documents'::regclass
+    ) THEN
+        CREATE TRIGGER set_timestamp_project_documents
+        BEFORE UPDATE ON project_documents
+        FOR EACH ROW
+        EXECUTE PROCEDURE trigger_set_updated_at();
+    END IF;
+END
+$$;
+
+-- Existing files become the first document of their type. Uploads are keyed
+-- "<folder>/<file>", which is the tail of their public URL.
+INSERT INTO project_documents (project_id, type, object_key, url, file_name, uploaded_by, created_at)
+SELECT id, 'IMAGE', substring(image_url FROM '[^/]+/[^/]+$'), image_url,
+    substring(image_url FROM '[^/]+$'), COALESCE(supplier_id, ''), created_at
+FROM projects
+WHERE substring(image_url FROM '[^/]+/[^/]+$') IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM project_documents d WHERE d.project_id = projects.id AND d.type = 'IMAGE');
+
+INSERT INTO project_documents (project_id, type, object_key, url, file_name, uploaded_by, created_at)
+SELECT id, 'AUDIT_REPORT', substring(audit_report_url FROM '[^/]+/[^/]+$'), audit_report_url,
+    substring(audit_report_url FROM '[^/]+$'), COALESCE(supplier_id, ''), created_at
+FROM projects
+WHERE substring(audit_report_url FROM '[^/]+/[^/]+$') IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM project_documents d WHERE d.project_id = projects.id AND d.type = 'AUDIT_REPORT');
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS project_documents;
+DROP TYPE IF EXISTS project_document_type;
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model/token"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	"github.com/inventedsarawak/ledgera/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type AssetHandler struct {
+	Handler
+	assetService *service.AssetService
+}
+
+func NewAssetHandler(s *server.Server, assetService *service.AssetService) *AssetHandler {
+	return &AssetHandler{
+		Handler:      NewHandler(s),
+		assetService: assetService,
+	}
+}
+
+func (h *AssetHandler) Deploy(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, req *validation.DeployProjectRequest) error {
+			adminID := middleware.GetUserID(c)
+			return h.assetService.RequestDeploy(c, req.ID, adminID, req.Symbol)
+		},
+		http.StatusAccepted,
+		&validation.DeployProjectRequest{},
+	)(c)
+}
+
+func (h *AssetHandler) Mint(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.MintProjectRequest) (*token.Issuance, error) {
+			adminID := middleware.GetUserID(c)
+			return h.assetService.RequestMint(c, req.ID, adminID, req.Tonnes)
+		},
+		http.StatusAccepted,
+		&validation.MintProjectRequest{},
+	)(c)
+}
+
+func (h *AssetHandler) ListIssuances(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListIssuancesRequest) ([]token.Issuance, error) {
+			userID := middleware.GetUserID(c)
+			return h.assetService.ListIssuances(c, req.ID, userID)
+		},
+		http.StatusOK,
+		&validation.ListIssuancesRequest{},
+	)(c)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/inventedsarawak/ledgera/internal/middleware"
@@ -33,3 +34,20 @@ func (h *AuthHandler) SyncUser(c echo.Context) error {
 		&user.SyncUserPayload{},
 	)(c)
 }
+
+// maxWebhookBody caps the size of a webhook delivery read into memory.
+const maxWebhookBody = 1 << 20
+
+// ClerkWebhook receives Clerk's user and membership events. The raw body is
+// passed on untouched because the Svix signature covers its exact bytes.
+func (h *AuthHandler) ClerkWebhook(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBody))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook body")
+	}
+
+	if err := h.authService.ReceiveClerkWebhook(c, body); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/certificate"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	"github.com/inventedsarawak/ledgera/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type CertificateHandler struct {
+	Handler
+	certificateService *service.CertificateService
+}
+
+func NewCertificateHandler(s *server.Server, certificateService *service.CertificateService) *CertificateHandler {
+	return &CertificateHandler{
+		Handler:            NewHandler(s),
+		certificateService: certificateService,
+	}
+}
+
+func (h *CertificateHandler) Retire(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.RetireRequest) (*certificate.Certificate, error) {
+			userID := middleware.GetUserID(c)
+			payload := certificate.RetirePayload{
+				TxHash:           req.TxHash,
+				BeneficiaryName:  req.BeneficiaryName,
+				RetirementReason: req.RetirementReason,
+			}
+			return h.certificateService.Retire(c, req.ProjectID, payload, userID)
+		},
+		http.StatusCreated,
+		&validation.RetireRequest{},
+	)(c)
+}
+
+func (h *CertificateHandler) ListMine(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListCertificatesRequest) (*model.PaginatedResponse[certificate.Certificate], error) {
+			userID := middleware.GetUserID(c)
+			return h.certificateService.ListMine(c, certificate.GetCertificatesQuery{
+				Page:  req.Page,
+				Limit: req.Limit,
+			}, userID)
+		},
+		http.StatusOK,
+		&validation.ListCertificatesRequest{},
+	)(c)
+}
+
+func (h *CertificateHandler) GetByID(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.GetCertificateRequest) (*certificate.Certificate, error) {
+			userID := middleware.GetUserID(c)
+			return h.certificateService.GetByID(c, req.ID, userID)
+		},
+		http.StatusOK,
+		&validation.GetCertificateRequest{},
+	)(c)
+}
+
+func (h *CertificateHandler) Verify(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VerifyCertificateRequest) (*certificate.Verification, error) {
+			return h.certificateService.Verify(c, req.ID)
+		},
+		http.StatusOK,
+		&validation.VerifyCertificateRequest{},
+	)(c)
+}
+
+func (h *CertificateHandler) VerifyByTxHash(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.VerifyCertificateByTxRequest) (*certificate.Verification, error) {
+			return h.certificateService.VerifyByTxHash(c, req.TxHash)
+		},
+		http.StatusOK,
+		&validation.VerifyCertificateByTxRequest{},
+	)(c)
+}
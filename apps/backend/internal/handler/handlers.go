@@ -6,17 +6,27 @@ import (
 )
 
 type Handlers struct {
-	Health  *HealthHandler
-	OpenAPI *OpenAPIHandler
-	Auth    *AuthHandler
-	Project *ProjectHandler
+	Health      *HealthHandler
+	OpenAPI     *OpenAPIHandler
+	Auth        *AuthHandler
+	Project     *ProjectHandler
+	Asset       *AssetHandler
+	Listing     *ListingHandler
+	Order       *OrderHandler
+	Certificate *CertificateHandler
+	User        *UserHandler
 }
 
 func NewHandlers(s *server.Server, services *service.Services) *Handlers {
 	return &Handlers{
-		Health:  NewHealthHandler(s),
-		OpenAPI: NewOpenAPIHandler(s),
-		Auth:    NewAuthHandler(s, services.Auth),
-		Project: NewProjectHandler(s, services.Project),
+		Health:      NewHealthHandler(s),
+		OpenAPI:     NewOpenAPIHandler(s),
+		Auth:        NewAuthHandler(s, services.Auth),
+		Project:     NewProjectHandler(s, services.Project),
+		Asset:       NewAssetHandler(s, services.Asset),
+		Listing:     NewListingHandler(s, services.Listing),
+		Order:       NewOrderHandler(s, services.Order),
+		Certificate: NewCertificateHandler(s, services.Certificate),
+		User:        NewUserHandler(s, services.User),
 	}
 }
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/listing"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	"github.com/inventedsarawak/ledgera/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type ListingHandler struct {
+	Handler
+	listingService *service.ListingService
+}
+
+func NewListingHandler(s *server.Server, listingService *service.ListingService) *ListingHandler {
+	return &ListingHandler{
+		Handler:        NewHandler(s),
+		listingService: listingService,
+	}
+}
+
+func (h *ListingHandler) Create(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateListingRequest) (*listing.Listing, error) {
+			userID := middleware.GetUserID(c)
+			payload := listing.CreateListingPayload{
+				ProjectID:         uuid.MustParse(req.ProjectID),
+				PricePerToken:     req.PricePerToken,
+				QuantityAvailable: req.QuantityAvailable,
+			}
+			return h.listingService.Create(c, payload, userID)
+		},
+		http.StatusCreated,
+		&validation.CreateListingRequest{},
+	)(c)
+}
+
+func (h *ListingHandler) List(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListListingsRequest) (*model.PaginatedResponse[listing.Listing], error) {
+			return h.listingService.Browse(c, listing.GetListingsQuery{
+				ProjectID: req.ProjectUUID(),
+				Page:      req.Page,
+				Limit:     req.Limit,
+				Cursor:    req.Cursor,
+			})
+		},
+		http.StatusOK,
+		&validation.ListListingsRequest{},
+	)(c)
+}
+
+func (h *ListingHandler) GetByID(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.GetListingRequest) (*listing.Listing, error) {
+			return h.listingService.GetByID(c, req.ID)
+		},
+		http.StatusOK,
+		&validation.GetListingRequest{},
+	)(c)
+}
+
+func (h *ListingHandler) Update(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.UpdateListingRequest) (*listing.Listing, error) {
+			userID := middleware.GetUserID(c)
+			payload := listing.UpdateListingPayload{
+				PricePerToken:     req.PricePerToken,
+				QuantityAvailable: req.QuantityAvailable,
+			}
+			return h.listingService.Update(c, req.ID, payload, userID)
+		},
+		http.StatusOK,
+		&validation.UpdateListingRequest{},
+	)(c)
+}
+
+func (h *ListingHandler) Deactivate(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, req *validation.DeactivateListingRequest) error {
+			userID := middleware.GetUserID(c)
+			return h.listingService.Deactivate(c, req.ID, userID)
+		},
+		http.StatusNoContent,
+		&validation.DeactivateListingRequest{},
+	)(c)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/order"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	"github.com/inventedsarawak/ledgera/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type OrderHandler struct {
+	Handler
+	orderService *service.OrderService
+}
+
+func NewOrderHandler(s *server.Server, orderService *service.OrderService) *OrderHandler {
+	return &OrderHandler{
+		Handler:      NewHandler(s),
+		orderService: orderService,
+	}
+}
+
+func (h *OrderHandler) Place(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.PlaceOrderRequest) (*order.Order, error) {
+			userID := middleware.GetUserID(c)
+			payload := order.PlaceOrderPayload{
+				ListingID: uuid.MustParse(req.ListingID),
+				Quantity:  req.Quantity,
+			}
+			return h.orderService.Place(c, payload, userID)
+		},
+		http.StatusCreated,
+		&validation.PlaceOrderRequest{},
+	)(c)
+}
+
+func (h *OrderHandler) ListMine(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListOrdersRequest) (*model.PaginatedResponse[order.Order], error) {
+			userID := middleware.GetUserID(c)
+			return h.orderService.ListMine(c, order.GetOrdersQuery{
+				Status: req.StatusFilter(),
+				Page:   req.Page,
+				Limit:  req.Limit,
+			}, userID)
+		},
+		http.StatusOK,
+		&validation.ListOrdersRequest{},
+	)(c)
+}
+
+func (h *OrderHandler) GetByID(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.GetOrderRequest) (*order.Order, error) {
+			userID := middleware.GetUserID(c)
+			return h.orderService.GetByID(c, req.ID, userID)
+		},
+		http.StatusOK,
+		&validation.GetOrderRequest{},
+	)(c)
+}
+
+func (h *OrderHandler) Pay(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.PayOrderRequest) (*order.Order, error) {
+			userID := middleware.GetUserID(c)
+			payload := order.PayOrderPayload{PaymentReference: req.PaymentReference}
+			return h.orderService.MarkPaid(c, req.ID, payload, userID)
+		},
+		http.StatusOK,
+		&validation.PayOrderRequest{},
+	)(c)
+}
+
+func (h *OrderHandler) Settle(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.SettleOrderRequest) (*order.Order, error) {
+			userID := middleware.GetUserID(c)
+			payload := order.SettleOrderPayload{TxHash: req.TxHash}
+			return h.orderService.Settle(c, req.ID, payload, userID)
+		},
+		http.StatusOK,
+		&validation.SettleOrderRequest{},
+	)(c)
+}
+
+func (h *OrderHandler) Cancel(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CancelOrderRequest) (*order.Order, error) {
+			userID := middleware.GetUserID(c)
+			return h.orderService.Cancel(c, req.ID, userID)
+		},
+		http.StatusOK,
+		&validation.CancelOrderRequest{},
+	)(c)
+}
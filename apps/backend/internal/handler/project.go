@@ -5,7 +5,10 @@ import (
 	"mime/multipart"
 	"net/http"
 
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/lib/upload"
 	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
 	"github.com/inventedsarawak/ledgera/internal/model/project"
 	"github.com/inventedsarawak/ledgera/internal/server"
 	"github.com/inventedsarawak/ledgera/internal/service"
@@ -13,6 +16,9 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// geoJSONContentType is the media type of GeoJSON responses (RFC 7946).
+const geoJSONContentType = "application/geo+json"
+
 type ProjectHandler struct {
 	Handler
 	projectService *service.ProjectService
@@ -66,6 +72,15 @@ func (h *ProjectHandler) ListMine(c echo.Context) error {
 		h.Handler,
 		func(c echo.Context, req *validation.ListProjectsRequest) ([]project.Project, error) {
 			userID := middleware.GetUserID(c)
+			if req.Cursor != nil {
+				items, total, next, err := h.projectService.ListBySupplierAfter(c, userID, *req.Cursor, req.Limit)
+				if err != nil {
+					return nil, err
+				}
+				setCursorHeaders(c, total, req.Limit, next)
+				return items, nil
+			}
+
 			items, total, err := h.projectService.ListBySupplier(c, userID, req.Page, req.Limit)
 			if err != nil {
 				return nil, err
@@ -80,11 +95,74 @@ func (h *ProjectHandler) ListMine(c echo.Context) error {
 	)(c)
 }
 
+func (h *ProjectHandler) Browse(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, q *project.GetProjectsQuery) ([]project.Project, error) {
+			items, total, next, err := h.projectService.Browse(c, *q)
+			if err != nil {
+				return nil, err
+			}
+			if q.Cursor != nil {
+				setCursorHeaders(c, total, *q.Limit, next)
+				return items, nil
+			}
+			c.Response().Header().Set("X-Total-Count", fmt.Sprintf("%d", total))
+			c.Response().Header().Set("X-Page", fmt.Sprintf("%d", *q.Page))
+			c.Response().Header().Set("X-Limit", fmt.Sprintf("%d", *q.Limit))
+			return items, nil
+		},
+		http.StatusOK,
+		&project.GetProjectsQuery{},
+	)(c)
+}
+
+func (h *ProjectHandler) InBBox(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, q *project.GetProjectsInBBoxQuery) (*project.FeatureCollection, error) {
+			fc, err := h.projectService.InBBox(c, *q)
+			if err != nil {
+				return nil, err
+			}
+			c.Response().Header().Set(echo.HeaderContentType, geoJSONContentType)
+			return fc, nil
+		},
+		http.StatusOK,
+		&project.GetProjectsInBBoxQuery{},
+	)(c)
+}
+
+func (h *ProjectHandler) Near(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, q *project.GetProjectsNearQuery) (*project.FeatureCollection, error) {
+			fc, err := h.projectService.Near(c, *q)
+			if err != nil {
+				return nil, err
+			}
+			c.Response().Header().Set(echo.HeaderContentType, geoJSONContentType)
+			return fc, nil
+		},
+		http.StatusOK,
+		&project.GetProjectsNearQuery{},
+	)(c)
+}
+
 func (h *ProjectHandler) ListPendingReview(c echo.Context) error {
 	return Handle(
 		h.Handler,
 		func(c echo.Context, req *validation.ListProjectsRequest) ([]project.ProjectWithSupplier, error) {
 			adminID := middleware.GetUserID(c)
+			if req.Cursor != nil {
+				items, total, next, err := h.projectService.ListPendingForReviewAfter(c, adminID, *req.Cursor, req.Limit)
+				if err != nil {
+					return nil, err
+				}
+				setCursorHeaders(c, total, req.Limit, next)
+				return items, nil
+			}
+
 			items, total, err := h.projectService.ListPendingForReview(c, adminID, req.Page, req.Limit)
 			if err != nil {
 				return nil, err
@@ -102,8 +180,34 @@ func (h *ProjectHandler) ListPendingReview(c echo.Context) error {
 func (h *ProjectHandler) GetByID(c echo.Context) error {
 	return Handle(
 		h.Handler,
-		func(c echo.Context, req *validation.GetProjectRequest) (*project.Project, error) {
-			return h.projectService.GetByID(c, req.ID)
+		func(c echo.Context, req *validation.GetProjectRequest) (*project.ProjectDetail, error) {
+			userID := middleware.GetUserID(c)
+			return h.projectService.GetByID(c, req.ID, userID)
+		},
+		http.StatusOK,
+		&validation.GetProjectRequest{},
+	)(c)
+}
+
+func (h *ProjectHandler) FactSheet(c echo.Context) error {
+	return HandleFile(
+		h.Handler,
+		func(c echo.Context, req *validation.GetProjectRequest) ([]byte, error) {
+			return h.projectService.FactSheet(c, req.ID)
+		},
+		http.StatusOK,
+		&validation.GetProjectRequest{},
+		"fact-sheet.pdf",
+		"application/pdf",
+	)(c)
+}
+
+func (h *ProjectHandler) History(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.GetProjectRequest) ([]project.StatusChange, error) {
+			userID := middleware.GetUserID(c)
+			return h.projectService.History(c, req.ID, userID)
 		},
 		http.StatusOK,
 		&validation.GetProjectRequest{},
@@ -139,11 +243,6 @@ func (h *ProjectHandler) Update(c echo.Context) error {
 				auditHeader = doc
 			}
 
-			var statusPtr *project.ProjectStatus
-			if req.Status != nil {
-				s := project.ProjectStatus(*req.Status)
-				statusPtr = &s
-			}
 			payload := project.UpdateProjectPayload{
 				ID:              req.ID,
 				Title:           req.Title,
@@ -152,8 +251,6 @@ func (h *ProjectHandler) Update(c echo.Context) error {
 				LocationLng:     req.LocationLng,
 				Area:            req.Area,
 				CarbonAmount:    req.CarbonAmount,
-				ContractAddress: req.ContractAddress,
-				Status:          statusPtr,
 			}
 
 			return h.projectService.Update(c, req.ID.String(), payload, userID, imageHeader, auditHeader)
@@ -163,6 +260,134 @@ func (h *ProjectHandler) Update(c echo.Context) error {
 	)(c)
 }
 
+func (h *ProjectHandler) SetBoundary(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.SetProjectBoundaryRequest) (*project.Project, error) {
+			userID := middleware.GetUserID(c)
+
+			file, err := c.FormFile("boundary")
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Boundary file is required")
+			}
+
+			return h.projectService.SetBoundary(c, req.ID, userID, file)
+		},
+		http.StatusOK,
+		&validation.SetProjectBoundaryRequest{},
+	)(c)
+}
+
+func (h *ProjectHandler) Boundary(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.GetProjectRequest) (*project.BoundaryFeature, error) {
+			feature, err := h.projectService.Boundary(c, req.ID)
+			if err != nil {
+				return nil, err
+			}
+			c.Response().Header().Set(echo.HeaderContentType, geoJSONContentType)
+			return feature, nil
+		},
+		http.StatusOK,
+		&validation.GetProjectRequest{},
+	)(c)
+}
+
+func (h *ProjectHandler) ListDocuments(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ListProjectDocumentsRequest) ([]project.Document, error) {
+			return h.projectService.ListDocuments(c, req.ID)
+		},
+		http.StatusOK,
+		&validation.ListProjectDocumentsRequest{},
+	)(c)
+}
+
+func (h *ProjectHandler) AddDocument(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.AddProjectDocumentRequest) (*project.Document, error) {
+			userID := middleware.GetUserID(c)
+
+			file, err := c.FormFile("file")
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "File is required")
+			}
+
+			return h.projectService.AddDocument(c, req.ID, userID, project.DocumentType(req.Type), file)
+		},
+		http.StatusCreated,
+		&validation.AddProjectDocumentRequest{},
+	)(c)
+}
+
+func (h *ProjectHandler) PresignDocument(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.PresignProjectDocumentRequest) (*upload.PresignedUpload, error) {
+			userID := middleware.GetUserID(c)
+			return h.projectService.PresignDocument(c, req.ID, userID, project.PresignDocumentPayload{
+				Type:        project.DocumentType(req.Type),
+				FileName:    req.FileName,
+				ContentType: req.ContentType,
+				Size:        req.Size,
+			})
+		},
+		http.StatusCreated,
+		&validation.PresignProjectDocumentRequest{},
+	)(c)
+}
+
+func (h *ProjectHandler) ConfirmDocument(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ConfirmProjectDocumentRequest) (*project.Document, error) {
+			userID := middleware.GetUserID(c)
+			return h.projectService.ConfirmDocument(c, req.ID, userID, project.ConfirmDocumentPayload{
+				Type:     project.DocumentType(req.Type),
+				Key:      req.Key,
+				FileName: req.FileName,
+			})
+		},
+		http.StatusCreated,
+		&validation.ConfirmProjectDocumentRequest{},
+	)(c)
+}
+
+func (h *ProjectHandler) DeleteDocument(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, req *validation.DeleteProjectDocumentRequest) error {
+			userID := middleware.GetUserID(c)
+			return h.projectService.DeleteDocument(c, req.ID, req.DocumentID, userID)
+		},
+		http.StatusNoContent,
+		&validation.DeleteProjectDocumentRequest{},
+	)(c)
+}
+
+func (h *ProjectHandler) ReorderDocuments(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.ReorderProjectDocumentsRequest) ([]project.Document, error) {
+			userID := middleware.GetUserID(c)
+			payload := project.ReorderDocumentsPayload{Type: project.DocumentType(req.Type)}
+			for _, raw := range req.IDs {
+				id, err := uuid.Parse(raw)
+				if err != nil {
+					return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid document id")
+				}
+				payload.IDs = append(payload.IDs, id)
+			}
+			return h.projectService.ReorderDocuments(c, req.ID, userID, payload)
+		},
+		http.StatusOK,
+		&validation.ReorderProjectDocumentsRequest{},
+	)(c)
+}
+
 func (h *ProjectHandler) SendForApproval(c echo.Context) error {
 	return HandleNoContent(
 		h.Handler,
@@ -190,11 +415,64 @@ func (h *ProjectHandler) Approve(c echo.Context) error {
 func (h *ProjectHandler) Reject(c echo.Context) error {
 	return Handle(
 		h.Handler,
-		func(c echo.Context, req *validation.ReviewProjectRequest) (*project.Project, error) {
+		func(c echo.Context, req *validation.RejectProjectRequest) (*project.Project, error) {
 			adminID := middleware.GetUserID(c)
-			return h.projectService.Reject(c, req.ID, adminID)
+			payload := project.RejectProjectPayload{
+				Code:    project.RejectionReason(req.Code),
+				Message: req.Message,
+				Field:   req.Field,
+			}
+			return h.projectService.Reject(c, req.ID, adminID, payload)
 		},
 		http.StatusOK,
-		&validation.ReviewProjectRequest{},
+		&validation.RejectProjectRequest{},
 	)(c)
-}
\ No newline at end of file
+}
+
+func (h *ProjectHandler) ListComments(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.GetProjectRequest) ([]project.ReviewComment, error) {
+			userID := middleware.GetUserID(c)
+			return h.projectService.ListComments(c, req.ID, userID)
+		},
+		http.StatusOK,
+		&validation.GetProjectRequest{},
+	)(c)
+}
+
+func (h *ProjectHandler) AddComment(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.CreateReviewCommentRequest) (*project.ReviewComment, error) {
+			userID := middleware.GetUserID(c)
+			payload := project.CreateReviewCommentPayload{
+				Body:  req.Body,
+				Field: req.Field,
+			}
+			if req.ParentID != nil {
+				parentID, err := uuid.Parse(*req.ParentID)
+				if err != nil {
+					return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid parent comment id")
+				}
+				payload.ParentID = &parentID
+			}
+			return h.projectService.AddComment(c, req.ID, userID, payload)
+		},
+		http.StatusCreated,
+		&validation.CreateReviewCommentRequest{},
+	)(c)
+}
+
+// setCursorHeaders describes a cursor-paginated page. X-Total-Count is only
+// set when total is known (not negative), and X-Next-Cursor while there are
+// more items.
+func setCursorHeaders(c echo.Context, total int64, limit int, next *model.Cursor) {
+	if total >= 0 {
+		c.Response().Header().Set("X-Total-Count", fmt.Sprintf("%d", total))
+	}
+	c.Response().Header().Set("X-Limit", fmt.Sprintf("%d", limit))
+	if next != nil {
+		c.Response().Header().Set("X-Next-Cursor", next.Encode())
+	}
+}
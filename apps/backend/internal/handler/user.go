@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	"github.com/inventedsarawak/ledgera/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type UserHandler struct {
+	Handler
+	userService *service.UserService
+}
+
+func NewUserHandler(s *server.Server, userService *service.UserService) *UserHandler {
+	return &UserHandler{
+		Handler:     NewHandler(s),
+		userService: userService,
+	}
+}
+
+func (h *UserHandler) GetMe(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *validation.GetMeRequest) (*user.User, error) {
+			userID := middleware.GetUserID(c)
+			return h.userService.GetMe(c, userID)
+		},
+		http.StatusOK,
+		&validation.GetMeRequest{},
+	)(c)
+}
+
+func (h *UserHandler) UpdateMe(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *user.UpdateMePayload) (*user.User, error) {
+			userID := middleware.GetUserID(c)
+			return h.userService.UpdateMe(c, userID, *payload)
+		},
+		http.StatusOK,
+		&user.UpdateMePayload{},
+	)(c)
+}
+
+func (h *UserHandler) WalletChallenge(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *user.WalletChallengePayload) (*user.WalletChallenge, error) {
+			userID := middleware.GetUserID(c)
+			return h.userService.WalletChallenge(c, userID, *payload)
+		},
+		http.StatusCreated,
+		&user.WalletChallengePayload{},
+	)(c)
+}
+
+func (h *UserHandler) VerifyWallet(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *user.VerifyWalletPayload) (*user.User, error) {
+			userID := middleware.GetUserID(c)
+			return h.userService.VerifyWallet(c, userID, *payload)
+		},
+		http.StatusOK,
+		&user.VerifyWalletPayload{},
+	)(c)
+}
+
+func (h *UserHandler) List(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, q *user.GetUsersQuery) (*model.PaginatedResponse[user.User], error) {
+			adminID := middleware.GetUserID(c)
+			return h.userService.List(c, adminID, *q)
+		},
+		http.StatusOK,
+		&user.GetUsersQuery{},
+	)(c)
+}
+
+func (h *UserHandler) Update(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, payload *user.UpdateUserPayload) (*user.User, error) {
+			adminID := middleware.GetUserID(c)
+			return h.userService.Update(c, adminID, *payload)
+		},
+		http.StatusOK,
+		&user.UpdateUserPayload{},
+	)(c)
+}
+
+func (h *UserHandler) DeleteMe(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, req *validation.DeleteMeRequest) error {
+			userID := middleware.GetUserID(c)
+			return h.userService.DeleteMe(c, userID)
+		},
+		http.StatusNoContent,
+		&validation.DeleteMeRequest{},
+	)(c)
+}
@@ -4,27 +4,78 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"net/mail"
+	"path/filepath"
 
 	"github.com/inventedsarawak/ledgera/internal/config"
 	"github.com/pkg/errors"
-	"github.com/resend/resend-go/v2"
 	"github.com/rs/zerolog"
 )
 
+const (
+	// DefaultTemplateDir is where email templates live relative to the
+	// working directory of the server.
+	DefaultTemplateDir = "templates/emails"
+
+	DefaultFromName    = "Ledgera"
+	DefaultFromAddress = "onboarding@resend.dev"
+)
+
+// Options configure a Client. Empty fields take the package defaults.
+type Options struct {
+	FromName    string
+	FromAddress string
+	ReplyTo     string
+	TemplateDir string
+}
+
 type Client struct {
-	client *resend.Client
-	logger *zerolog.Logger
+	sender      Sender
+	from        string
+	replyTo     string
+	templateDir string
+	logger      *zerolog.Logger
+}
+
+// NewClient builds a client on the transport and sender identity configured
+// in cfg.Integration.
+func NewClient(cfg *config.Config, logger *zerolog.Logger) (*Client, error) {
+	sender, err := NewSender(cfg.Integration)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientWithSender(sender, Options{
+		FromName:    cfg.Integration.EmailFromName,
+		FromAddress: cfg.Integration.EmailFromAddress,
+		ReplyTo:     cfg.Integration.EmailReplyTo,
+	}, logger), nil
 }
 
-func NewClient(cfg *config.Config, logger *zerolog.Logger) *Client {
+// NewClientWithSender renders templates and hands the result to sender, e.g.
+// a DryRunSender in tests.
+func NewClientWithSender(sender Sender, opts Options, logger *zerolog.Logger) *Client {
+	if opts.FromName == "" {
+		opts.FromName = DefaultFromName
+	}
+	if opts.FromAddress == "" {
+		opts.FromAddress = DefaultFromAddress
+	}
+	if opts.TemplateDir == "" {
+		opts.TemplateDir = DefaultTemplateDir
+	}
+
 	return &Client{
-		client: resend.NewClient(cfg.Integration.ResendAPIKey),
-		logger: logger,
+		sender:      sender,
+		from:        (&mail.Address{Name: opts.FromName, Address: opts.FromAddress}).String(),
+		replyTo:     opts.ReplyTo,
+		templateDir: opts.TemplateDir,
+		logger:      logger,
 	}
 }
 
 func (c *Client) SendEmail(to, subject string, templateName Template, data map[string]string) error {
-	tmplPath := fmt.Sprintf("%s/%s.html", "templates/emails", templateName)
+	tmplPath := filepath.Join(c.templateDir, string(templateName)+".html")
 
 	tmpl, err := template.ParseFiles(tmplPath)
 	if err != nil {
@@ -36,14 +87,13 @@ func (c *Client) SendEmail(to, subject string, templateName Template, data map[s
 		return errors.Wrapf(err, "failed to execute email template %s", templateName)
 	}
 
-	params := &resend.SendEmailRequest{
-		From:    fmt.Sprintf("%s <%s>", "Ledgera", "onboarding@resend.dev"),
-		To:      []string{to},
+	err = c.sender.Send(Message{
+		From:    c.from,
+		ReplyTo: c.replyTo,
+		To:      to,
 		Subject: subject,
-		Html:    body.String(),
-	}
-
-	_, err = c.client.Emails.Send(params)
+		HTML:    body.String(),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
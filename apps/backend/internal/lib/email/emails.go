@@ -1,14 +1,116 @@
 package email
 
-func (c *Client) SendWelcomeEmail(to, firstName string) error {
+import "fmt"
+
+// SendWelcomeEmail greets a new user with the onboarding steps for their
+// role ("SUPPLIER" or "BUYER"); other roles get the generic welcome.
+func (c *Client) SendWelcomeEmail(to, firstName, role string) error {
 	data := map[string]string{
 		"UserFirstName": firstName,
 	}
 
+	templateName := TemplateWelcome
+	switch role {
+	case "SUPPLIER":
+		templateName = TemplateWelcomeSupplier
+	case "BUYER":
+		templateName = TemplateWelcomeBuyer
+	}
+
 	return c.SendEmail(
 		to,
 		"Welcome to Ledgera!",
-		TemplateWelcome,
+		templateName,
+		data,
+	)
+}
+
+func (c *Client) SendProjectSubmittedEmail(to, projectTitle, supplierEmail, carbonAmount string) error {
+	data := map[string]string{
+		"ProjectTitle":  projectTitle,
+		"SupplierEmail": supplierEmail,
+		"CarbonAmount":  carbonAmount,
+	}
+
+	return c.SendEmail(
+		to,
+		fmt.Sprintf("New project awaiting review: %s", projectTitle),
+		TemplateProjectSubmitted,
+		data,
+	)
+}
+
+func (c *Client) SendProjectApprovedEmail(to, projectTitle string) error {
+	data := map[string]string{
+		"ProjectTitle": projectTitle,
+	}
+
+	return c.SendEmail(
+		to,
+		fmt.Sprintf("Your project %q has been approved", projectTitle),
+		TemplateProjectApproved,
+		data,
+	)
+}
+
+func (c *Client) SendProjectRejectedEmail(to, projectTitle, reason, feedback string) error {
+	data := map[string]string{
+		"ProjectTitle": projectTitle,
+		"Reason":       reason,
+		"Feedback":     feedback,
+	}
+
+	return c.SendEmail(
+		to,
+		fmt.Sprintf("Your project %q needs changes", projectTitle),
+		TemplateProjectRejected,
+		data,
+	)
+}
+
+func (c *Client) SendProjectDeployedEmail(to, projectTitle, tokenSymbol, contractAddress string) error {
+	data := map[string]string{
+		"ProjectTitle":    projectTitle,
+		"TokenSymbol":     tokenSymbol,
+		"ContractAddress": contractAddress,
+	}
+
+	return c.SendEmail(
+		to,
+		fmt.Sprintf("Your project %q is live on chain", projectTitle),
+		TemplateProjectDeployed,
+		data,
+	)
+}
+
+func (c *Client) SendListingSoldEmail(to, projectTitle, quantity, totalPrice, txHash string) error {
+	data := map[string]string{
+		"ProjectTitle": projectTitle,
+		"Quantity":     quantity,
+		"TotalPrice":   totalPrice,
+		"TxHash":       txHash,
+	}
+
+	return c.SendEmail(
+		to,
+		fmt.Sprintf("You sold %s credits of %s", quantity, projectTitle),
+		TemplateListingSold,
+		data,
+	)
+}
+
+func (c *Client) SendCertificateIssuedEmail(to, beneficiaryName, projectTitle, tonnes, verifyURL string) error {
+	data := map[string]string{
+		"BeneficiaryName": beneficiaryName,
+		"ProjectTitle":    projectTitle,
+		"Tonnes":          tonnes,
+		"VerifyURL":       verifyURL,
+	}
+
+	return c.SendEmail(
+		to,
+		fmt.Sprintf("Your retirement certificate for %s tCO2e", tonnes),
+		TemplateCertificateIssued,
 		data,
 	)
 }
@@ -0,0 +1,31 @@
+package email
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// FileSender writes every message as an .eml file into an outbox directory
+// instead of delivering it, for local development without a mail server.
+type FileSender struct {
+	dir string
+}
+
+func NewFileSender(dir string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create email outbox %s: %w", dir, err)
+	}
+	return &FileSender{dir: dir}, nil
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (s *FileSender) Send(msg Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), unsafeFilenameChars.ReplaceAllString(msg.To, "_"))
+
+	return os.WriteFile(filepath.Join(s.dir, name), buildMIME(msg, now), 0o644)
+}
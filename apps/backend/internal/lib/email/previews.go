@@ -4,4 +4,40 @@ var PreviewData = map[string]map[string]string{
 	"welcome": {
 		"UserFirstName": "John",
 	},
+	"welcome-supplier": {
+		"UserFirstName": "John",
+	},
+	"welcome-buyer": {
+		"UserFirstName": "John",
+	},
+	"project-submitted": {
+		"ProjectTitle":  "Kinabatangan Mangrove Restoration",
+		"SupplierEmail": "supplier@example.com",
+		"CarbonAmount":  "12500",
+	},
+	"project-approved": {
+		"ProjectTitle": "Kinabatangan Mangrove Restoration",
+	},
+	"project-rejected": {
+		"ProjectTitle": "Kinabatangan Mangrove Restoration",
+		"Reason":       "Area mismatch: The declared area does not match the boundary in the audit report.",
+		"Feedback":     "[area] ADMIN: The audit report covers 82 ha, not 100 ha.\n  [area] SUPPLIER: The remaining 18 ha are in the second annex.",
+	},
+	"project-deployed": {
+		"ProjectTitle":    "Kinabatangan Mangrove Restoration",
+		"TokenSymbol":     "KMR0001",
+		"ContractAddress": "0x5FbDB2315678afecb367f032d93F642f64180aa3",
+	},
+	"listing-sold": {
+		"ProjectTitle": "Kinabatangan Mangrove Restoration",
+		"Quantity":     "250",
+		"TotalPrice":   "3125",
+		"TxHash":       "0x8f4c2b6e1d9a7c3b5e0f2a4d6c8b1e3f5a7c9d0b2e4f6a8c1d3e5f7a9b0c2d4e",
+	},
+	"certificate-issued": {
+		"BeneficiaryName": "Acme Sdn Bhd",
+		"ProjectTitle":    "Kinabatangan Mangrove Restoration",
+		"Tonnes":          "25",
+		"VerifyURL":       "https://ledgera.example.com/api/v1/public/certificates/2f1c9a4e-7b3d-4c8e-9a1f-5d6e7b8c9d0a",
+	},
 }
@@ -0,0 +1,25 @@
+package email
+
+import "github.com/resend/resend-go/v2"
+
+// ResendSender delivers through the Resend API.
+type ResendSender struct {
+	client *resend.Client
+}
+
+func NewResendSender(apiKey string) *ResendSender {
+	return &ResendSender{client: resend.NewClient(apiKey)}
+}
+
+func (s *ResendSender) Send(msg Message) error {
+	params := &resend.SendEmailRequest{
+		From:    msg.From,
+		To:      []string{msg.To},
+		ReplyTo: msg.ReplyTo,
+		Subject: msg.Subject,
+		Html:    msg.HTML,
+	}
+
+	_, err := s.client.Emails.Send(params)
+	return err
+}
@@ -0,0 +1,100 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/inventedsarawak/ledgera/internal/config"
+)
+
+// Message is a rendered email ready to be delivered. From and ReplyTo are
+// RFC 5322 addresses such as "Ledgera <noreply@ledgera.io>".
+type Message struct {
+	From    string
+	ReplyTo string
+	To      string
+	Subject string
+	HTML    string
+}
+
+// Sender delivers rendered messages.
+type Sender interface {
+	Send(msg Message) error
+}
+
+// NewSender returns the transport selected by cfg.EmailTransport.
+func NewSender(cfg config.IntegrationConfig) (Sender, error) {
+	switch cfg.EmailTransport {
+	case "", "resend":
+		return NewResendSender(cfg.ResendAPIKey), nil
+	case "smtp":
+		return NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword), nil
+	case "file":
+		return NewFileSender(cfg.EmailOutboxDir)
+	default:
+		return nil, fmt.Errorf("unknown email transport %q", cfg.EmailTransport)
+	}
+}
+
+// DryRunSender keeps messages in memory instead of delivering them, so tests
+// can check what would have been sent.
+type DryRunSender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (s *DryRunSender) Send(msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+// Messages returns everything sent so far, oldest first.
+func (s *DryRunSender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// headerSanitizer keeps user-supplied text such as project titles from
+// breaking out of a header line.
+var headerSanitizer = strings.NewReplacer("\r", " ", "\n", " ")
+
+// buildMIME encodes msg as a single-part HTML message, as written by the SMTP
+// and file transports.
+func buildMIME(msg Message, date time.Time) []byte {
+	var b bytes.Buffer
+
+	header := func(key, value string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", key, headerSanitizer.Replace(value))
+	}
+	header("From", msg.From)
+	header("To", msg.To)
+	if msg.ReplyTo != "" {
+		header("Reply-To", msg.ReplyTo)
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `text/html; charset="utf-8"`)
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+	b.WriteString(msg.HTML)
+
+	return b.Bytes()
+}
+
+// addressOf returns the bare address of an RFC 5322 address.
+func addressOf(s string) (string, error) {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return "", err
+	}
+	return addr.Address, nil
+}
@@ -0,0 +1,38 @@
+package email
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPSender delivers through a plain SMTP server, such as MailHog in local
+// development. STARTTLS is used when the server offers it; credentials are
+// only sent when a username is configured.
+type SMTPSender struct {
+	addr string
+	auth smtp.Auth
+}
+
+func NewSMTPSender(host string, port int, username, password string) *SMTPSender {
+	s := &SMTPSender{addr: net.JoinHostPort(host, strconv.Itoa(port))}
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s
+}
+
+func (s *SMTPSender) Send(msg Message) error {
+	from, err := addressOf(msg.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	to, err := addressOf(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	return smtp.SendMail(s.addr, s.auth, from, []string{to}, buildMIME(msg, time.Now()))
+}
@@ -3,5 +3,13 @@ package email
 type Template string
 
 const (
-	TemplateWelcome Template = "welcome"
+	TemplateWelcome           Template = "welcome"
+	TemplateWelcomeSupplier   Template = "welcome-supplier"
+	TemplateWelcomeBuyer      Template = "welcome-buyer"
+	TemplateProjectSubmitted  Template = "project-submitted"
+	TemplateProjectApproved   Template = "project-approved"
+	TemplateProjectRejected   Template = "project-rejected"
+	TemplateProjectDeployed   Template = "project-deployed"
+	TemplateListingSold       Template = "listing-sold"
+	TemplateCertificateIssued Template = "certificate-issued"
 )
@@ -0,0 +1,315 @@
+// Package geo parses and checks project boundary polygons uploaded as GeoJSON
+// or KML.
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Limits on what counts as a plausible project boundary.
+const (
+	// MaxPositions bounds the vertex count, which also bounds the cost of
+	// the self-intersection check.
+	MaxPositions = 5000
+
+	MinAreaHectares = 0.1
+	MaxAreaHectares = 1_000_000
+)
+
+// earthRadius is the WGS84 equatorial radius in metres, as used by GeoJSON
+// tooling for geodesic areas.
+const earthRadius = 6378137.0
+
+var (
+	ErrUnsupportedFormat   = errors.New("boundary must be a GeoJSON or KML document")
+	ErrUnsupportedGeometry = errors.New("boundary must contain exactly one polygon")
+	ErrTooFewPositions     = errors.New("every ring needs at least four positions")
+	ErrTooManyPositions    = fmt.Errorf("boundary has more than %d positions", MaxPositions)
+	ErrRingNotClosed       = errors.New("every ring must end where it starts")
+	ErrOutOfRange          = errors.New("coordinates must be valid longitudes and latitudes")
+	ErrSelfIntersection    = errors.New("boundary intersects itself")
+	ErrHoleOutside         = errors.New("holes must lie inside the outer ring")
+	ErrAreaOutOfRange      = fmt.Errorf("boundary area must be between %g and %g ha", float64(MinAreaHectares), float64(MaxAreaHectares))
+)
+
+// Position is a [longitude, latitude] pair in degrees.
+type Position [2]float64
+
+// Ring is a closed linear ring: its first and last positions are equal.
+type Ring []Position
+
+// Polygon is an outer ring followed by any holes.
+type Polygon []Ring
+
+// Parse reads a boundary from a GeoJSON or KML document, telling them apart
+// by their first character.
+func Parse(data []byte) (Polygon, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, ErrUnsupportedFormat
+	}
+
+	var (
+		p   Polygon
+		err error
+	)
+	switch trimmed[0] {
+	case '{':
+		p, err = ParseGeoJSON(trimmed)
+	case '<':
+		p, err = ParseKML(trimmed)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.withoutRepeats(), nil
+}
+
+// withoutRepeats drops consecutive duplicate positions, which GIS exports
+// often contain and which would otherwise read as zero-length edges.
+func (p Polygon) withoutRepeats() Polygon {
+	out := make(Polygon, len(p))
+	for i, ring := range p {
+		for j, pos := range ring {
+			if j > 0 && pos == ring[j-1] {
+				continue
+			}
+			out[i] = append(out[i], pos)
+		}
+	}
+	return out
+}
+
+// geoJSONObject covers the GeoJSON objects a boundary may be wrapped in.
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSONObject  `json:"geometry"`
+	Features    []geoJSONObject `json:"features"`
+}
+
+// ParseGeoJSON reads a Polygon, or a MultiPolygon with one polygon, either
+// bare or as the only feature of a Feature or FeatureCollection.
+func ParseGeoJSON(data []byte) (Polygon, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	for {
+		switch obj.Type {
+		case "FeatureCollection":
+			if len(obj.Features) != 1 {
+				return nil, ErrUnsupportedGeometry
+			}
+			obj = obj.Features[0]
+		case "Feature":
+			if obj.Geometry == nil {
+				return nil, ErrUnsupportedGeometry
+			}
+			obj = *obj.Geometry
+		case "Polygon":
+			var p Polygon
+			if err := json.Unmarshal(obj.Coordinates, &p); err != nil {
+				return nil, ErrUnsupportedGeometry
+			}
+			return p, nil
+		case "MultiPolygon":
+			var mp []Polygon
+			if err := json.Unmarshal(obj.Coordinates, &mp); err != nil || len(mp) != 1 {
+				return nil, ErrUnsupportedGeometry
+			}
+			return mp[0], nil
+		default:
+			return nil, ErrUnsupportedGeometry
+		}
+	}
+}
+
+// GeoJSON returns p as a GeoJSON Polygon geometry.
+func (p Polygon) GeoJSON() []byte {
+	out, _ := json.Marshal(struct {
+		Type        string  `json:"type"`
+		Coordinates Polygon `json:"coordinates"`
+	}{"Polygon", p})
+	return out
+}
+
+// Validate checks that p is a simple polygon of plausible size: every ring is
+// closed and in range, no edges cross or touch, holes lie inside the outer
+// ring, and the area is between MinAreaHectares and MaxAreaHectares.
+func (p Polygon) Validate() error {
+	if len(p) == 0 {
+		return ErrUnsupportedGeometry
+	}
+
+	count := 0
+	for _, ring := range p {
+		count += len(ring)
+		if count > MaxPositions {
+			return ErrTooManyPositions
+		}
+		if len(ring) < 4 {
+			return ErrTooFewPositions
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return ErrRingNotClosed
+		}
+		for _, pos := range ring {
+			if math.IsNaN(pos[0]) || math.IsNaN(pos[1]) || math.Abs(pos[0]) > 180 || math.Abs(pos[1]) > 90 {
+				return ErrOutOfRange
+			}
+		}
+	}
+
+	if p.selfIntersects() {
+		return ErrSelfIntersection
+	}
+	for _, hole := range p[1:] {
+		if !p[0].contains(hole[0]) {
+			return ErrHoleOutside
+		}
+	}
+
+	if area := p.AreaHectares(); area < MinAreaHectares || area > MaxAreaHectares {
+		return ErrAreaOutOfRange
+	}
+	return nil
+}
+
+// AreaHectares is the geodesic area of p, holes excluded.
+func (p Polygon) AreaHectares() float64 {
+	if len(p) == 0 {
+		return 0
+	}
+	area := math.Abs(p[0].area())
+	for _, hole := range p[1:] {
+		area -= math.Abs(hole.area())
+	}
+	return area / 10000
+}
+
+// area is the signed area of r on the sphere in square metres, after
+// Chamberlain and Duquette, "Some Algorithms for Polygons on a Sphere".
+func (r Ring) area() float64 {
+	n := len(r) - 1 // the closing position repeats the first
+	if n < 3 {
+		return 0
+	}
+
+	var total float64
+	for i := 0; i < n; i++ {
+		lower, middle, upper := r[i], r[(i+1)%n], r[(i+2)%n]
+		total += (rad(upper[0]) - rad(lower[0])) * math.Sin(rad(middle[1]))
+	}
+	return total * earthRadius * earthRadius / 2
+}
+
+// contains reports whether pos lies inside r, treating coordinates as planar,
+// which is accurate enough at the scale of a project.
+func (r Ring) contains(pos Position) bool {
+	inside := false
+	for i, j := 0, len(r)-2; i < len(r)-1; j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a[1] > pos[1]) != (b[1] > pos[1]) &&
+			pos[0] < (b[0]-a[0])*(pos[1]-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// selfIntersects reports whether any two edges of p meet, other than
+// consecutive edges of a ring at their shared vertex.
+func (p Polygon) selfIntersects() bool {
+	type edge struct {
+		ring, index int
+		a, b        Position
+	}
+
+	var edges []edge
+	for ri, ring := range p {
+		for i := 0; i < len(ring)-1; i++ {
+			edges = append(edges, edge{ri, i, ring[i], ring[i+1]})
+		}
+	}
+
+	for i := 0; i < len(edges); i++ {
+		for j := i + 1; j < len(edges); j++ {
+			e, f := edges[i], edges[j]
+			if e.ring == f.ring && adjacent(e.index, f.index, len(p[e.ring])-1) {
+				if overlapping(e.a, e.b, f.a, f.b) {
+					return true
+				}
+				continue
+			}
+			if segmentsIntersect(e.a, e.b, f.a, f.b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// adjacent reports whether edges i and j of a ring with n edges share a
+// vertex.
+func adjacent(i, j, n int) bool {
+	d := j - i
+	if d < 0 {
+		d = -d
+	}
+	return d == 1 || d == n-1
+}
+
+// overlapping reports whether two edges that share a vertex fold back over
+// each other.
+func overlapping(a, b, c, d Position) bool {
+	return orientation(a, b, c) == 0 && orientation(a, b, d) == 0 &&
+		(between(a, b, c) && c != a && c != b || between(a, b, d) && d != a && d != b ||
+			between(c, d, a) && a != c && a != d || between(c, d, b) && b != c && b != d)
+}
+
+// segmentsIntersect reports whether segments ab and cd have any point in
+// common.
+func segmentsIntersect(a, b, c, d Position) bool {
+	o1, o2 := orientation(a, b, c), orientation(a, b, d)
+	o3, o4 := orientation(c, d, a), orientation(c, d, b)
+
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	return o1 == 0 && between(a, b, c) ||
+		o2 == 0 && between(a, b, d) ||
+		o3 == 0 && between(c, d, a) ||
+		o4 == 0 && between(c, d, b)
+}
+
+// orientation is the turn direction of a, b, c: 1 counter-clockwise, -1
+// clockwise, 0 collinear.
+func orientation(a, b, c Position) int {
+	v := (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// between reports whether c, collinear with ab, lies on segment ab.
+func between(a, b, c Position) bool {
+	return math.Min(a[0], b[0]) <= c[0] && c[0] <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= c[1] && c[1] <= math.Max(a[1], b[1])
+}
+
+func rad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
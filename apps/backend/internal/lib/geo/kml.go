@@ -0,0 +1,81 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// kmlPolygon is the part of a KML <Polygon> a boundary needs. Elements are
+// matched by local name, so the KML namespace version does not matter.
+type kmlPolygon struct {
+	Outer string   `xml:"outerBoundaryIs>LinearRing>coordinates"`
+	Inner []string `xml:"innerBoundaryIs>LinearRing>coordinates"`
+}
+
+// ParseKML reads the single <Polygon> in a KML document, wherever it sits in
+// the Document/Folder/Placemark tree.
+func ParseKML(data []byte) (Polygon, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var found []kmlPolygon
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrUnsupportedFormat
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Polygon" {
+			continue
+		}
+		var kp kmlPolygon
+		if err := dec.DecodeElement(&kp, &start); err != nil {
+			return nil, ErrUnsupportedFormat
+		}
+		found = append(found, kp)
+	}
+	if len(found) != 1 {
+		return nil, ErrUnsupportedGeometry
+	}
+
+	outer, err := parseKMLCoordinates(found[0].Outer)
+	if err != nil {
+		return nil, err
+	}
+	p := Polygon{outer}
+	for _, raw := range found[0].Inner {
+		hole, err := parseKMLCoordinates(raw)
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, hole)
+	}
+	return p, nil
+}
+
+// parseKMLCoordinates reads a whitespace-separated list of "lng,lat[,alt]"
+// tuples, dropping any altitude.
+func parseKMLCoordinates(raw string) (Ring, error) {
+	var ring Ring
+	for _, tuple := range strings.Fields(raw) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, ErrUnsupportedGeometry
+		}
+		lng, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return nil, ErrUnsupportedGeometry
+		}
+		lat, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, ErrUnsupportedGeometry
+		}
+		ring = append(ring, Position{lng, lat})
+	}
+	return ring, nil
+}
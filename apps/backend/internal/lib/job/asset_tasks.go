@@ -0,0 +1,65 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskDeployProject = "asset:deploy_project"
+	TaskMintTokens    = "asset:mint_tokens"
+)
+
+// DeployProjectQueue is the queue deploy tasks run in.
+const DeployProjectQueue = "critical"
+
+// DeployProjectTaskID is the ID of the deploy task of a project.
+func DeployProjectTaskID(projectID string) string {
+	return "deploy:" + projectID
+}
+
+type DeployProjectPayload struct {
+	ProjectID string `json:"project_id"`
+	Symbol    string `json:"symbol"`
+}
+
+func NewDeployProjectTask(projectID, symbol string) (*asynq.Task, error) {
+	payload, err := json.Marshal(DeployProjectPayload{
+		ProjectID: projectID,
+		Symbol:    symbol,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The task ID makes enqueueing idempotent: a second deploy request for the
+	// same project is rejected by asynq while the first one is still around.
+	// Enqueue it with JobService.EnqueueRetryable so a failed deploy can be
+	// requested again.
+	return asynq.NewTask(TaskDeployProject, payload,
+		asynq.TaskID(DeployProjectTaskID(projectID)),
+		asynq.MaxRetry(5),
+		asynq.Queue(DeployProjectQueue),
+		asynq.Timeout(5*time.Minute)), nil
+}
+
+type MintTokensPayload struct {
+	IssuanceID string `json:"issuance_id"`
+}
+
+func NewMintTokensTask(issuanceID string) (*asynq.Task, error) {
+	payload, err := json.Marshal(MintTokensPayload{
+		IssuanceID: issuanceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskMintTokens, payload,
+		asynq.TaskID("mint:"+issuanceID),
+		asynq.MaxRetry(5),
+		asynq.Queue("critical"),
+		asynq.Timeout(5*time.Minute)), nil
+}
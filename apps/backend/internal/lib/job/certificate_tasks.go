@@ -0,0 +1,34 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskRenderCertificate = "certificate:render"
+)
+
+type RenderCertificatePayload struct {
+	CertificateID string `json:"certificate_id"`
+}
+
+// NewRenderCertificateTask builds the task that renders a retirement
+// certificate PDF and uploads it. The task ID keeps a certificate from being
+// queued twice.
+func NewRenderCertificateTask(certificateID string) (*asynq.Task, error) {
+	payload, err := json.Marshal(RenderCertificatePayload{
+		CertificateID: certificateID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskRenderCertificate, payload,
+		asynq.TaskID("certificate:"+certificateID),
+		asynq.MaxRetry(5),
+		asynq.Queue("default"),
+		asynq.Timeout(2*time.Minute)), nil
+}
@@ -8,25 +8,132 @@ import (
 )
 
 const (
-	TaskWelcome = "email:welcome"
+	TaskWelcome           = "email:welcome"
+	TaskProjectSubmitted  = "email:project_submitted"
+	TaskProjectApproved   = "email:project_approved"
+	TaskProjectRejected   = "email:project_rejected"
+	TaskProjectDeployed   = "email:project_deployed"
+	TaskListingSold       = "email:listing_sold"
+	TaskCertificateIssued = "email:certificate_issued"
 )
 
+// welcomeEmailRetention keeps a completed welcome task around so its TaskID
+// keeps rejecting duplicates, e.g. a user syncing twice from two tabs.
+const welcomeEmailRetention = 30 * 24 * time.Hour
+
+// deployedEmailRetention does the same for the deployed email, outlasting
+// every retry of a deployment job.
+const deployedEmailRetention = 7 * 24 * time.Hour
+
 type WelcomeEmailPayload struct {
 	To        string `json:"to"`
 	FirstName string `json:"first_name"`
+	Role      string `json:"role"`
+}
+
+// NewWelcomeEmailTask builds the welcome email for the user with clerkID.
+// The task ID is derived from clerkID, so enqueueing it again for the same
+// user fails with asynq.ErrTaskIDConflict instead of sending a second email.
+func NewWelcomeEmailTask(clerkID string, p WelcomeEmailPayload) (*asynq.Task, error) {
+	return newEmailTask(TaskWelcome, p,
+		asynq.TaskID(TaskWelcome+":"+clerkID),
+		asynq.Retention(welcomeEmailRetention))
+}
+
+// emailPayload is implemented by every transactional email task payload.
+type emailPayload interface {
+	recipient() string
+}
+
+type ProjectSubmittedEmailPayload struct {
+	To            string `json:"to"`
+	ProjectTitle  string `json:"project_title"`
+	SupplierEmail string `json:"supplier_email"`
+	CarbonAmount  string `json:"carbon_amount"`
+}
+
+type ProjectApprovedEmailPayload struct {
+	To           string `json:"to"`
+	ProjectTitle string `json:"project_title"`
+}
+
+type ProjectRejectedEmailPayload struct {
+	To           string `json:"to"`
+	ProjectTitle string `json:"project_title"`
+	Reason       string `json:"reason"`
+	Feedback     string `json:"feedback"`
+}
+
+type ProjectDeployedEmailPayload struct {
+	To              string `json:"to"`
+	ProjectTitle    string `json:"project_title"`
+	TokenSymbol     string `json:"token_symbol"`
+	ContractAddress string `json:"contract_address"`
+}
+
+type ListingSoldEmailPayload struct {
+	To           string `json:"to"`
+	ProjectTitle string `json:"project_title"`
+	Quantity     string `json:"quantity"`
+	TotalPrice   string `json:"total_price"`
+	TxHash       string `json:"tx_hash"`
+}
+
+type CertificateIssuedEmailPayload struct {
+	To              string `json:"to"`
+	BeneficiaryName string `json:"beneficiary_name"`
+	ProjectTitle    string `json:"project_title"`
+	Tonnes          string `json:"tonnes"`
+	VerifyURL       string `json:"verify_url"`
+}
+
+func (p WelcomeEmailPayload) recipient() string           { return p.To }
+func (p ProjectSubmittedEmailPayload) recipient() string  { return p.To }
+func (p ProjectApprovedEmailPayload) recipient() string   { return p.To }
+func (p ProjectRejectedEmailPayload) recipient() string   { return p.To }
+func (p ProjectDeployedEmailPayload) recipient() string   { return p.To }
+func (p ListingSoldEmailPayload) recipient() string       { return p.To }
+func (p CertificateIssuedEmailPayload) recipient() string { return p.To }
+
+func NewProjectSubmittedEmailTask(p ProjectSubmittedEmailPayload) (*asynq.Task, error) {
+	return newEmailTask(TaskProjectSubmitted, p)
+}
+
+func NewProjectApprovedEmailTask(p ProjectApprovedEmailPayload) (*asynq.Task, error) {
+	return newEmailTask(TaskProjectApproved, p)
+}
+
+func NewProjectRejectedEmailTask(p ProjectRejectedEmailPayload) (*asynq.Task, error) {
+	return newEmailTask(TaskProjectRejected, p)
+}
+
+// NewProjectDeployedEmailTask is keyed by project so a deployment job that
+// is retried, or finds the indexer already linked the token, does not email
+// the supplier twice.
+func NewProjectDeployedEmailTask(projectID string, p ProjectDeployedEmailPayload) (*asynq.Task, error) {
+	return newEmailTask(TaskProjectDeployed, p,
+		asynq.TaskID(TaskProjectDeployed+":"+projectID),
+		asynq.Retention(deployedEmailRetention))
+}
+
+func NewListingSoldEmailTask(p ListingSoldEmailPayload) (*asynq.Task, error) {
+	return newEmailTask(TaskListingSold, p)
+}
+
+func NewCertificateIssuedEmailTask(p CertificateIssuedEmailPayload) (*asynq.Task, error) {
+	return newEmailTask(TaskCertificateIssued, p)
 }
 
-func NewWelcomeEmailTask(to, firstName string) (*asynq.Task, error) {
-	payload, err := json.Marshal(WelcomeEmailPayload{
-		To:        to,
-		FirstName: firstName,
-	})
+func newEmailTask(taskType string, p emailPayload, opts ...asynq.Option) (*asynq.Task, error) {
+	payload, err := json.Marshal(p)
 	if err != nil {
 		return nil, err
 	}
 
-	return asynq.NewTask(TaskWelcome, payload,
+	opts = append([]asynq.Option{
 		asynq.MaxRetry(3),
 		asynq.Queue("default"),
-		asynq.Timeout(30*time.Second)), nil
+		asynq.Timeout(30 * time.Second),
+	}, opts...)
+	return asynq.NewTask(taskType, payload, opts...), nil
 }
@@ -6,44 +6,88 @@ import (
 	"fmt"
 
 	"github.com/hibiken/asynq"
-	"github.com/rs/zerolog"
-	"github.com/inventedsarawak/ledgera/internal/config"
 	"github.com/inventedsarawak/ledgera/internal/lib/email"
 )
 
 var emailClient *email.Client
 
-func (j *JobService) InitHandlers(config *config.Config, logger *zerolog.Logger) {
-	emailClient = email.NewClient(config, logger)
+func (j *JobService) InitHandlers(client *email.Client) {
+	emailClient = client
 }
 
-func (j *JobService) handleWelcomeEmailTask(ctx context.Context, t *asynq.Task) error {
-	var p WelcomeEmailPayload
-	if err := json.Unmarshal(t.Payload(), &p); err != nil {
-		return fmt.Errorf("failed to unmarshal welcome email payload: %w", err)
+// emailSender decodes an email task payload and sends it with c, returning
+// the recipient for logging.
+type emailSender func(c *email.Client, payload []byte) (string, error)
+
+func decodeAndSend[P emailPayload](send func(c *email.Client, p P) error) emailSender {
+	return func(c *email.Client, payload []byte) (string, error) {
+		var p P
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return "", fmt.Errorf("failed to unmarshal email payload: %w", err)
+		}
+		return p.recipient(), send(c, p)
+	}
+}
+
+// emailSenders maps every transactional email task to the client method that
+// renders and sends it.
+var emailSenders = map[string]emailSender{
+	TaskWelcome: decodeAndSend(func(c *email.Client, p WelcomeEmailPayload) error {
+		return c.SendWelcomeEmail(p.To, p.FirstName, p.Role)
+	}),
+	TaskProjectSubmitted: decodeAndSend(func(c *email.Client, p ProjectSubmittedEmailPayload) error {
+		return c.SendProjectSubmittedEmail(p.To, p.ProjectTitle, p.SupplierEmail, p.CarbonAmount)
+	}),
+	TaskProjectApproved: decodeAndSend(func(c *email.Client, p ProjectApprovedEmailPayload) error {
+		return c.SendProjectApprovedEmail(p.To, p.ProjectTitle)
+	}),
+	TaskProjectRejected: decodeAndSend(func(c *email.Client, p ProjectRejectedEmailPayload) error {
+		return c.SendProjectRejectedEmail(p.To, p.ProjectTitle, p.Reason, p.Feedback)
+	}),
+	TaskProjectDeployed: decodeAndSend(func(c *email.Client, p ProjectDeployedEmailPayload) error {
+		return c.SendProjectDeployedEmail(p.To, p.ProjectTitle, p.TokenSymbol, p.ContractAddress)
+	}),
+	TaskListingSold: decodeAndSend(func(c *email.Client, p ListingSoldEmailPayload) error {
+		return c.SendListingSoldEmail(p.To, p.ProjectTitle, p.Quantity, p.TotalPrice, p.TxHash)
+	}),
+	TaskCertificateIssued: decodeAndSend(func(c *email.Client, p CertificateIssuedEmailPayload) error {
+		return c.SendCertificateIssuedEmail(p.To, p.BeneficiaryName, p.ProjectTitle, p.Tonnes, p.VerifyURL)
+	}),
+}
+
+// SendEmailTask sends an email task straight away with client instead of
+// queueing it. It is how emails go out when there is no job server.
+func SendEmailTask(client *email.Client, task *asynq.Task) error {
+	send, ok := emailSenders[task.Type()]
+	if !ok {
+		return fmt.Errorf("unknown email task %s", task.Type())
 	}
+	_, err := send(client, task.Payload())
+	return err
+}
 
-	j.logger.Info().
-		Str("type", "welcome").
-		Str("to", p.To).
-		Msg("Processing welcome email task")
-
-	err := emailClient.SendWelcomeEmail(
-		p.To,
-		p.FirstName,
-	)
-	if err != nil {
-		j.logger.Error().
-			Str("type", "welcome").
-			Str("to", p.To).
-			Err(err).
-			Msg("Failed to send welcome email")
-		return err
+func (j *JobService) registerEmailHandlers() {
+	for taskType, send := range emailSenders {
+		j.mux.HandleFunc(taskType, j.handleEmailTask(taskType, send))
 	}
+}
 
-	j.logger.Info().
-		Str("type", "welcome").
-		Str("to", p.To).
-		Msg("Successfully sent welcome email")
-	return nil
+func (j *JobService) handleEmailTask(taskType string, send emailSender) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		to, err := send(emailClient, t.Payload())
+		if err != nil {
+			j.logger.Error().
+				Str("type", taskType).
+				Str("to", to).
+				Err(err).
+				Msg("Failed to send email")
+			return err
+		}
+
+		j.logger.Info().
+			Str("type", taskType).
+			Str("to", to).
+			Msg("Successfully sent email")
+		return nil
+	}
 }
\ No newline at end of file
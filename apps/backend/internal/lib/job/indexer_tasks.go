@@ -0,0 +1,26 @@
+package job
+
+import (
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskIndexerSync = "indexer:sync"
+
+	// IndexerSyncInterval is how often the scheduler enqueues TaskIndexerSync.
+	IndexerSyncInterval = "@every 15s"
+)
+
+// NewIndexerSyncTask builds the periodic chain indexer task. It carries no
+// payload: the indexer resumes from its checkpoint. Unique keeps ticks from
+// piling up while a long catch-up run is still in progress, and a failed run
+// is simply picked up by the next tick.
+func NewIndexerSyncTask() *asynq.Task {
+	return asynq.NewTask(TaskIndexerSync, nil,
+		asynq.MaxRetry(0),
+		asynq.Queue("low"),
+		asynq.Timeout(5*time.Minute),
+		asynq.Unique(5*time.Minute))
+}
@@ -1,15 +1,21 @@
 package job
 
 import (
+	"context"
+	"errors"
+
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog"
 	"github.com/inventedsarawak/ledgera/internal/config"
 )
 
 type JobService struct {
-	Client *asynq.Client
-	server *asynq.Server
-	logger *zerolog.Logger
+	Client    *asynq.Client
+	Inspector *asynq.Inspector
+	server    *asynq.Server
+	scheduler *asynq.Scheduler
+	mux       *asynq.ServeMux
+	logger    *zerolog.Logger
 }
 
 func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
@@ -31,28 +37,82 @@ func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
 		},
 	)
 
+	scheduler := asynq.NewScheduler(
+		asynq.RedisClientOpt{Addr: redisAddr},
+		&asynq.SchedulerOpts{},
+	)
+
 	return &JobService{
-		Client: client,
-		server: server,
-		logger: logger,
+		Client:    client,
+		Inspector: asynq.NewInspector(asynq.RedisClientOpt{Addr: redisAddr}),
+		server:    server,
+		scheduler: scheduler,
+		mux:       asynq.NewServeMux(),
+		logger:    logger,
 	}
 }
 
 func (j *JobService) Start() error {
 	// Register task handlers
-	mux := asynq.NewServeMux()
-	mux.HandleFunc(TaskWelcome, j.handleWelcomeEmailTask)
+	j.registerEmailHandlers()
 
 	j.logger.Info().Msg("Starting background job server")
-	if err := j.server.Start(mux); err != nil {
+	if err := j.server.Start(j.mux); err != nil {
+		return err
+	}
+
+	if err := j.scheduler.Start(); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// Register adds a handler for a task type owned by another package (e.g. a
+// service that needs repositories). Safe to call after Start.
+func (j *JobService) Register(taskType string, handler asynq.HandlerFunc) {
+	j.mux.HandleFunc(taskType, handler)
+}
+
+// Schedule enqueues task periodically according to cronspec (e.g. "@every 15s").
+// Safe to call after Start.
+func (j *JobService) Schedule(cronspec string, task *asynq.Task, opts ...asynq.Option) error {
+	_, err := j.scheduler.Register(cronspec, task, opts...)
+	return err
+}
+
 func (j *JobService) Stop() {
 	j.logger.Info().Msg("Stopping background job server")
+	j.scheduler.Shutdown()
 	j.server.Shutdown()
 	j.Client.Close()
+	j.Inspector.Close()
+}
+
+// EnqueueRetryable enqueues task, which must carry asynq.TaskID(taskID) and
+// asynq.Queue(queue). A previous task with that ID that was given up on is archived,
+// and asynq keeps its ID taken for months; it is deleted so the work can be
+// asked for again. A previous task that is still pending, scheduled,
+// retrying or running makes it fail with asynq.ErrTaskIDConflict.
+func (j *JobService) EnqueueRetryable(ctx context.Context, task *asynq.Task, queue, taskID string) error {
+	_, err := j.Client.EnqueueContext(ctx, task)
+	if !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return err
+	}
+
+	info, err := j.Inspector.GetTaskInfo(queue, taskID)
+	if err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+		return err
+	}
+	if info != nil {
+		if info.State != asynq.TaskStateArchived {
+			return asynq.ErrTaskIDConflict
+		}
+		if err := j.Inspector.DeleteTask(queue, taskID); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+			return err
+		}
+	}
+
+	_, err = j.Client.EnqueueContext(ctx, task)
+	return err
 }
\ No newline at end of file
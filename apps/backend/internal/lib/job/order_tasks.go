@@ -0,0 +1,25 @@
+package job
+
+import (
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskExpireOrders = "order:expire_reservations"
+
+	// ExpireOrdersInterval is how often the scheduler enqueues TaskExpireOrders.
+	ExpireOrdersInterval = "@every 1m"
+)
+
+// NewExpireOrdersTask builds the periodic task that expires lapsed order
+// reservations and returns their quantity to the listings. Like the indexer
+// tick it carries no payload and a failed run is retried by the next tick.
+func NewExpireOrdersTask() *asynq.Task {
+	return asynq.NewTask(TaskExpireOrders, nil,
+		asynq.MaxRetry(0),
+		asynq.Queue("default"),
+		asynq.Timeout(time.Minute),
+		asynq.Unique(time.Minute))
+}
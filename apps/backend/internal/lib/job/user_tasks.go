@@ -0,0 +1,26 @@
+package job
+
+import (
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskPurgeDeletedUsers = "user:purge_deleted"
+
+	// PurgeDeletedUsersInterval is how often the scheduler enqueues
+	// TaskPurgeDeletedUsers.
+	PurgeDeletedUsersInterval = "@every 1h"
+)
+
+// NewPurgeDeletedUsersTask builds the periodic task that hard-deletes
+// accounts whose retention period has passed. It carries no payload; a failed
+// run is retried by the next tick.
+func NewPurgeDeletedUsersTask() *asynq.Task {
+	return asynq.NewTask(TaskPurgeDeletedUsers, nil,
+		asynq.MaxRetry(0),
+		asynq.Queue("default"),
+		asynq.Timeout(5*time.Minute),
+		asynq.Unique(time.Hour))
+}
@@ -0,0 +1,107 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CertificateData is everything printed on a retirement certificate.
+// VerifyURL is encoded in the QR code; it is left out when empty. ContentHash
+// is the value the public verification endpoint reports for the certificate.
+type CertificateData struct {
+	CertificateID   string
+	BeneficiaryName string
+	Tonnes          float64
+	ProjectTitle    string
+	Reason          string
+	TxHash          string
+	WalletAddress   string
+	RetiredAt       time.Time
+	VerifyURL       string
+	ContentHash     string
+}
+
+// RenderCertificate renders a one-page A4 landscape retirement certificate.
+func RenderCertificate(data CertificateData) ([]byte, error) {
+	doc := newDocument("L", "Retirement Certificate "+data.CertificateID, data.RetiredAt)
+	pageW, pageH := doc.GetPageSize()
+
+	// Frame
+	doc.SetDrawColor(brandPrimary.r, brandPrimary.g, brandPrimary.b)
+	doc.SetLineWidth(1.2)
+	doc.Rect(8, 8, pageW-16, pageH-16, "D")
+	doc.SetLineWidth(0.3)
+	doc.Rect(11, 11, pageW-22, pageH-22, "D")
+
+	doc.SetXY(20, 20)
+	doc.font("B", 13, brandPrimary)
+	doc.text(0, 8, brandName, "C")
+	doc.font("B", 28, textDark)
+	doc.text(0, 16, "Carbon Credit Retirement Certificate", "C")
+
+	doc.Ln(4)
+	doc.font("", 13, textMuted)
+	doc.text(0, 9, "This certifies that", "C")
+	doc.font("B", 22, textDark)
+	doc.text(0, 13, data.BeneficiaryName, "C")
+	doc.font("", 13, textMuted)
+	doc.text(0, 9, "has permanently retired", "C")
+	doc.font("B", 26, brandPrimary)
+	doc.text(0, 15, formatTonnes(data.Tonnes)+" tCO2e", "C")
+	doc.font("", 13, textMuted)
+	doc.text(0, 9, "of verified carbon credits from", "C")
+	doc.font("B", 15, textDark)
+	doc.text(0, 10, data.ProjectTitle, "C")
+
+	if data.Reason != "" {
+		doc.Ln(2)
+		doc.font("I", 11, textMuted)
+		doc.SetX(50)
+		doc.MultiCell(pageW-100, 6, doc.tr(data.Reason), "", "C", false)
+	}
+
+	// On-chain proof, bottom left
+	detailsY := pageH - 56.0
+	doc.SetFillColor(brandLight.r, brandLight.g, brandLight.b)
+	doc.Rect(20, detailsY-4, pageW-90, 40, "F")
+	doc.SetXY(24, detailsY)
+	rows := [][2]string{
+		{"Certificate", data.CertificateID},
+		{"Retired on", data.RetiredAt.UTC().Format("2 January 2006")},
+		{"Wallet", data.WalletAddress},
+		{"Transaction", data.TxHash},
+		{"Content hash", data.ContentHash},
+	}
+	for _, row := range rows {
+		doc.SetX(24)
+		doc.font("B", 9, textDark)
+		doc.CellFormat(28, 6, row[0], "", 0, "L", false, 0, "")
+		doc.SetFont("Courier", "", 9)
+		doc.CellFormat(0, 6, row[1], "", 1, "L", false, 0, "")
+	}
+
+	// Verification QR, bottom right
+	if data.VerifyURL != "" {
+		const size = 34.0
+		x, y := pageW-20-size, pageH-20-size-6
+		if err := doc.qrCode(data.VerifyURL, x, y, size); err != nil {
+			return nil, err
+		}
+		doc.SetXY(x-8, y+size+1)
+		doc.font("", 8, textMuted)
+		doc.CellFormat(size+16, 4, "Scan to verify", "", 0, "C", false, 0, "")
+	}
+
+	out, err := doc.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render certificate: %w", err)
+	}
+	return out, nil
+}
+
+// formatTonnes prints tonnes with as many decimals as needed, up to the
+// NUMERIC(36, 18) precision of the certificates table.
+func formatTonnes(tonnes float64) string {
+	return strconv.FormatFloat(tonnes, 'f', -1, 64)
+}
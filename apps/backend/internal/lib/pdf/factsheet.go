@@ -0,0 +1,73 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+)
+
+// RenderFactSheet renders a one-page A4 summary of a project. The document is
+// dated by the project's last update, so an unchanged project always renders
+// the same file.
+func RenderFactSheet(p project.Project) ([]byte, error) {
+	doc := newDocument("P", "Project Fact Sheet - "+p.Title, p.UpdatedAt)
+	pageW, pageH := doc.GetPageSize()
+	contentW := pageW - 40
+
+	doc.header("Project Fact Sheet")
+
+	doc.SetXY(20, 24)
+	doc.font("B", 22, textDark)
+	doc.MultiCell(contentW, 10, doc.tr(p.Title), "", "L", false)
+
+	// Status badge
+	doc.Ln(1)
+	doc.SetFillColor(brandLight.r, brandLight.g, brandLight.b)
+	doc.font("B", 9, brandPrimary)
+	status := string(p.Status)
+	doc.CellFormat(doc.GetStringWidth(status)+8, 6, status, "", 1, "C", true, 0, "")
+
+	doc.Ln(5)
+	doc.font("", 11, textDark)
+	doc.MultiCell(contentW, 6, doc.tr(p.Description), "", "L", false)
+
+	doc.Ln(6)
+	doc.font("B", 13, brandPrimary)
+	doc.text(0, 8, "Key facts", "L")
+
+	facts := [][2]string{
+		{"Verified carbon", formatTonnes(p.CarbonAmount) + " tCO2e"},
+		{"Price per tonne", "$" + strconv.FormatFloat(p.PricePerTonne, 'f', 2, 64)},
+		{"Area", strconv.FormatFloat(p.Area, 'f', -1, 64) + " ha"},
+		{"Location", fmt.Sprintf("%.5f, %.5f", p.LocationLat, p.LocationLng)},
+	}
+	if p.TokenSymbol != nil {
+		facts = append(facts, [2]string{"Token", *p.TokenSymbol})
+	}
+	if p.ContractAddress != nil {
+		facts = append(facts, [2]string{"Token contract", *p.ContractAddress})
+	}
+	if p.AuditReportURL != "" {
+		facts = append(facts, [2]string{"Audit report", p.AuditReportURL})
+	}
+
+	doc.SetDrawColor(brandLight.r, brandLight.g, brandLight.b)
+	doc.SetLineWidth(0.3)
+	for _, fact := range facts {
+		doc.font("B", 10, textMuted)
+		doc.CellFormat(45, 8, fact[0], "B", 0, "L", false, 0, "")
+		doc.font("", 10, textDark)
+		doc.CellFormat(contentW-45, 8, doc.tr(fact[1]), "B", 1, "L", false, 0, "")
+	}
+
+	doc.SetXY(20, pageH-18)
+	doc.font("", 8, textMuted)
+	doc.text(0, 5, fmt.Sprintf("Project %s - updated %s", p.ID, p.UpdatedAt.UTC().Format("2 January 2006")), "L")
+
+	out, err := doc.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render fact sheet: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,116 @@
+// Package siwe builds and checks EIP-4361 "Sign-In with Ethereum" messages,
+// which prove that the holder of a wallet's key agreed to link it.
+package siwe
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	version = "1"
+
+	nonceAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	nonceLength   = 17
+)
+
+var (
+	ErrInvalidSignature = errors.New("signature is not a valid ethereum signature")
+	ErrAddressMismatch  = errors.New("message was not signed by the expected address")
+)
+
+// Message is an EIP-4361 message. Only the fields the backend issues are
+// supported.
+type Message struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	ChainID        int
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime time.Time
+}
+
+// String renders the message in the EIP-4361 text format that wallets show
+// to the user and sign.
+func (m Message) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s wants you to sign in with your Ethereum account:\n", m.Domain)
+	fmt.Fprintf(&b, "%s\n\n", m.Address.Hex())
+	if m.Statement != "" {
+		fmt.Fprintf(&b, "%s\n", m.Statement)
+	}
+	fmt.Fprintf(&b, "\nURI: %s\n", m.URI)
+	fmt.Fprintf(&b, "Version: %s\n", version)
+	fmt.Fprintf(&b, "Chain ID: %d\n", m.ChainID)
+	fmt.Fprintf(&b, "Nonce: %s\n", m.Nonce)
+	fmt.Fprintf(&b, "Issued At: %s", m.IssuedAt.UTC().Format(time.RFC3339))
+	if !m.ExpirationTime.IsZero() {
+		fmt.Fprintf(&b, "\nExpiration Time: %s", m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// NewNonce returns a random alphanumeric nonce as required by EIP-4361.
+func NewNonce() (string, error) {
+	buf := make([]byte, nonceLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, c := range buf {
+		buf[i] = nonceAlphabet[int(c)%len(nonceAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// Recover returns the address that produced signature over message with
+// personal_sign (EIP-191). The signature is the 65-byte hex string wallets
+// return; v may be 0/1 or 27/28.
+func Recover(message string, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return common.Address{}, ErrInvalidSignature
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Verify checks that message was signed by address.
+func Verify(message string, signature string, address common.Address) error {
+	signer, err := Recover(message, signature)
+	if err != nil {
+		return err
+	}
+	if signer != address {
+		return ErrAddressMismatch
+	}
+	return nil
+}
+
+// Sign signs message with key the way a wallet's personal_sign does. It is
+// used by tests and local scripts.
+func Sign(message string, key *ecdsa.PrivateKey) (string, error) {
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		return "", err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig), nil
+}
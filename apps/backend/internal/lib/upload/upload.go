@@ -3,11 +3,12 @@ package upload
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
+	"net/http"
 
-	// "net/http"
 	"path"
 	"strings"
 	"time"
@@ -16,8 +17,13 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
+// ErrObjectNotFound is returned by Head when no object exists at the key.
+var ErrObjectNotFound = errors.New("object not found")
+
 type Client struct {
 	s3Client   *s3.Client
 	bucketName string
@@ -36,7 +42,11 @@ func NewClient(ctx context.Context, endpoint, accessKey, secretKey, bucketName,
 	}
 
 	return &Client{
-		s3Client:   s3.NewFromConfig(cfg),
+		// Path-style requests work on R2 and are the only kind a local MinIO
+		// at e.g. http://localhost:9000 understands.
+		s3Client: s3.NewFromConfig(cfg, func(o *s3.Options) {
+			o.UsePathStyle = true
+		}),
 		bucketName: bucketName,
 		publicURL:  strings.TrimRight(publicURL, "/"), // Ensure no trailing slash
 	}, nil
@@ -53,19 +63,20 @@ type UploadParams struct {
 
 // Upload streams the file to the bucket and returns the Viewable Public URL
 func (c *Client) Upload(ctx context.Context, params UploadParams) (string, error) {
-	// 1. Generate new filename: datetime-user_id-random_6_numbers
-	ext := path.Ext(params.Filename)
-	timestamp := time.Now().Format("20060102150405")
-
-	randNumBig, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	key, err := c.Store(ctx, params)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate random number: %w", err)
+		return "", err
 	}
+	return c.URL(key), nil
+}
 
-	newFilename := fmt.Sprintf("%s-%s-%06d%s", timestamp, params.UserID, randNumBig, ext)
-
-	// 2. Smart Path Joining: Prevents double slashes (projects//image.jpg)
-	key := path.Join(params.Folder, newFilename)
+// Store streams the file to the bucket and returns its object key, which
+// Delete takes.
+func (c *Client) Store(ctx context.Context, params UploadParams) (string, error) {
+	key, err := newKey(params.Folder, params.Filename, params.UserID)
+	if err != nil {
+		return "", err
+	}
 
 	// 3. Auto-Detect Content Type if missing (Optional safeguard)
 	if params.ContentType == "" {
@@ -87,7 +98,138 @@ func (c *Client) Upload(ctx context.Context, params UploadParams) (string, error
 	if err != nil {
 		return "", fmt.Errorf("failed to upload to storage: %w", err)
 	}
-	return fmt.Sprintf("%s/%s", c.publicURL, key), nil
+	return key, nil
+}
+
+// newKey names a new object in folder: datetime-user_id-random_6_numbers,
+// keeping the extension of filename.
+func newKey(folder, filename, userID string) (string, error) {
+	ext := path.Ext(filename)
+	timestamp := time.Now().Format(keyTimeLayout)
+
+	randNumBig, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate random number: %w", err)
+	}
+
+	newFilename := fmt.Sprintf("%s-%s-%06d%s", timestamp, userID, randNumBig, ext)
+
+	// Smart Path Joining: Prevents double slashes (projects//image.jpg)
+	return path.Join(folder, newFilename), nil
+}
+
+const keyTimeLayout = "20060102150405"
+
+// IssuedTo reports whether key names an object in folder generated for
+// userID by Store or PresignPut.
+func IssuedTo(key, folder, userID string) bool {
+	if userID == "" || path.Dir(key) != folder {
+		return false
+	}
+	name := path.Base(key)
+	if len(name) <= len(keyTimeLayout) || name[len(keyTimeLayout)] != '-' {
+		return false
+	}
+	return strings.HasPrefix(name[len(keyTimeLayout)+1:], userID+"-")
+}
+
+type PresignParams struct {
+	Folder      string        // Folder the object is created in
+	Filename    string        // Original filename; only its extension is kept
+	UserID      string        // User ID for filename generation
+	ContentType string        // Content type the upload must be sent with
+	Size        int64         // Exact size in bytes the upload must have
+	Expires     time.Duration // How long the URL stays valid
+}
+
+// PresignedUpload is a URL the client PUTs the file to directly, together
+// with the headers it must send unchanged.
+type PresignedUpload struct {
+	Key       string            `json:"key"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+// PresignPut issues a URL that lets the holder create one new object in
+// params.Folder. The content type and length are part of the signature, so
+// the bucket rejects uploads of any other type or size.
+func (c *Client) PresignPut(ctx context.Context, params PresignParams) (*PresignedUpload, error) {
+	key, err := newKey(params.Folder, params.Filename, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	presigner := s3.NewPresignClient(c.s3Client)
+	req, err := presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(c.bucketName),
+		Key:           aws.String(key),
+		ContentType:   aws.String(params.ContentType),
+		ContentLength: aws.Int64(params.Size),
+	}, s3.WithPresignExpires(params.Expires))
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload: %w", err)
+	}
+
+	headers := make(map[string]string, len(req.SignedHeader))
+	for name, values := range req.SignedHeader {
+		// Browsers set these themselves and refuse to have them set
+		if strings.EqualFold(name, "Host") || strings.EqualFold(name, "Content-Length") {
+			continue
+		}
+		headers[name] = strings.Join(values, ",")
+	}
+
+	return &PresignedUpload{
+		Key:       key,
+		URL:       req.URL,
+		Method:    req.Method,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(params.Expires),
+	}, nil
+}
+
+// ObjectInfo is what the bucket reports about a stored object.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// Head looks up the object at key, returning ErrObjectNotFound if there is
+// none.
+func (c *Client) Head(ctx context.Context, key string) (*ObjectInfo, error) {
+	out, err := c.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(c.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		var respErr *smithyhttp.ResponseError
+		switch {
+		case errors.As(err, &notFound),
+			errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound:
+			return nil, ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to look up object: %w", err)
+	}
+
+	return &ObjectInfo{
+		Size:        aws.ToInt64(out.ContentLength),
+		ContentType: aws.ToString(out.ContentType),
+	}, nil
+}
+
+// URL is the viewable public URL of the object at key
+func (c *Client) URL(key string) string {
+	return fmt.Sprintf("%s/%s", c.publicURL, key)
+}
+
+// Key is the inverse of URL: the key of the object served at url, or false
+// when url is not in this bucket
+func (c *Client) Key(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, c.publicURL+"/")
+	return key, ok && key != ""
 }
 
 // Delete removes an object from the bucket by its key
@@ -30,4 +30,8 @@ type PaginatedResponse[T interface{}] struct {
 	Limit      int `json:"limit"`
 	Total      int `json:"total"`
 	TotalPages int `json:"totalPages"`
+
+	// NextCursor is set on cursor-paginated responses while there are more
+	// items.
+	NextCursor *string `json:"nextCursor,omitempty"`
 }
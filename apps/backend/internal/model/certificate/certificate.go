@@ -0,0 +1,25 @@
+package certificate
+
+import (
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+)
+
+// Certificate records a retirement of project credits. TxHash is the
+// transaction that burnt or locked the tokens, and AmountRetired is in tonnes.
+// PDFURL stays nil until the certificate document has been rendered.
+type Certificate struct {
+	model.Base
+
+	OwnerID   string    `json:"ownerId" db:"owner_id"`
+	ProjectID uuid.UUID `json:"projectId" db:"project_id"`
+
+	TxHash        string  `json:"txHash" db:"tx_hash"`
+	BlockNumber   uint64  `json:"blockNumber" db:"block_number"`
+	WalletAddress string  `json:"walletAddress" db:"wallet_address"`
+	AmountRetired float64 `json:"amountRetired" db:"amount_retired"`
+
+	BeneficiaryName  string  `json:"beneficiaryName" db:"beneficiary_name"`
+	RetirementReason *string `json:"retirementReason" db:"retirement_reason"`
+	PDFURL           *string `json:"pdfUrl" db:"pdf_url"`
+}
@@ -0,0 +1,31 @@
+package certificate
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// ------------------------------------------------------------
+// Retire
+// ------------------------------------------------------------
+
+type RetirePayload struct {
+	TxHash string `json:"txHash" validate:"required,len=66,startswith=0x,hexadecimal"`
+	// BeneficiaryName is published on the certificate. It defaults to the
+	// retiring wallet's address.
+	BeneficiaryName  *string `json:"beneficiaryName" validate:"omitempty,min=1,max=200"`
+	RetirementReason *string `json:"retirementReason" validate:"omitempty,max=1000"`
+}
+
+func (p *RetirePayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+// List
+// ------------------------------------------------------------
+
+type GetCertificatesQuery struct {
+	Page  int
+	Limit int
+}
@@ -0,0 +1,91 @@
+package certificate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+)
+
+type ProofStatus string
+
+const (
+	// ProofStatusConfirmed means the retirement transaction is mined and
+	// succeeded.
+	ProofStatusConfirmed ProofStatus = "CONFIRMED"
+	// ProofStatusNotFound means the chain does not know the transaction, for
+	// example after a reorganisation dropped it.
+	ProofStatusNotFound ProofStatus = "NOT_FOUND"
+	// ProofStatusReverted means the transaction is mined but failed.
+	ProofStatusReverted ProofStatus = "REVERTED"
+	// ProofStatusUnavailable means the chain could not be queried.
+	ProofStatusUnavailable ProofStatus = "UNAVAILABLE"
+)
+
+// Verification is the public view of a certificate that third parties check a
+// certificate document against. It leaves out the owner's account.
+type Verification struct {
+	ID               uuid.UUID `json:"id"`
+	BeneficiaryName  string    `json:"beneficiaryName"`
+	AmountRetired    float64   `json:"amountRetired"`
+	RetirementReason *string   `json:"retirementReason"`
+	WalletAddress    string    `json:"walletAddress"`
+	TxHash           string    `json:"txHash"`
+	RetiredAt        time.Time `json:"retiredAt"`
+	PDFURL           *string   `json:"pdfUrl"`
+
+	Project ProjectSummary `json:"project"`
+	Proof   OnChainProof   `json:"proof"`
+
+	// ContentHash is printed on the certificate document; a document whose
+	// details do not hash to it has been altered. Signature is the platform's
+	// EIP-191 signature of ContentHash by Signer, and is omitted when the
+	// chain client is not configured.
+	ContentHash string `json:"contentHash"`
+	Signature   string `json:"signature,omitempty"`
+	Signer      string `json:"signer,omitempty"`
+}
+
+type ProjectSummary struct {
+	ID              uuid.UUID             `json:"id"`
+	Title           string                `json:"title"`
+	Status          project.ProjectStatus `json:"status"`
+	LocationLat     float64               `json:"locationLat"`
+	LocationLng     float64               `json:"locationLng"`
+	TokenSymbol     *string               `json:"tokenSymbol"`
+	ContractAddress *string               `json:"contractAddress"`
+}
+
+type OnChainProof struct {
+	Status        ProofStatus `json:"status"`
+	BlockNumber   uint64      `json:"blockNumber,omitempty"`
+	Confirmations uint64      `json:"confirmations,omitempty"`
+}
+
+// ContentHash is the hex SHA-256 of the certificate details that are printed
+// on the document, one "key=value" per line in a fixed order.
+func ContentHash(c Certificate) string {
+	var b strings.Builder
+	fields := [][2]string{
+		{"id", c.ID.String()},
+		{"project_id", c.ProjectID.String()},
+		{"beneficiary", c.BeneficiaryName},
+		{"tonnes", strconv.FormatFloat(c.AmountRetired, 'f', -1, 64)},
+		{"wallet", c.WalletAddress},
+		{"tx_hash", c.TxHash},
+		{"retired_at", c.CreatedAt.UTC().Format(time.RFC3339)},
+	}
+	for _, f := range fields {
+		b.WriteString(f[0])
+		b.WriteByte('=')
+		b.WriteString(f[1])
+		b.WriteByte('\n')
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return "0x" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,48 @@
+package listing
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+// Create
+// ------------------------------------------------------------
+
+type CreateListingPayload struct {
+	ProjectID         uuid.UUID `json:"projectId" validate:"required"`
+	PricePerToken     float64   `json:"pricePerToken" validate:"required,gt=0"`
+	QuantityAvailable float64   `json:"quantityAvailable" validate:"required,gt=0"`
+}
+
+func (p *CreateListingPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+// Update
+// ------------------------------------------------------------
+
+type UpdateListingPayload struct {
+	PricePerToken     *float64 `json:"pricePerToken" validate:"omitempty,gt=0"`
+	QuantityAvailable *float64 `json:"quantityAvailable" validate:"omitempty,gte=0"`
+}
+
+func (p *UpdateListingPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+// Browse
+// ------------------------------------------------------------
+
+// GetListingsQuery pages through open listings by price, or newest first by
+// cursor when Cursor is set; an empty cursor is the first page.
+type GetListingsQuery struct {
+	ProjectID *uuid.UUID
+	Page      int
+	Limit     int
+	Cursor    *string
+}
@@ -0,0 +1,21 @@
+package listing
+
+import (
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+)
+
+// Listing offers a seller's project tokens on the marketplace. Price and
+// quantity are per whole token (one tonne of CO2e).
+type Listing struct {
+	model.Base
+
+	ProjectID        uuid.UUID `json:"projectId" db:"project_id"`
+	ListingIDOnChain *string   `json:"listingIdOnChain" db:"listing_id_on_chain"`
+	SellerAddress    string    `json:"sellerAddress" db:"seller_address"`
+
+	PricePerToken     float64 `json:"pricePerToken" db:"price_per_token"`
+	QuantityAvailable float64 `json:"quantityAvailable" db:"quantity_available"`
+
+	Active bool `json:"active" db:"active"`
+}
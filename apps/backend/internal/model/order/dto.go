@@ -0,0 +1,52 @@
+package order
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+// Place
+// ------------------------------------------------------------
+
+type PlaceOrderPayload struct {
+	ListingID uuid.UUID `json:"listingId" validate:"required"`
+	Quantity  float64   `json:"quantity" validate:"required,gt=0"`
+}
+
+func (p *PlaceOrderPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+// Pay / Settle
+// ------------------------------------------------------------
+
+type PayOrderPayload struct {
+	PaymentReference string `json:"paymentReference" validate:"required,max=255"`
+}
+
+func (p *PayOrderPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+type SettleOrderPayload struct {
+	TxHash string `json:"txHash" validate:"required,len=66,startswith=0x,hexadecimal"`
+}
+
+func (p *SettleOrderPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+// List
+// ------------------------------------------------------------
+
+type GetOrdersQuery struct {
+	Status *OrderStatus
+	Page   int
+	Limit  int
+}
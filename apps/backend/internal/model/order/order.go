@@ -0,0 +1,44 @@
+package order
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusReserved  OrderStatus = "RESERVED"
+	OrderStatusPaid      OrderStatus = "PAID"
+	OrderStatusSettled   OrderStatus = "SETTLED"
+	OrderStatusCancelled OrderStatus = "CANCELLED"
+	OrderStatusExpired   OrderStatus = "EXPIRED"
+)
+
+// Order is a buyer's purchase against a listing. While RESERVED it holds
+// Quantity out of the listing until ExpiresAt.
+type Order struct {
+	model.Base
+
+	ListingID uuid.UUID `json:"listingId" db:"listing_id"`
+	ProjectID uuid.UUID `json:"projectId" db:"project_id"`
+	BuyerID   string    `json:"buyerId" db:"buyer_id"`
+
+	BuyerAddress  string `json:"buyerAddress" db:"buyer_address"`
+	SellerAddress string `json:"sellerAddress" db:"seller_address"`
+
+	Quantity      float64 `json:"quantity" db:"quantity"`
+	PricePerToken float64 `json:"pricePerToken" db:"price_per_token"`
+	TotalPrice    float64 `json:"totalPrice" db:"total_price"`
+
+	Status    OrderStatus `json:"status" db:"status"`
+	ExpiresAt time.Time   `json:"expiresAt" db:"expires_at"`
+
+	PaymentReference *string `json:"paymentReference" db:"payment_reference"`
+	SettlementTxHash *string `json:"settlementTxHash" db:"settlement_tx_hash"`
+
+	PaidAt    *time.Time `json:"paidAt" db:"paid_at"`
+	SettledAt *time.Time `json:"settledAt" db:"settled_at"`
+}
@@ -0,0 +1,125 @@
+package project
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+)
+
+// DocumentType classifies a file attached to a project.
+type DocumentType string
+
+const (
+	DocumentImage            DocumentType = "IMAGE"
+	DocumentAuditReport      DocumentType = "AUDIT_REPORT"
+	DocumentLandTitle        DocumentType = "LAND_TITLE"
+	DocumentMethodology      DocumentType = "METHODOLOGY"
+	DocumentMonitoringReport DocumentType = "MONITORING_REPORT"
+)
+
+// Upload limits per document type.
+const (
+	MaxImageSize    = 10 << 20
+	MaxDocumentSize = 25 << 20
+)
+
+// Reviewed reports whether documents of type t are part of what admins
+// review. Those are frozen once the project is submitted; monitoring reports
+// keep coming in for the life of a project.
+func (t DocumentType) Reviewed() bool {
+	return t != DocumentMonitoringReport
+}
+
+// Folder is the bucket folder documents of type t are stored in.
+func (t DocumentType) Folder() string {
+	if t == DocumentImage {
+		return "projects"
+	}
+	return "documents"
+}
+
+// MaxSize is the largest file accepted for type t, in bytes.
+func (t DocumentType) MaxSize() int64 {
+	if t == DocumentImage {
+		return MaxImageSize
+	}
+	return MaxDocumentSize
+}
+
+// Accepts reports whether a file of contentType may be attached as type t:
+// images are JPEG, PNG or WebP; other documents are PDFs or scans.
+func (t DocumentType) Accepts(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "image/jpeg", "image/png", "image/webp":
+		return true
+	case "application/pdf":
+		return t != DocumentImage
+	default:
+		return false
+	}
+}
+
+// Document is a file attached to a project.
+type Document struct {
+	model.Base
+
+	ProjectID   uuid.UUID    `json:"projectId" db:"project_id"`
+	Type        DocumentType `json:"type" db:"type"`
+	ObjectKey   string       `json:"-" db:"object_key"`
+	URL         string       `json:"url" db:"url"`
+	FileName    string       `json:"fileName" db:"file_name"`
+	ContentType *string      `json:"contentType" db:"content_type"`
+	SizeBytes   *int64       `json:"sizeBytes" db:"size_bytes"`
+	UploadedBy  string       `json:"uploadedBy" db:"uploaded_by"`
+	Position    int          `json:"position" db:"position"`
+}
+
+// ------------------------------------------------------------
+// Reorder
+// ------------------------------------------------------------
+
+// ReorderDocumentsPayload lists every document of one type in their new
+// order.
+type ReorderDocumentsPayload struct {
+	Type DocumentType `json:"type" validate:"required,oneof=IMAGE AUDIT_REPORT LAND_TITLE METHODOLOGY MONITORING_REPORT"`
+	IDs  []uuid.UUID  `json:"ids" validate:"required,min=1,max=100"`
+}
+
+func (p *ReorderDocumentsPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ------------------------------------------------------------
+// Direct uploads
+// ------------------------------------------------------------
+
+// PresignDocumentPayload describes a file the client is about to PUT to the
+// bucket itself instead of sending it through the API.
+type PresignDocumentPayload struct {
+	Type        DocumentType `json:"type" validate:"required,oneof=IMAGE AUDIT_REPORT LAND_TITLE METHODOLOGY MONITORING_REPORT"`
+	FileName    string       `json:"fileName" validate:"required,max=255"`
+	ContentType string       `json:"contentType" validate:"required,max=255"`
+	Size        int64        `json:"size" validate:"required,min=1"`
+}
+
+func (p *PresignDocumentPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// ConfirmDocumentPayload attaches an object uploaded through a presigned URL
+// to a project.
+type ConfirmDocumentPayload struct {
+	Type     DocumentType `json:"type" validate:"required,oneof=IMAGE AUDIT_REPORT LAND_TITLE METHODOLOGY MONITORING_REPORT"`
+	Key      string       `json:"key" validate:"required,max=1024"`
+	FileName string       `json:"fileName" validate:"required,max=255"`
+}
+
+func (p *ConfirmDocumentPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
@@ -1,13 +1,16 @@
 package project
 
 import (
+	"time"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
 type ProjectWithSupplier struct {
 	Project
-	SupplierEmail string `json:"supplierEmail"`
+	SupplierEmail string          `json:"supplierEmail"`
+	Warnings      []ReviewWarning `json:"warnings"`
 }
 
 // ------------------------------------------------------------
@@ -35,15 +38,13 @@ func (p *CreateProjectPayload) Validate() error {
 // ------------------------------------------------------------
 
 type UpdateProjectPayload struct {
-	ID              uuid.UUID      `param:"id" validate:"required,uuid"`
-	Title           *string        `json:"title" validate:"omitempty,min=3,max=150"`
-	Description     *string        `json:"description" validate:"omitempty,min=10"`
-	ContractAddress *string        `json:"contractAddress" validate:"omitempty,eth_addr"`
-	LocationLat     *float64       `json:"locationLat" validate:"omitempty,latitude"`
-	LocationLng     *float64       `json:"locationLng" validate:"omitempty,longitude"`
-	Area            *float64       `json:"area" validate:"omitempty,gt=0"`
-	Status          *ProjectStatus `json:"status" validate:"omitempty,oneof=DRAFT PENDING APPROVED DEPLOYED REJECTED"`
-	CarbonAmount    *float64       `json:"carbonAmount" validate:"omitempty,gt=0"`
+	ID           uuid.UUID `param:"id" validate:"required,uuid"`
+	Title        *string   `json:"title" validate:"omitempty,min=3,max=150"`
+	Description  *string   `json:"description" validate:"omitempty,min=10"`
+	LocationLat  *float64  `json:"locationLat" validate:"omitempty,latitude"`
+	LocationLng  *float64  `json:"locationLng" validate:"omitempty,longitude"`
+	Area         *float64  `json:"area" validate:"omitempty,gt=0"`
+	CarbonAmount *float64  `json:"carbonAmount" validate:"omitempty,gt=0"`
 }
 
 func (p *UpdateProjectPayload) Validate() error {
@@ -55,13 +56,29 @@ func (p *UpdateProjectPayload) Validate() error {
 // Query (Get Many)
 // ------------------------------------------------------------
 
+// GetProjectsQuery filters and orders a project listing. Ranges are
+// inclusive; created_at bounds are RFC 3339 timestamps. Sorting by
+// "relevance" only applies together with Search.
 type GetProjectsQuery struct {
 	Page       *int           `query:"page" validate:"omitempty,min=1"`
 	Limit      *int           `query:"limit" validate:"omitempty,min=1,max=100"`
-	Sort       *string        `query:"sort" validate:"omitempty,oneof=created_at title"`
+	Sort       *string        `query:"sort" validate:"omitempty,oneof=created_at title price_per_tonne carbon_amount relevance"`
 	Order      *string        `query:"order" validate:"omitempty,oneof=asc desc"`
 	Status     *ProjectStatus `query:"status" validate:"omitempty,oneof=DRAFT PENDING APPROVED DEPLOYED REJECTED"`
 	SupplierID *string        `query:"supplierId" validate:"omitempty"`
+
+	Search        *string    `query:"search" validate:"omitempty,min=1,max=200"`
+	MinPrice      *float64   `query:"minPrice" validate:"omitempty,gte=0"`
+	MaxPrice      *float64   `query:"maxPrice" validate:"omitempty,gte=0"`
+	MinCarbon     *float64   `query:"minCarbon" validate:"omitempty,gte=0"`
+	MaxCarbon     *float64   `query:"maxCarbon" validate:"omitempty,gte=0"`
+	CreatedAfter  *time.Time `query:"createdAfter"`
+	CreatedBefore *time.Time `query:"createdBefore"`
+
+	// Cursor switches to keyset pagination, newest first; it is only
+	// supported with the default created_at sort. An empty cursor is the
+	// first page.
+	Cursor *string `query:"cursor" validate:"omitempty,max=200"`
 }
 
 func (q *GetProjectsQuery) Validate() error {
@@ -103,6 +120,32 @@ func (p *DeleteProjectPayload) Validate() error {
 	return validate.Struct(p)
 }
 
+// ------------------------------------------------------------
+// Review
+// ------------------------------------------------------------
+
+type RejectProjectPayload struct {
+	Code    RejectionReason `json:"code" validate:"required,oneof=INCOMPLETE_DOCUMENTATION UNVERIFIABLE_CARBON_CLAIM LOCATION_MISMATCH AREA_MISMATCH DUPLICATE_PROJECT OTHER"`
+	Message string          `json:"message" validate:"required,min=10,max=2000"`
+	Field   *string         `json:"field" validate:"omitempty,oneof=title description imageUrl auditReportUrl locationLat locationLng area carbonAmount pricePerTonne"`
+}
+
+func (p *RejectProjectPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+type CreateReviewCommentPayload struct {
+	Body     string     `json:"body" validate:"required,min=1,max=2000"`
+	Field    *string    `json:"field" validate:"omitempty,oneof=title description imageUrl auditReportUrl locationLat locationLng area carbonAmount pricePerTonne"`
+	ParentID *uuid.UUID `json:"parentId" validate:"omitempty"`
+}
+
+func (p *CreateReviewCommentPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
 // ------------------------------------------------------------
 // Create Project Request
 // ------------------------------------------------------------
@@ -0,0 +1,195 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// ------------------------------------------------------------
+// GeoJSON
+// ------------------------------------------------------------
+
+// FeatureCollection is a GeoJSON (RFC 7946) collection of project points,
+// ready to hand to a map.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string            `json:"type"`
+	ID         uuid.UUID         `json:"id"`
+	Geometry   Point             `json:"geometry"`
+	Properties FeatureProperties `json:"properties"`
+}
+
+// Point is a GeoJSON point; coordinates are [longitude, latitude].
+type Point struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// FeatureProperties is what a map marker shows for a project.
+type FeatureProperties struct {
+	Title         string        `json:"title"`
+	Status        ProjectStatus `json:"status"`
+	ImageURL      string        `json:"imageUrl"`
+	Area          float64       `json:"area"`
+	CarbonAmount  float64       `json:"carbonAmount"`
+	PricePerTonne float64       `json:"pricePerTonne"`
+	TokenSymbol   *string       `json:"tokenSymbol,omitempty"`
+	DistanceKm    *float64      `json:"distanceKm,omitempty"`
+}
+
+// ProjectWithDistance is a project found by a radius search.
+type ProjectWithDistance struct {
+	Project
+	DistanceKm float64 `json:"distanceKm"`
+}
+
+// NewFeature returns p as a GeoJSON point feature.
+func NewFeature(p Project) Feature {
+	return Feature{
+		Type: "Feature",
+		ID:   p.ID,
+		Geometry: Point{
+			Type:        "Point",
+			Coordinates: [2]float64{p.LocationLng, p.LocationLat},
+		},
+		Properties: FeatureProperties{
+			Title:         p.Title,
+			Status:        p.Status,
+			ImageURL:      p.ImageURL,
+			Area:          p.Area,
+			CarbonAmount:  p.CarbonAmount,
+			PricePerTonne: p.PricePerTonne,
+			TokenSymbol:   p.TokenSymbol,
+		},
+	}
+}
+
+// NewFeatureCollection wraps features; it is never null so maps can always
+// iterate it.
+func NewFeatureCollection(features []Feature) *FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return &FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// BoundaryFeature is a project's boundary polygon as a GeoJSON feature.
+type BoundaryFeature struct {
+	Type       string             `json:"type"`
+	ID         uuid.UUID          `json:"id"`
+	Geometry   json.RawMessage    `json:"geometry"`
+	Properties BoundaryProperties `json:"properties"`
+}
+
+// BoundaryProperties compares the measured area of a boundary with the area
+// the supplier declared.
+type BoundaryProperties struct {
+	AreaHa         float64 `json:"areaHa"`
+	DeclaredAreaHa float64 `json:"declaredAreaHa"`
+	AreaMismatch   bool    `json:"areaMismatch"`
+}
+
+// NewBoundaryFeature returns the boundary geometry of p as a GeoJSON feature.
+func NewBoundaryFeature(p Project, geometry []byte) BoundaryFeature {
+	f := BoundaryFeature{
+		Type:     "Feature",
+		ID:       p.ID,
+		Geometry: geometry,
+		Properties: BoundaryProperties{
+			DeclaredAreaHa: p.Area,
+			AreaMismatch:   p.AreaMismatch,
+		},
+	}
+	if p.BoundaryAreaHa != nil {
+		f.Properties.AreaHa = *p.BoundaryAreaHa
+	}
+	return f
+}
+
+// ------------------------------------------------------------
+// Query (Map)
+// ------------------------------------------------------------
+
+// BBox is a bounding box in degrees.
+type BBox struct {
+	MinLng, MinLat, MaxLng, MaxLat float64
+}
+
+// ParseBBox parses "minLng,minLat,maxLng,maxLat", the GeoJSON bbox order.
+// Boxes that cross the antimeridian are not supported.
+func ParseBBox(raw string) (BBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return BBox{}, errors.New("bbox must be minLng,minLat,maxLng,maxLat")
+	}
+
+	var v [4]float64
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BBox{}, errors.New("bbox must contain four numbers")
+		}
+		v[i] = f
+	}
+
+	b := BBox{MinLng: v[0], MinLat: v[1], MaxLng: v[2], MaxLat: v[3]}
+	if b.MinLng < -180 || b.MaxLng > 180 || b.MinLat < -90 || b.MaxLat > 90 {
+		return BBox{}, errors.New("bbox is outside the valid longitude and latitude range")
+	}
+	if b.MinLng > b.MaxLng || b.MinLat > b.MaxLat {
+		return BBox{}, errors.New("bbox minimums must not exceed its maximums")
+	}
+	return b, nil
+}
+
+// GetProjectsInBBoxQuery finds projects inside a map viewport.
+type GetProjectsInBBoxQuery struct {
+	BBox  string `query:"bbox" validate:"required"`
+	Limit *int   `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+func (q *GetProjectsInBBoxQuery) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Limit == nil {
+		defaultLimit := 200
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
+
+// GetProjectsNearQuery finds projects within RadiusKm of a point, nearest
+// first.
+type GetProjectsNearQuery struct {
+	Lat      *float64 `query:"lat" validate:"required,latitude"`
+	Lng      *float64 `query:"lng" validate:"required,longitude"`
+	RadiusKm float64  `query:"radiusKm" validate:"required,gt=0,max=1000"`
+	Limit    *int     `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+func (q *GetProjectsNearQuery) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	if q.Limit == nil {
+		defaultLimit := 200
+		q.Limit = &defaultLimit
+	}
+
+	return nil
+}
@@ -27,8 +27,14 @@ type Project struct {
 	CarbonAmount   float64 `json:"carbonAmount" db:"carbon_amount_total"`
 	PricePerTonne  float64 `json:"pricePerTonne" db:"price_per_tonne"`
 
+	// BoundaryAreaHa is the measured area of the uploaded boundary, if any.
+	// AreaMismatch is set when it and Area differ by more than 10%.
+	BoundaryAreaHa *float64 `json:"boundaryAreaHa" db:"boundary_area_ha"`
+	AreaMismatch   bool     `json:"areaMismatch" db:"area_mismatch"`
+
 	ContractAddress *string `json:"contractAddress" db:"contract_address"`
 	TokenSymbol     *string `json:"tokenSymbol" db:"token_symbol"`
+	DeployTxHash    *string `json:"deployTxHash" db:"deploy_tx_hash"`
 
 	Status ProjectStatus `json:"status" db:"status"`
 }
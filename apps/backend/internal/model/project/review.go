@@ -0,0 +1,177 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+)
+
+// RejectionReason classifies why an admin rejected a project.
+type RejectionReason string
+
+const (
+	RejectionIncompleteDocumentation RejectionReason = "INCOMPLETE_DOCUMENTATION"
+	RejectionUnverifiableCarbonClaim RejectionReason = "UNVERIFIABLE_CARBON_CLAIM"
+	RejectionLocationMismatch        RejectionReason = "LOCATION_MISMATCH"
+	RejectionAreaMismatch            RejectionReason = "AREA_MISMATCH"
+	RejectionDuplicateProject        RejectionReason = "DUPLICATE_PROJECT"
+	RejectionOther                   RejectionReason = "OTHER"
+)
+
+// Label is the reason in words, e.g. "Area mismatch".
+func (r RejectionReason) Label() string {
+	words := strings.ToLower(strings.ReplaceAll(string(r), "_", " "))
+	if words == "" {
+		return ""
+	}
+	return strings.ToUpper(words[:1]) + words[1:]
+}
+
+// StatusReason explains a status change. Code is only set for rejections.
+type StatusReason struct {
+	Code    *RejectionReason
+	Message string
+}
+
+// Reviewable reports whether review comments may be posted on a project in
+// status s.
+func (s ProjectStatus) Reviewable() bool {
+	return s == ProjectStatusPending || s == ProjectStatusRejected
+}
+
+// ReviewComment is feedback on a project under review. Top-level comments
+// start a thread and carry their replies; Field optionally anchors the
+// comment to a project attribute such as "area".
+type ReviewComment struct {
+	model.Base
+
+	ProjectID  uuid.UUID     `json:"projectId" db:"project_id"`
+	ParentID   *uuid.UUID    `json:"parentId" db:"parent_id"`
+	AuthorID   string        `json:"authorId" db:"author_id"`
+	AuthorRole user.UserRole `json:"authorRole" db:"author_role"`
+	Field      *string       `json:"field" db:"field"`
+	Body       string        `json:"body" db:"body"`
+
+	Replies []ReviewComment `json:"replies,omitempty"`
+}
+
+// ThreadComments nests replies under their top-level comment. comments must
+// be ordered oldest first; threads and replies keep that order.
+func ThreadComments(comments []ReviewComment) []ReviewComment {
+	index := map[uuid.UUID]int{}
+	threads := []ReviewComment{}
+
+	for _, c := range comments {
+		if c.ParentID == nil {
+			index[c.ID] = len(threads)
+			threads = append(threads, c)
+		}
+	}
+	for _, c := range comments {
+		if c.ParentID == nil {
+			continue
+		}
+		if i, ok := index[*c.ParentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, c)
+		}
+	}
+	return threads
+}
+
+// ProjectDetail is a project together with its review feedback, which is only
+// filled in for the supplier and admins.
+type ProjectDetail struct {
+	Project
+	ReviewComments []ReviewComment `json:"reviewComments,omitempty"`
+}
+
+// ReviewWarningKind is the reason a submitted project may double count land
+// already on the marketplace.
+type ReviewWarningKind string
+
+const (
+	// WarningBoundaryOverlap: the boundaries share land.
+	WarningBoundaryOverlap ReviewWarningKind = "BOUNDARY_OVERLAP"
+	// WarningLocationOverlap: the locations are close together or one lies
+	// within the other's boundary.
+	WarningLocationOverlap ReviewWarningKind = "LOCATION_OVERLAP"
+	// WarningSimilarTitle: the titles are near-identical.
+	WarningSimilarTitle ReviewWarningKind = "SIMILAR_TITLE"
+)
+
+// Thresholds for raising review warnings.
+const (
+	// MinOverlapHa ignores boundaries that merely touch.
+	MinOverlapHa = 0.01
+	// NearbyDistanceM is how close two locations must be to be flagged.
+	NearbyDistanceM = 500.0
+	// MinTitleSimilarity is the pg_trgm similarity above which titles are
+	// flagged.
+	MinTitleSimilarity = 0.5
+)
+
+// ReviewWarning flags a reviewed project a submitted one may duplicate.
+type ReviewWarning struct {
+	ProjectID            uuid.UUID         `json:"projectId" db:"project_id"`
+	ConflictingProjectID uuid.UUID         `json:"conflictingProjectId" db:"conflicting_project_id"`
+	ConflictingTitle     string            `json:"conflictingTitle"`
+	ConflictingStatus    ProjectStatus     `json:"conflictingStatus"`
+	Kind                 ReviewWarningKind `json:"kind" db:"kind"`
+	Message              string            `json:"message" db:"message"`
+	CreatedAt            time.Time         `json:"createdAt" db:"created_at"`
+}
+
+// Conflict is a reviewed project that shares land or a title with a
+// submitted one, as measured by the database.
+type Conflict struct {
+	ProjectID uuid.UUID
+	Title     string
+	Status    ProjectStatus
+
+	// OverlapHa is the shared area when both projects have a boundary.
+	OverlapHa *float64
+	// DistanceM is the distance between the two locations.
+	DistanceM *float64
+	// Contains is set when either location lies within the other's
+	// boundary.
+	Contains        bool
+	TitleSimilarity float64
+}
+
+// Warnings turns a conflict with another project into the warnings shown to
+// reviewers of projectID. A location warning is left out when the boundaries
+// already overlap.
+func (c Conflict) Warnings(projectID uuid.UUID) []ReviewWarning {
+	warning := func(kind ReviewWarningKind, format string, args ...any) ReviewWarning {
+		return ReviewWarning{
+			ProjectID:            projectID,
+			ConflictingProjectID: c.ProjectID,
+			ConflictingTitle:     c.Title,
+			ConflictingStatus:    c.Status,
+			Kind:                 kind,
+			Message:              fmt.Sprintf(format, args...),
+		}
+	}
+
+	var warnings []ReviewWarning
+	switch {
+	case c.OverlapHa != nil && *c.OverlapHa >= MinOverlapHa:
+		warnings = append(warnings, warning(WarningBoundaryOverlap,
+			"Boundary overlaps %.2f ha of %q", *c.OverlapHa, c.Title))
+	case c.Contains:
+		warnings = append(warnings, warning(WarningLocationOverlap,
+			"Location lies within the boundary of %q", c.Title))
+	case c.DistanceM != nil && *c.DistanceM <= NearbyDistanceM:
+		warnings = append(warnings, warning(WarningLocationOverlap,
+			"Location is %.0f m from %q", *c.DistanceM, c.Title))
+	}
+	if c.TitleSimilarity >= MinTitleSimilarity {
+		warnings = append(warnings, warning(WarningSimilarTitle,
+			"Title is %.0f%% similar to %q", c.TitleSimilarity*100, c.Title))
+	}
+	return warnings
+}
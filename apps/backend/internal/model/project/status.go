@@ -0,0 +1,46 @@
+package project
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// transitions is the project lifecycle. Every status change, whether it comes
+// from the supplier, an admin or the chain, must be an edge in this table.
+var transitions = map[ProjectStatus][]ProjectStatus{
+	ProjectStatusDraft:    {ProjectStatusPending},
+	ProjectStatusPending:  {ProjectStatusApproved, ProjectStatusRejected},
+	ProjectStatusApproved: {ProjectStatusDeployed},
+	ProjectStatusRejected: {ProjectStatusDraft, ProjectStatusPending},
+	ProjectStatusDeployed: {},
+}
+
+// CanTransitionTo reports whether a project may move from s to next.
+func (s ProjectStatus) CanTransitionTo(next ProjectStatus) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Editable reports whether the supplier may still change or delete the
+// project, which is the case for as long as it can be (re)submitted.
+func (s ProjectStatus) Editable() bool {
+	return s.CanTransitionTo(ProjectStatusPending)
+}
+
+// StatusChange is one entry of a project's status history. ActorID is nil for
+// transitions made by the system, such as a deployment seen on chain.
+type StatusChange struct {
+	ID         uuid.UUID        `json:"id" db:"id"`
+	ProjectID  uuid.UUID        `json:"projectId" db:"project_id"`
+	ActorID    *string          `json:"actorId" db:"actor_id"`
+	FromStatus ProjectStatus    `json:"fromStatus" db:"from_status"`
+	ToStatus   ProjectStatus    `json:"toStatus" db:"to_status"`
+	ReasonCode *RejectionReason `json:"reasonCode" db:"reason_code"`
+	Reason     *string          `json:"reason" db:"reason"`
+	CreatedAt  time.Time        `json:"createdAt" db:"created_at"`
+}
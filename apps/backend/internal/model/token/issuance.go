@@ -0,0 +1,32 @@
+package token
+
+import (
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+)
+
+type IssuanceStatus string
+
+const (
+	IssuanceStatusPending   IssuanceStatus = "PENDING"
+	IssuanceStatusConfirmed IssuanceStatus = "CONFIRMED"
+	IssuanceStatusFailed    IssuanceStatus = "FAILED"
+)
+
+// Issuance is a mint of verified carbon credits to the supplier's wallet.
+// Tonnes is the carbon amount it represents, Amount the same quantity in token
+// base units (Tonnes * 10^Decimals) as a decimal string.
+type Issuance struct {
+	model.Base
+
+	ProjectID uuid.UUID `json:"projectId" db:"project_id"`
+	IssuedBy  string    `json:"issuedBy" db:"issued_by"`
+
+	RecipientAddress string  `json:"recipientAddress" db:"recipient_address"`
+	Tonnes           float64 `json:"tonnes" db:"tonnes"`
+	Amount           string  `json:"amount" db:"amount"`
+	Decimals         uint8   `json:"decimals" db:"decimals"`
+
+	TxHash *string        `json:"txHash" db:"tx_hash"`
+	Status IssuanceStatus `json:"status" db:"status"`
+}
@@ -0,0 +1,55 @@
+package token
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+)
+
+const (
+	// ZeroAddress is the from/to address of mints and burns.
+	ZeroAddress = "0x0000000000000000000000000000000000000000"
+
+	// RetirementAddress is where credits are locked when retired by transfer
+	// instead of burnt. Nobody holds its key, so tokens sent here are out of
+	// circulation and never count as a holding.
+	RetirementAddress = "0x000000000000000000000000000000000000dEaD"
+)
+
+// Transfer is an indexed ERC20 Transfer of a project token. Amount is in
+// token base units, as a decimal string.
+type Transfer struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+
+	ProjectID       *uuid.UUID `json:"projectId" db:"project_id"`
+	ContractAddress string     `json:"contractAddress" db:"contract_address"`
+
+	FromAddress string `json:"fromAddress" db:"from_address"`
+	ToAddress   string `json:"toAddress" db:"to_address"`
+	Amount      string `json:"amount" db:"amount"`
+
+	TxHash      string `json:"txHash" db:"tx_hash"`
+	LogIndex    uint   `json:"logIndex" db:"log_index"`
+	BlockNumber uint64 `json:"blockNumber" db:"block_number"`
+	BlockHash   string `json:"blockHash" db:"block_hash"`
+}
+
+// Holding is the indexed balance of one address for one project token.
+type Holding struct {
+	model.BaseWithUpdatedAt
+
+	ContractAddress string     `json:"contractAddress" db:"contract_address"`
+	HolderAddress   string     `json:"holderAddress" db:"holder_address"`
+	ProjectID       *uuid.UUID `json:"projectId" db:"project_id"`
+	Balance         string     `json:"balance" db:"balance"`
+}
+
+// Checkpoint is the last block an indexer has fully processed.
+type Checkpoint struct {
+	Name          string    `json:"name" db:"name"`
+	LastBlock     uint64    `json:"lastBlock" db:"last_block"`
+	LastBlockHash string    `json:"lastBlockHash" db:"last_block_hash"`
+	UpdatedAt     time.Time `json:"updatedAt" db:"updated_at"`
+}
@@ -36,6 +36,17 @@ func (p *UpdateUserPayload) Validate() error {
 	return validate.Struct(p)
 }
 
+// UpdateMePayload is what users may change about their own account. Email
+// and role are managed in Clerk.
+type UpdateMePayload struct {
+	WalletAddress *string `json:"walletAddress" validate:"omitempty,eth_addr"`
+}
+
+func (p *UpdateMePayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
 // ------------------------------------------------------------
 // Query (Get Many)
 // ------------------------------------------------------------
@@ -17,9 +17,16 @@ const (
 type User struct {
 	model.Base
 
-	ClerkID       string     `json:"clerkId" db:"clerk_id"`
-	Email         string     `json:"email" db:"email"`
-	WalletAddress *string    `json:"walletAddress" db:"wallet_address"`
-	Role          UserRole   `json:"role" db:"role"`
-	DeletedAt     *time.Time `json:"deletedAt" db:"deleted_at"`
+	ClerkID          string     `json:"clerkId" db:"clerk_id"`
+	Email            string     `json:"email" db:"email"`
+	WalletAddress    *string    `json:"walletAddress" db:"wallet_address"`
+	WalletVerifiedAt *time.Time `json:"walletVerifiedAt" db:"wallet_verified_at"`
+	Role             UserRole   `json:"role" db:"role"`
+	DeletedAt        *time.Time `json:"deletedAt" db:"deleted_at"`
+}
+
+// HasVerifiedWallet reports whether the user proved ownership of their
+// linked wallet.
+func (u *User) HasVerifiedWallet() bool {
+	return u.WalletAddress != nil && u.WalletVerifiedAt != nil
 }
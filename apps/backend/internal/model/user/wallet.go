@@ -0,0 +1,37 @@
+package user
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// WalletChallengePayload asks for a message proving ownership of Address.
+type WalletChallengePayload struct {
+	Address string `json:"address" validate:"required,eth_addr"`
+}
+
+func (p *WalletChallengePayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
+
+// WalletChallenge is the EIP-4361 message the wallet must sign. It can be
+// used once, until ExpiresAt.
+type WalletChallenge struct {
+	Nonce     string    `json:"nonce"`
+	Message   string    `json:"message"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// VerifyWalletPayload answers a challenge with the wallet's personal_sign
+// signature of its message.
+type VerifyWalletPayload struct {
+	Nonce     string `json:"nonce" validate:"required,alphanum"`
+	Signature string `json:"signature" validate:"required,startswith=0x,len=132"`
+}
+
+func (p *VerifyWalletPayload) Validate() error {
+	validate := validator.New()
+	return validate.Struct(p)
+}
@@ -0,0 +1,21 @@
+package user
+
+import "encoding/json"
+
+// Clerk webhook event types that affect local users.
+const (
+	ClerkUserCreated       = "user.created"
+	ClerkUserUpdated       = "user.updated"
+	ClerkUserDeleted       = "user.deleted"
+	ClerkMembershipCreated = "organizationMembership.created"
+	ClerkMembershipUpdated = "organizationMembership.updated"
+	ClerkMembershipDeleted = "organizationMembership.deleted"
+)
+
+// ClerkEvent is the envelope of a Clerk webhook delivery. Data holds the
+// Clerk resource the event is about and is decoded according to Type.
+type ClerkEvent struct {
+	Type   string          `json:"type"`
+	Object string          `json:"object"`
+	Data   json.RawMessage `json:"data"`
+}
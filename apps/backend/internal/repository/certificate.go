@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/inventedsarawak/ledgera/internal/model/certificate"
+	"github.com/inventedsarawak/ledgera/internal/model/token"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrCertificateExists is returned by Create when the retirement transaction
+// already has a certificate.
+var ErrCertificateExists = errors.New("transaction has already been retired")
+
+const certificateColumns = `
+            id, owner_id, project_id, tx_hash, COALESCE(block_number, 0), COALESCE(wallet_address, ''),
+            amount_retired, COALESCE(beneficiary_name, ''), retirement_reason, pdf_url,
+            created_at, updated_at`
+
+func scanCertificate(row pgx.Row) (*certificate.Certificate, error) {
+	var c certificate.Certificate
+	err := row.Scan(
+		&c.ID, &c.OwnerID, &c.ProjectID, &c.TxHash, &c.BlockNumber, &c.WalletAddress,
+		&c.AmountRetired, &c.BeneficiaryName, &c.RetirementReason, &c.PDFURL,
+		&c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanCertificates(rows pgx.Rows) ([]certificate.Certificate, error) {
+	defer rows.Close()
+
+	certificates := []certificate.Certificate{}
+	for rows.Next() {
+		c, err := scanCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, *c)
+	}
+	return certificates, rows.Err()
+}
+
+type CertificateRepository struct {
+	s *server.Server
+}
+
+func NewCertificateRepository(s *server.Server) *CertificateRepository {
+	return &CertificateRepository{s: s}
+}
+
+// Create inserts a certificate together with the transfers that retired its
+// tokens, so the holder's balance drops immediately instead of when the
+// indexer reaches the block. The indexer later skips those transfers by
+// (tx_hash, log_index), and the UNIQUE tx_hash keeps a transaction from being
+// retired twice, so nothing is counted twice.
+func (r *CertificateRepository) Create(ctx context.Context, c certificate.Certificate, amount string, transfers []token.Transfer) (*certificate.Certificate, error) {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	insertQuery := `
+        INSERT INTO certificates (
+            owner_id, project_id, tx_hash, block_number, wallet_address,
+            amount_retired, beneficiary_name, retirement_reason
+        ) VALUES (
+            @owner_id, @project_id, @tx_hash, @block_number, @wallet_address,
+            @amount_retired::numeric, @beneficiary_name, @retirement_reason
+        )
+        ON CONFLICT (tx_hash) DO NOTHING
+        RETURNING ` + certificateColumns + `
+    `
+
+	args := pgx.NamedArgs{
+		"owner_id":          c.OwnerID,
+		"project_id":        c.ProjectID,
+		"tx_hash":           c.TxHash,
+		"block_number":      c.BlockNumber,
+		"wallet_address":    c.WalletAddress,
+		"amount_retired":    amount,
+		"beneficiary_name":  c.BeneficiaryName,
+		"retirement_reason": c.RetirementReason,
+	}
+
+	created, err := scanCertificate(tx.QueryRow(ctx, insertQuery, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCertificateExists
+		}
+		return nil, err
+	}
+
+	if err := saveTransfers(ctx, tx, transfers); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *CertificateRepository) FindByID(ctx context.Context, id string) (*certificate.Certificate, error) {
+	query := `
+        SELECT ` + certificateColumns + `
+        FROM certificates
+        WHERE id = @id
+    `
+
+	c, err := scanCertificate(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *CertificateRepository) FindByTxHash(ctx context.Context, txHash string) (*certificate.Certificate, error) {
+	query := `
+        SELECT ` + certificateColumns + `
+        FROM certificates
+        WHERE tx_hash = @tx_hash
+    `
+
+	c, err := scanCertificate(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"tx_hash": txHash}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListByOwnerPaginated returns one page of a user's certificates, newest
+// first.
+func (r *CertificateRepository) ListByOwnerPaginated(ctx context.Context, ownerID string, q certificate.GetCertificatesQuery) ([]certificate.Certificate, int64, error) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 1 {
+		q.Limit = 20
+	}
+	offset := (q.Page - 1) * q.Limit
+
+	listQuery := `
+        SELECT ` + certificateColumns + `
+        FROM certificates
+        WHERE owner_id = @owner_id
+        ORDER BY created_at DESC
+        LIMIT @limit OFFSET @offset
+    `
+
+	listArgs := pgx.NamedArgs{
+		"owner_id": ownerID,
+		"limit":    q.Limit,
+		"offset":   offset,
+	}
+
+	rows, err := r.s.DB.Pool.Query(ctx, listQuery, listArgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	certificates, err := scanCertificates(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM certificates WHERE owner_id = @owner_id`
+	err = r.s.DB.Pool.QueryRow(ctx, countQuery, pgx.NamedArgs{"owner_id": ownerID}).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return certificates, total, nil
+}
+
+func (r *CertificateRepository) SetPDFURL(ctx context.Context, id string, pdfURL string) error {
+	query := `
+        UPDATE certificates
+        SET pdf_url = @pdf_url
+        WHERE id = @id
+    `
+
+	_, err := r.s.DB.Pool.Exec(ctx, query, pgx.NamedArgs{"id": id, "pdf_url": pdfURL})
+	return err
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/listing"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+// listingColumns is the column list every listing query selects or returns,
+// in the order scanListing expects.
+const listingColumns = `
+            id, project_id, listing_id_on_chain::text, seller_address,
+            price_per_token, quantity_available, active,
+            created_at, updated_at`
+
+func scanListing(row pgx.Row) (*listing.Listing, error) {
+	var l listing.Listing
+	err := row.Scan(
+		&l.ID, &l.ProjectID, &l.ListingIDOnChain, &l.SellerAddress,
+		&l.PricePerToken, &l.QuantityAvailable, &l.Active,
+		&l.CreatedAt, &l.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func scanListings(rows pgx.Rows) ([]listing.Listing, error) {
+	defer rows.Close()
+
+	listings := []listing.Listing{}
+	for rows.Next() {
+		l, err := scanListing(rows)
+		if err != nil {
+			return nil, err
+		}
+		listings = append(listings, *l)
+	}
+	return listings, rows.Err()
+}
+
+type ListingRepository struct {
+	s *server.Server
+}
+
+func NewListingRepository(s *server.Server) *ListingRepository {
+	return &ListingRepository{s: s}
+}
+
+func (r *ListingRepository) Create(ctx context.Context, l listing.Listing) (*listing.Listing, error) {
+	query := `
+        INSERT INTO listings (
+            project_id, seller_address, price_per_token, quantity_available, active
+        ) VALUES (
+            @project_id, @seller_address, @price_per_token, @quantity_available, TRUE
+        )
+        RETURNING ` + listingColumns + `
+    `
+
+	args := pgx.NamedArgs{
+		"project_id":         l.ProjectID,
+		"seller_address":     l.SellerAddress,
+		"price_per_token":    l.PricePerToken,
+		"quantity_available": l.QuantityAvailable,
+	}
+
+	return scanListing(r.s.DB.Pool.QueryRow(ctx, query, args))
+}
+
+func (r *ListingRepository) FindByID(ctx context.Context, id string) (*listing.Listing, error) {
+	query := `
+        SELECT ` + listingColumns + `
+        FROM listings
+        WHERE id = @id
+    `
+
+	l, err := scanListing(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListActivePaginated browses active listings with something left to sell,
+// optionally restricted to one project, cheapest first.
+func (r *ListingRepository) ListActivePaginated(ctx context.Context, q listing.GetListingsQuery) ([]listing.Listing, int64, error) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 1 {
+		q.Limit = 20
+	}
+	offset := (q.Page - 1) * q.Limit
+
+	where := `
+        WHERE active AND quantity_available > 0
+          AND (@project_id::uuid IS NULL OR project_id = @project_id::uuid)`
+
+	listQuery := `
+        SELECT ` + listingColumns + `
+        FROM listings` + where + `
+        ORDER BY price_per_token ASC, created_at DESC
+        LIMIT @limit OFFSET @offset
+    `
+
+	listArgs := pgx.NamedArgs{
+		"project_id": q.ProjectID,
+		"limit":      q.Limit,
+		"offset":     offset,
+	}
+
+	rows, err := r.s.DB.Pool.Query(ctx, listQuery, listArgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	listings, err := scanListings(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	countArgs := pgx.NamedArgs{"project_id": q.ProjectID}
+	err = r.s.DB.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM listings`+where, countArgs).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return listings, total, nil
+}
+
+// ListActiveAfter is the keyset-paginated form of ListActivePaginated. Pages
+// are ordered newest first rather than by price, starting after the after
+// cursor (or from the newest when it is nil).
+func (r *ListingRepository) ListActiveAfter(ctx context.Context, projectID *uuid.UUID, after *model.Cursor, limit int) ([]listing.Listing, int64, *model.Cursor, error) {
+	if limit < 1 {
+		limit = 20
+	}
+
+	where := `
+        WHERE active AND quantity_available > 0
+          AND (@project_id::uuid IS NULL OR project_id = @project_id::uuid)`
+
+	listQuery := `
+        SELECT ` + listingColumns + `
+        FROM listings` + where + `
+          AND` + keysetWhere + `
+        ORDER BY created_at DESC, id DESC
+        LIMIT @limit
+    `
+
+	listArgs := pgx.NamedArgs{"project_id": projectID, "limit": limit + 1}
+	for k, v := range keysetArgs(after) {
+		listArgs[k] = v
+	}
+
+	rows, err := r.s.DB.Pool.Query(ctx, listQuery, listArgs)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	listings, err := scanListings(rows)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	listings, next := keysetPage(listings, limit, func(l listing.Listing) model.Cursor {
+		return model.Cursor{CreatedAt: l.CreatedAt, ID: l.ID}
+	})
+
+	var total int64
+	countArgs := pgx.NamedArgs{"project_id": projectID}
+	err = r.s.DB.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM listings`+where, countArgs).Scan(&total)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return listings, total, next, nil
+}
+
+// CountActiveBySeller counts the active listings of sellerAddress, matched
+// case-insensitively.
+func (r *ListingRepository) CountActiveBySeller(ctx context.Context, sellerAddress string) (int64, error) {
+	query := `
+        SELECT COUNT(*)
+        FROM listings
+        WHERE active AND LOWER(seller_address) = LOWER(@seller_address)
+    `
+
+	var count int64
+	err := r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"seller_address": sellerAddress}).Scan(&count)
+	return count, err
+}
+
+// Update changes the price and/or quantity of an active listing.
+func (r *ListingRepository) Update(ctx context.Context, id string, payload listing.UpdateListingPayload) (*listing.Listing, error) {
+	query := `
+        UPDATE listings
+        SET
+            price_per_token = COALESCE(@price_per_token, price_per_token),
+            quantity_available = COALESCE(@quantity_available, quantity_available),
+            updated_at = NOW()
+        WHERE id = @id AND active
+        RETURNING ` + listingColumns + `
+    `
+
+	args := pgx.NamedArgs{
+		"id":                 id,
+		"price_per_token":    payload.PricePerToken,
+		"quantity_available": payload.QuantityAvailable,
+	}
+
+	l, err := scanListing(r.s.DB.Pool.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+func (r *ListingRepository) Deactivate(ctx context.Context, id string) (*listing.Listing, error) {
+	query := `
+        UPDATE listings
+        SET active = FALSE, updated_at = NOW()
+        WHERE id = @id
+        RETURNING ` + listingColumns + `
+    `
+
+	l, err := scanListing(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return l, nil
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/inventedsarawak/ledgera/internal/model/order"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	// ErrListingUnavailable is returned by Reserve when the listing is
+	// missing or no longer active.
+	ErrListingUnavailable = errors.New("listing is not available")
+
+	// ErrInsufficientQuantity is returned by Reserve when the listing has less
+	// left than the requested quantity.
+	ErrInsufficientQuantity = errors.New("listing does not have enough quantity available")
+
+	// ErrSettlementTxUsed is returned by Settle when the transaction already
+	// settled another order.
+	ErrSettlementTxUsed = errors.New("settlement transaction already used")
+)
+
+const orderColumns = `
+            id, listing_id, project_id, buyer_id, buyer_address, seller_address,
+            quantity, price_per_token, total_price, status, expires_at,
+            payment_reference, settlement_tx_hash, paid_at, settled_at,
+            created_at, updated_at`
+
+func scanOrder(row pgx.Row) (*order.Order, error) {
+	var o order.Order
+	err := row.Scan(
+		&o.ID, &o.ListingID, &o.ProjectID, &o.BuyerID, &o.BuyerAddress, &o.SellerAddress,
+		&o.Quantity, &o.PricePerToken, &o.TotalPrice, &o.Status, &o.ExpiresAt,
+		&o.PaymentReference, &o.SettlementTxHash, &o.PaidAt, &o.SettledAt,
+		&o.CreatedAt, &o.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func scanOrders(rows pgx.Rows) ([]order.Order, error) {
+	defer rows.Close()
+
+	orders := []order.Order{}
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, *o)
+	}
+	return orders, rows.Err()
+}
+
+type OrderRepository struct {
+	s *server.Server
+}
+
+func NewOrderRepository(s *server.Server) *OrderRepository {
+	return &OrderRepository{s: s}
+}
+
+// Reserve places a RESERVED order for quantity tokens of a listing. The
+// listing row is locked while its quantity_available is checked and
+// decremented, so concurrent orders can never oversell it.
+func (r *OrderRepository) Reserve(ctx context.Context, listingID string, buyerID string, buyerAddress string, quantity float64, ttl time.Duration) (*order.Order, error) {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	lockQuery := `
+        SELECT active, quantity_available >= @quantity::numeric
+        FROM listings
+        WHERE id = @listing_id
+        FOR UPDATE
+    `
+
+	var active, enough bool
+	err = tx.QueryRow(ctx, lockQuery, pgx.NamedArgs{"listing_id": listingID, "quantity": quantity}).
+		Scan(&active, &enough)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrListingUnavailable
+		}
+		return nil, err
+	}
+	if !active {
+		return nil, ErrListingUnavailable
+	}
+	if !enough {
+		return nil, ErrInsufficientQuantity
+	}
+
+	insertQuery := `
+        WITH reserved AS (
+            UPDATE listings
+            SET quantity_available = quantity_available - @quantity::numeric, updated_at = NOW()
+            WHERE id = @listing_id
+            RETURNING id, project_id, seller_address, price_per_token
+        )
+        INSERT INTO orders (
+            listing_id, project_id, buyer_id, buyer_address, seller_address,
+            quantity, price_per_token, total_price, status, expires_at
+        )
+        SELECT
+            id, project_id, @buyer_id, @buyer_address, seller_address,
+            @quantity::numeric, price_per_token, price_per_token * @quantity::numeric, 'RESERVED', NOW() + @ttl_seconds * INTERVAL '1 second'
+        FROM reserved
+        RETURNING ` + orderColumns + `
+    `
+
+	args := pgx.NamedArgs{
+		"listing_id":    listingID,
+		"buyer_id":      buyerID,
+		"buyer_address": buyerAddress,
+		"quantity":      quantity,
+		"ttl_seconds":   int64(ttl / time.Second),
+	}
+
+	o, err := scanOrder(tx.QueryRow(ctx, insertQuery, args))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (r *OrderRepository) FindByID(ctx context.Context, id string) (*order.Order, error) {
+	query := `
+        SELECT ` + orderColumns + `
+        FROM orders
+        WHERE id = @id
+    `
+
+	o, err := scanOrder(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return o, nil
+}
+
+// ListByBuyerPaginated returns one page of a buyer's orders, newest first.
+func (r *OrderRepository) ListByBuyerPaginated(ctx context.Context, buyerID string, q order.GetOrdersQuery) ([]order.Order, int64, error) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 1 {
+		q.Limit = 20
+	}
+	offset := (q.Page - 1) * q.Limit
+
+	where := `
+        WHERE buyer_id = @buyer_id
+          AND (@status::order_status IS NULL OR status = @status::order_status)`
+
+	listQuery := `
+        SELECT ` + orderColumns + `
+        FROM orders` + where + `
+        ORDER BY created_at DESC
+        LIMIT @limit OFFSET @offset
+    `
+
+	listArgs := pgx.NamedArgs{
+		"buyer_id": buyerID,
+		"status":   q.Status,
+		"limit":    q.Limit,
+		"offset":   offset,
+	}
+
+	rows, err := r.s.DB.Pool.Query(ctx, listQuery, listArgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	orders, err := scanOrders(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	countArgs := pgx.NamedArgs{"buyer_id": buyerID, "status": q.Status}
+	err = r.s.DB.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM orders`+where, countArgs).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return orders, total, nil
+}
+
+// MarkPaid moves an unexpired RESERVED order to PAID. It returns nil when the
+// order is in any other state or its reservation has lapsed.
+func (r *OrderRepository) MarkPaid(ctx context.Context, id string, paymentReference string) (*order.Order, error) {
+	query := `
+        UPDATE orders
+        SET status = 'PAID', payment_reference = @payment_reference, paid_at = NOW()
+        WHERE id = @id AND status = 'RESERVED' AND expires_at > NOW()
+        RETURNING ` + orderColumns + `
+    `
+
+	args := pgx.NamedArgs{"id": id, "payment_reference": paymentReference}
+
+	o, err := scanOrder(r.s.DB.Pool.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return o, nil
+}
+
+// Settle moves a PAID order to SETTLED and records the transfer that
+// delivered its tokens. It returns nil when the order is not PAID, and
+// ErrSettlementTxUsed when the transfer already settled another order.
+func (r *OrderRepository) Settle(ctx context.Context, id string, txHash string) (*order.Order, error) {
+	query := `
+        UPDATE orders
+        SET status = 'SETTLED', settlement_tx_hash = @tx_hash, settled_at = NOW()
+        WHERE id = @id AND status = 'PAID'
+        RETURNING ` + orderColumns + `
+    `
+
+	o, err := scanOrder(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id, "tx_hash": txHash}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		// settlement_tx_hash is unique
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrSettlementTxUsed
+		}
+		return nil, err
+	}
+	return o, nil
+}
+
+// Cancel moves a RESERVED order to CANCELLED and returns its quantity to the
+// listing in the same statement. It returns nil when the order is not
+// RESERVED.
+func (r *OrderRepository) Cancel(ctx context.Context, id string) (*order.Order, error) {
+	query := `
+        WITH cancelled AS (
+            UPDATE orders
+            SET status = 'CANCELLED'
+            WHERE id = @id AND status = 'RESERVED'
+            RETURNING ` + orderColumns + `
+        ), released AS (
+            UPDATE listings l
+            SET quantity_available = l.quantity_available + c.quantity, updated_at = NOW()
+            FROM cancelled c
+            WHERE l.id = c.listing_id
+        )
+        SELECT ` + orderColumns + ` FROM cancelled
+    `
+
+	o, err := scanOrder(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return o, nil
+}
+
+// ExpireReservations moves every RESERVED order past its expiry to EXPIRED
+// and returns the held quantities to their listings. It returns the number of
+// orders expired.
+func (r *OrderRepository) ExpireReservations(ctx context.Context) (int64, error) {
+	query := `
+        WITH expired AS (
+            UPDATE orders
+            SET status = 'EXPIRED'
+            WHERE status = 'RESERVED' AND expires_at <= NOW()
+            RETURNING listing_id, quantity
+        ), released AS (
+            UPDATE listings l
+            SET quantity_available = l.quantity_available + e.quantity, updated_at = NOW()
+            FROM (
+                SELECT listing_id, SUM(quantity) AS quantity
+                FROM expired
+                GROUP BY listing_id
+            ) e
+            WHERE l.id = e.listing_id
+        )
+        SELECT COUNT(*) FROM expired
+    `
+
+	var count int64
+	if err := r.s.DB.Pool.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
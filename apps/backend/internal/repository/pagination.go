@@ -0,0 +1,28 @@
+package repository
+
+import "github.com/inventedsarawak/ledgera/internal/model"
+
+// keysetWhere restricts a feed ordered by created_at DESC, id DESC to the rows
+// after the @cursor_created_at/@cursor_id position; it matches everything
+// when no cursor is given.
+const keysetWhere = `
+            (@cursor_id::uuid IS NULL OR (created_at, id) < (@cursor_created_at::timestamptz, @cursor_id::uuid))`
+
+// keysetArgs returns the named arguments keysetWhere expects.
+func keysetArgs(after *model.Cursor) map[string]any {
+	if after == nil {
+		return map[string]any{"cursor_created_at": nil, "cursor_id": nil}
+	}
+	return map[string]any{"cursor_created_at": after.CreatedAt, "cursor_id": after.ID}
+}
+
+// keysetPage trims rows, fetched with a LIMIT of limit+1, to limit and returns
+// the cursor of its last row when there is a further page.
+func keysetPage[T any](rows []T, limit int, key func(T) model.Cursor) ([]T, *model.Cursor) {
+	if len(rows) <= limit {
+		return rows, nil
+	}
+	rows = rows[:limit]
+	next := key(rows[limit-1])
+	return rows, &next
+}
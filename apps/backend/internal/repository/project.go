@@ -4,11 +4,51 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
 	"github.com/inventedsarawak/ledgera/internal/model/project"
 	"github.com/inventedsarawak/ledgera/internal/server"
 	"github.com/jackc/pgx/v5"
 )
 
+// projectColumns is the column list every project query selects or returns,
+// in the order scanProject expects.
+const projectColumns = `
+            id, supplier_id, title, description, image_url, audit_report_url,
+            location_lat, location_lng, area, boundary_area_ha, area_mismatch,
+            carbon_amount_total, price_per_tonne,
+            contract_address, token_symbol, deploy_tx_hash,
+            status, created_at, updated_at`
+
+func scanProject(row pgx.Row) (*project.Project, error) {
+	var p project.Project
+	err := row.Scan(
+		&p.ID, &p.SupplierID, &p.Title, &p.Description, &p.ImageURL, &p.AuditReportURL,
+		&p.LocationLat, &p.LocationLng, &p.Area, &p.BoundaryAreaHa, &p.AreaMismatch,
+		&p.CarbonAmount, &p.PricePerTonne,
+		&p.ContractAddress, &p.TokenSymbol, &p.DeployTxHash,
+		&p.Status, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func scanProjects(rows pgx.Rows) ([]project.Project, error) {
+	defer rows.Close()
+
+	var projects []project.Project = []project.Project{}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, *p)
+	}
+	return projects, rows.Err()
+}
+
 type ProjectRepository struct {
 	s *server.Server
 }
@@ -57,12 +97,7 @@ func (r *ProjectRepository) Create(ctx context.Context, p project.Project) (*pro
 
 func (r *ProjectRepository) FindByID(ctx context.Context, id string) (*project.Project, error) {
 	query := `
-        SELECT 
-            id, supplier_id, title, description, image_url, audit_report_url,
-            location_lat, location_lng, area, 
-            carbon_amount_total, price_per_tonne,
-            contract_address, token_symbol, 
-            status, created_at, updated_at
+        SELECT ` + projectColumns + `
         FROM projects
         WHERE id = @id
     `
@@ -71,15 +106,7 @@ func (r *ProjectRepository) FindByID(ctx context.Context, id string) (*project.P
 		"id": id,
 	}
 
-	var p project.Project
-	err := r.s.DB.Pool.QueryRow(ctx, query, args).Scan(
-		&p.ID, &p.SupplierID, &p.Title, &p.Description, &p.ImageURL, &p.AuditReportURL,
-		&p.LocationLat, &p.LocationLng, &p.Area,
-		&p.CarbonAmount, &p.PricePerTonne,
-		&p.ContractAddress, &p.TokenSymbol,
-		&p.Status, &p.CreatedAt, &p.UpdatedAt,
-	)
-
+	p, err := scanProject(r.s.DB.Pool.QueryRow(ctx, query, args))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -87,7 +114,7 @@ func (r *ProjectRepository) FindByID(ctx context.Context, id string) (*project.P
 		return nil, err
 	}
 
-	return &p, nil
+	return p, nil
 }
 
 func (r *ProjectRepository) ListBySupplierPaginated(ctx context.Context, supplierID string, page int, limit int) ([]project.Project, int64, error) {
@@ -100,15 +127,10 @@ func (r *ProjectRepository) ListBySupplierPaginated(ctx context.Context, supplie
 	offset := (page - 1) * limit
 
 	listQuery := `
-        SELECT 
-            id, supplier_id, title, description, image_url, audit_report_url,
-            location_lat, location_lng, area, 
-            carbon_amount_total, price_per_tonne,
-            contract_address, token_symbol, 
-            status, created_at, updated_at
+        SELECT ` + projectColumns + `
         FROM projects
         WHERE supplier_id = @supplier_id
-        ORDER BY created_at DESC
+        ORDER BY created_at DESC, id DESC
         LIMIT @limit OFFSET @offset
     `
 
@@ -122,21 +144,10 @@ func (r *ProjectRepository) ListBySupplierPaginated(ctx context.Context, supplie
 	if err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var projects []project.Project
-	for rows.Next() {
-		var p project.Project
-		if err := rows.Scan(
-			&p.ID, &p.SupplierID, &p.Title, &p.Description, &p.ImageURL, &p.AuditReportURL,
-			&p.LocationLat, &p.LocationLng, &p.Area,
-			&p.CarbonAmount, &p.PricePerTonne,
-			&p.ContractAddress, &p.TokenSymbol,
-			&p.Status, &p.CreatedAt, &p.UpdatedAt,
-		); err != nil {
-			return nil, 0, err
-		}
-		projects = append(projects, p)
+	projects, err := scanProjects(rows)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	var total int64
@@ -159,15 +170,10 @@ func (r *ProjectRepository) ListByStatusPaginated(ctx context.Context, status pr
 	offset := (page - 1) * limit
 
 	listQuery := `
-        SELECT
-            id, supplier_id, title, description, image_url, audit_report_url,
-            location_lat, location_lng, area, 
-            carbon_amount_total, price_per_tonne,
-            contract_address, token_symbol,
-            status, created_at, updated_at
+        SELECT ` + projectColumns + `
         FROM projects
         WHERE status = @status
-        ORDER BY created_at DESC
+        ORDER BY created_at DESC, id DESC
         LIMIT @limit OFFSET @offset
     `
 
@@ -181,21 +187,10 @@ func (r *ProjectRepository) ListByStatusPaginated(ctx context.Context, status pr
 	if err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var projects []project.Project
-	for rows.Next() {
-		var p project.Project
-		if err := rows.Scan(
-			&p.ID, &p.SupplierID, &p.Title, &p.Description, &p.ImageURL, &p.AuditReportURL,
-			&p.LocationLat, &p.LocationLng, &p.Area,
-			&p.CarbonAmount, &p.PricePerTonne,
-			&p.ContractAddress, &p.TokenSymbol,
-			&p.Status, &p.CreatedAt, &p.UpdatedAt,
-		); err != nil {
-			return nil, 0, err
-		}
-		projects = append(projects, p)
+	projects, err := scanProjects(rows)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	var total int64
@@ -208,104 +203,488 @@ func (r *ProjectRepository) ListByStatusPaginated(ctx context.Context, status pr
 	return projects, total, nil
 }
 
-func (r *ProjectRepository) ListBySupplier(ctx context.Context, supplierID string) ([]project.Project, error) {
-	// Not paginated version
+// ListBySupplierAfter is the keyset-paginated form of ListBySupplierPaginated:
+// newest first, starting after the after cursor (or from the newest when it
+// is nil). It returns the cursor of the next page, if any.
+func (r *ProjectRepository) ListBySupplierAfter(ctx context.Context, supplierID string, after *model.Cursor, limit int) ([]project.Project, int64, *model.Cursor, error) {
+	return r.listAfter(ctx, `supplier_id = @supplier_id`, pgx.NamedArgs{"supplier_id": supplierID}, after, limit)
+}
+
+// ListByStatusAfter is the keyset-paginated form of ListByStatusPaginated.
+func (r *ProjectRepository) ListByStatusAfter(ctx context.Context, status project.ProjectStatus, after *model.Cursor, limit int) ([]project.Project, int64, *model.Cursor, error) {
+	return r.listAfter(ctx, `status = @status`, pgx.NamedArgs{"status": status}, after, limit)
+}
+
+// listAfter pages through the projects matching filter, newest first. On the
+// first page the total counts every matching project; later pages skip the
+// count, which repeats the whole filter, and return a total of -1.
+func (r *ProjectRepository) listAfter(ctx context.Context, filter string, args pgx.NamedArgs, after *model.Cursor, limit int) ([]project.Project, int64, *model.Cursor, error) {
+	if limit < 1 {
+		limit = 20
+	}
+
+	listQuery := `
+        SELECT ` + projectColumns + `
+        FROM projects
+        WHERE (` + filter + `) AND` + keysetWhere + `
+        ORDER BY created_at DESC, id DESC
+        LIMIT @limit
+    `
+
+	listArgs := pgx.NamedArgs{"limit": limit + 1}
+	for k, v := range args {
+		listArgs[k] = v
+	}
+	for k, v := range keysetArgs(after) {
+		listArgs[k] = v
+	}
+
+	rows, err := r.s.DB.Pool.Query(ctx, listQuery, listArgs)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	projects, err := scanProjects(rows)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	projects, next := keysetPage(projects, limit, projectCursor)
+
+	total := int64(-1)
+	if after == nil {
+		err = r.s.DB.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM projects WHERE `+filter, args).Scan(&total)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+	}
+
+	return projects, total, next, nil
+}
+
+// SearchAfter is the keyset-paginated form of Search. It always orders by
+// created_at, newest first.
+func (r *ProjectRepository) SearchAfter(ctx context.Context, q project.GetProjectsQuery, statuses []project.ProjectStatus, after *model.Cursor, limit int) ([]project.Project, int64, *model.Cursor, error) {
+	filter, args := searchFilter(q, statuses)
+	return r.listAfter(ctx, filter, args, after, limit)
+}
+
+// searchFilter returns the conditions and arguments of q's filters.
+func searchFilter(q project.GetProjectsQuery, statuses []project.ProjectStatus) (string, pgx.NamedArgs) {
+	filter := `status = ANY(@statuses::text[]::project_status[])
+            AND (@status::project_status IS NULL OR status = @status::project_status)
+            AND (@supplier_id::text IS NULL OR supplier_id = @supplier_id::text)
+            AND (@search::text IS NULL OR search_vector @@ websearch_to_tsquery('english', @search::text))
+            AND (@min_price::numeric IS NULL OR price_per_tonne >= @min_price::numeric)
+            AND (@max_price::numeric IS NULL OR price_per_tonne <= @max_price::numeric)
+            AND (@min_carbon::numeric IS NULL OR carbon_amount_total >= @min_carbon::numeric)
+            AND (@max_carbon::numeric IS NULL OR carbon_amount_total <= @max_carbon::numeric)
+            AND (@created_after::timestamptz IS NULL OR created_at >= @created_after::timestamptz)
+            AND (@created_before::timestamptz IS NULL OR created_at <= @created_before::timestamptz)`
+
+	return filter, pgx.NamedArgs{
+		"statuses":       statusNames(statuses),
+		"status":         q.Status,
+		"supplier_id":    q.SupplierID,
+		"search":         q.Search,
+		"min_price":      q.MinPrice,
+		"max_price":      q.MaxPrice,
+		"min_carbon":     q.MinCarbon,
+		"max_carbon":     q.MaxCarbon,
+		"created_after":  q.CreatedAfter,
+		"created_before": q.CreatedBefore,
+	}
+}
+
+// ListInBBox lists projects in one of statuses whose location lies inside b,
+// newest first.
+func (r *ProjectRepository) ListInBBox(ctx context.Context, b project.BBox, statuses []project.ProjectStatus, limit int) ([]project.Project, error) {
 	query := `
-        SELECT 
-            id, supplier_id, title, description, image_url, audit_report_url,
-            location_lat, location_lng, area, 
-            carbon_amount_total, price_per_tonne,
-            contract_address, token_symbol, 
-            status, created_at, updated_at
+        SELECT ` + projectColumns + `
         FROM projects
-        WHERE supplier_id = @supplier_id
-        ORDER BY created_at DESC
+        WHERE status = ANY(@statuses::text[]::project_status[])
+            AND location::geometry && ST_MakeEnvelope(@min_lng::float8, @min_lat::float8, @max_lng::float8, @max_lat::float8, 4326)
+        ORDER BY created_at DESC, id DESC
+        LIMIT @limit
     `
-	// ... (Implementation same as paginated but without LIMIT)
-	// For brevity, assuming this is used less or can follow the pattern above
-	// But to be safe, here is the implementation:
-	args := pgx.NamedArgs{
-		"supplier_id": supplierID,
+
+	rows, err := r.s.DB.Pool.Query(ctx, query, pgx.NamedArgs{
+		"statuses": statusNames(statuses),
+		"min_lng":  b.MinLng,
+		"min_lat":  b.MinLat,
+		"max_lng":  b.MaxLng,
+		"max_lat":  b.MaxLat,
+		"limit":    limit,
+	})
+	if err != nil {
+		return nil, err
 	}
+	return scanProjects(rows)
+}
 
-	rows, err := r.s.DB.Pool.Query(ctx, query, args)
+// ListNear lists projects in one of statuses within radiusKm of lat/lng,
+// nearest first, with their distance in kilometres.
+func (r *ProjectRepository) ListNear(ctx context.Context, lat, lng, radiusKm float64, statuses []project.ProjectStatus, limit int) ([]project.ProjectWithDistance, error) {
+	query := `
+        SELECT ` + projectColumns + `, ST_Distance(location, origin.point) / 1000
+        FROM projects,
+            (SELECT ST_SetSRID(ST_MakePoint(@lng::float8, @lat::float8), 4326)::geography AS point) AS origin
+        WHERE status = ANY(@statuses::text[]::project_status[])
+            AND ST_DWithin(location, origin.point, @radius_m::float8)
+        ORDER BY location <-> origin.point, id
+        LIMIT @limit
+    `
+
+	rows, err := r.s.DB.Pool.Query(ctx, query, pgx.NamedArgs{
+		"statuses": statusNames(statuses),
+		"lat":      lat,
+		"lng":      lng,
+		"radius_m": radiusKm * 1000,
+		"limit":    limit,
+	})
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var projects []project.Project = []project.Project{}
+	results := []project.ProjectWithDistance{}
 	for rows.Next() {
-		var p project.Project
+		var p project.ProjectWithDistance
 		err := rows.Scan(
 			&p.ID, &p.SupplierID, &p.Title, &p.Description, &p.ImageURL, &p.AuditReportURL,
-			&p.LocationLat, &p.LocationLng, &p.Area,
+			&p.LocationLat, &p.LocationLng, &p.Area, &p.BoundaryAreaHa, &p.AreaMismatch,
 			&p.CarbonAmount, &p.PricePerTonne,
-			&p.ContractAddress, &p.TokenSymbol,
+			&p.ContractAddress, &p.TokenSymbol, &p.DeployTxHash,
 			&p.Status, &p.CreatedAt, &p.UpdatedAt,
+			&p.DistanceKm,
 		)
 		if err != nil {
 			return nil, err
 		}
-		projects = append(projects, p)
+		results = append(results, p)
 	}
-	return projects, nil
+	return results, rows.Err()
 }
 
-func (r *ProjectRepository) Update(ctx context.Context, id string, payload project.UpdateProjectPayload, imageURL *string, auditReportURL *string) (*project.Project, error) {
-	// Added: audit_report_url update support
+func statusNames(statuses []project.ProjectStatus) []string {
+	names := make([]string, len(statuses))
+	for i, st := range statuses {
+		names[i] = string(st)
+	}
+	return names
+}
+
+func projectCursor(p project.Project) model.Cursor {
+	return model.Cursor{CreatedAt: p.CreatedAt, ID: p.ID}
+}
+
+// projectSortColumns maps the sort keys GetProjectsQuery accepts to the
+// columns they order by. Only these are ever interpolated into SQL.
+var projectSortColumns = map[string]string{
+	"created_at":      "created_at",
+	"title":           "title",
+	"price_per_tonne": "price_per_tonne",
+	"carbon_amount":   "carbon_amount_total",
+}
+
+// Search lists projects in one of statuses that match q's filters. Search
+// text is matched against title and description with websearch syntax.
+// Results are ordered by q's sort, with id as a tiebreaker so pages are
+// stable.
+func (r *ProjectRepository) Search(ctx context.Context, q project.GetProjectsQuery, statuses []project.ProjectStatus) ([]project.Project, int64, error) {
+	page, limit := 1, 20
+	if q.Page != nil && *q.Page > 0 {
+		page = *q.Page
+	}
+	if q.Limit != nil && *q.Limit > 0 {
+		limit = *q.Limit
+	}
+
+	filter, args := searchFilter(q, statuses)
+	where := `
+        WHERE ` + filter
+
+	direction := "DESC"
+	if q.Order != nil && *q.Order == "asc" {
+		direction = "ASC"
+	}
+	orderBy := "created_at " + direction
+	if q.Sort != nil {
+		if column, ok := projectSortColumns[*q.Sort]; ok {
+			orderBy = column + " " + direction
+		} else if *q.Sort == "relevance" && q.Search != nil {
+			// Relevance is always best match first
+			orderBy = "ts_rank(search_vector, websearch_to_tsquery('english', @search::text)) DESC, created_at DESC"
+		}
+	}
+
+	listQuery := `
+        SELECT ` + projectColumns + `
+        FROM projects` + where + `
+        ORDER BY ` + orderBy + `, id
+        LIMIT @limit OFFSET @offset
+    `
+
+	listArgs := pgx.NamedArgs{"limit": limit, "offset": (page - 1) * limit}
+	for k, v := range args {
+		listArgs[k] = v
+	}
+
+	rows, err := r.s.DB.Pool.Query(ctx, listQuery, listArgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	projects, err := scanProjects(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	err = r.s.DB.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM projects`+where, args).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return projects, total, nil
+}
+
+func (r *ProjectRepository) ListBySupplier(ctx context.Context, supplierID string) ([]project.Project, error) {
+	// Not paginated version
+	query := `
+        SELECT ` + projectColumns + `
+        FROM projects
+        WHERE supplier_id = @supplier_id
+        ORDER BY created_at DESC
+    `
+
+	args := pgx.NamedArgs{
+		"supplier_id": supplierID,
+	}
+
+	rows, err := r.s.DB.Pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanProjects(rows)
+}
+
+// CountBySupplierInStatus counts the supplier's projects in any of statuses.
+func (r *ProjectRepository) CountBySupplierInStatus(ctx context.Context, supplierID string, statuses ...project.ProjectStatus) (int64, error) {
+	query := `
+        SELECT COUNT(*)
+        FROM projects
+        WHERE supplier_id = @supplier_id AND status = ANY(@statuses::text[]::project_status[])
+    `
+
+	var count int64
+	err := r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"supplier_id": supplierID,
+		"statuses":    statusNames(statuses),
+	}).Scan(&count)
+	return count, err
+}
+
+// Update applies the set fields of payload. Images and audit reports are
+// changed through ProjectDocumentRepository.
+func (r *ProjectRepository) Update(ctx context.Context, id string, payload project.UpdateProjectPayload) (*project.Project, error) {
 	query := `
         UPDATE projects
-        SET 
+        SET
             title = COALESCE(@title, title),
             description = COALESCE(@description, description),
-            image_url = COALESCE(@image_url, image_url),
-            audit_report_url = COALESCE(@audit_report_url, audit_report_url),
             location_lat = COALESCE(@location_lat, location_lat),
             location_lng = COALESCE(@location_lng, location_lng),
             area = COALESCE(@area, area),
             carbon_amount_total = COALESCE(@carbon_amount_total, carbon_amount_total),
-            contract_address = COALESCE(@contract_address, contract_address),
-            status = COALESCE(@status, status),
             updated_at = NOW()
         WHERE id = @id
-        RETURNING 
-            id, supplier_id, title, description, image_url, audit_report_url,
-            location_lat, location_lng, area, 
-            carbon_amount_total, price_per_tonne,
-            contract_address, token_symbol,
-            status, created_at, updated_at
+        RETURNING ` + projectColumns + `
     `
 
 	args := pgx.NamedArgs{
 		"id":                  id,
 		"title":               payload.Title,
 		"description":         payload.Description,
-		"image_url":           imageURL,
-		"audit_report_url":    auditReportURL, // New Arg
 		"location_lat":        payload.LocationLat,
 		"location_lng":        payload.LocationLng,
 		"area":                payload.Area,
 		"carbon_amount_total": payload.CarbonAmount,
-		"contract_address":    payload.ContractAddress,
-		"status":              payload.Status,
 	}
 
-	var p project.Project
-	err := r.s.DB.Pool.QueryRow(ctx, query, args).Scan(
-		&p.ID, &p.SupplierID, &p.Title, &p.Description, &p.ImageURL, &p.AuditReportURL,
-		&p.LocationLat, &p.LocationLng, &p.Area,
-		&p.CarbonAmount, &p.PricePerTonne,
-		&p.ContractAddress, &p.TokenSymbol,
-		&p.Status, &p.CreatedAt, &p.UpdatedAt,
-	)
+	p, err := scanProject(r.s.DB.Pool.QueryRow(ctx, query, args))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &p, nil
+	return p, nil
+}
+
+// SetBoundary stores a validated GeoJSON polygon as the project's boundary
+// along with the area measured for it.
+func (r *ProjectRepository) SetBoundary(ctx context.Context, id string, geoJSON []byte, areaHa float64) (*project.Project, error) {
+	query := `
+        UPDATE projects
+        SET
+            boundary = ST_SetSRID(ST_GeomFromGeoJSON(@boundary::text), 4326)::geography,
+            boundary_area_ha = @boundary_area_ha,
+            updated_at = NOW()
+        WHERE id = @id
+        RETURNING ` + projectColumns + `
+    `
+
+	args := pgx.NamedArgs{
+		"id":               id,
+		"boundary":         string(geoJSON),
+		"boundary_area_ha": areaHa,
+	}
+
+	p, err := scanProject(r.s.DB.Pool.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// Boundary returns the project's boundary as a GeoJSON geometry, or nil when
+// none has been uploaded.
+func (r *ProjectRepository) Boundary(ctx context.Context, id string) ([]byte, error) {
+	query := `SELECT ST_AsGeoJSON(boundary) FROM projects WHERE id = @id`
+
+	var geoJSON *string
+	err := r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}).Scan(&geoJSON)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if geoJSON == nil {
+		return nil, nil
+	}
+	return []byte(*geoJSON), nil
+}
+
+// FindConflicts lists the projects in one of statuses that share land or a
+// title with project id: overlapping boundaries, nearby locations, a location
+// inside the other's boundary, or a similar title.
+func (r *ProjectRepository) FindConflicts(ctx context.Context, id string, statuses []project.ProjectStatus) ([]project.Conflict, error) {
+	query := `
+        WITH candidate AS (
+            SELECT id, title, location, boundary FROM projects WHERE id = @id
+        )
+        SELECT
+            o.id, o.title, o.status,
+            CASE WHEN ST_Intersects(c.boundary, o.boundary)
+                THEN ST_Area(ST_Intersection(c.boundary::geometry, o.boundary::geometry)::geography) / 10000
+            END,
+            ST_Distance(c.location, o.location),
+            COALESCE(ST_Covers(o.boundary, c.location), FALSE) OR COALESCE(ST_Covers(c.boundary, o.location), FALSE),
+            similarity(c.title, o.title)::float8
+        FROM candidate c
+        JOIN projects o ON o.id <> c.id
+        WHERE o.status = ANY(@statuses::text[]::project_status[])
+            AND (
+                ST_Intersects(c.boundary, o.boundary)
+                OR ST_DWithin(c.location, o.location, @nearby_m::float8)
+                OR ST_Covers(o.boundary, c.location)
+                OR ST_Covers(c.boundary, o.location)
+                OR (o.title % c.title AND similarity(c.title, o.title) >= @min_similarity::float8)
+            )
+        ORDER BY o.created_at, o.id
+    `
+
+	rows, err := r.s.DB.Pool.Query(ctx, query, pgx.NamedArgs{
+		"id":             id,
+		"statuses":       statusNames(statuses),
+		"nearby_m":       project.NearbyDistanceM,
+		"min_similarity": project.MinTitleSimilarity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conflicts := []project.Conflict{}
+	for rows.Next() {
+		var c project.Conflict
+		if err := rows.Scan(&c.ProjectID, &c.Title, &c.Status, &c.OverlapHa, &c.DistanceM, &c.Contains, &c.TitleSimilarity); err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, c)
+	}
+	return conflicts, rows.Err()
+}
+
+// ReplaceReviewWarnings stores warnings as the review warnings of project id,
+// dropping those of any earlier submission.
+func (r *ProjectRepository) ReplaceReviewWarnings(ctx context.Context, id string, warnings []project.ReviewWarning) error {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM project_review_warnings WHERE project_id = @id`, pgx.NamedArgs{"id": id}); err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO project_review_warnings (project_id, conflicting_project_id, kind, message)
+        VALUES (@project_id, @conflicting_project_id, @kind, @message)
+    `
+	for _, w := range warnings {
+		_, err := tx.Exec(ctx, query, pgx.NamedArgs{
+			"project_id":             id,
+			"conflicting_project_id": w.ConflictingProjectID,
+			"kind":                   w.Kind,
+			"message":                w.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListReviewWarnings returns the review warnings of the given projects keyed
+// by project, each with the conflicting project's current title and status.
+func (r *ProjectRepository) ListReviewWarnings(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]project.ReviewWarning, error) {
+	query := `
+        SELECT w.project_id, w.conflicting_project_id, o.title, o.status, w.kind, w.message, w.created_at
+        FROM project_review_warnings w
+        JOIN projects o ON o.id = w.conflicting_project_id
+        WHERE w.project_id = ANY(@ids::text[]::uuid[])
+        ORDER BY w.created_at, w.kind, o.title
+    `
+
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id.String()
+	}
+
+	rows, err := r.s.DB.Pool.Query(ctx, query, pgx.NamedArgs{"ids": names})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	warnings := map[uuid.UUID][]project.ReviewWarning{}
+	for rows.Next() {
+		var w project.ReviewWarning
+		err := rows.Scan(&w.ProjectID, &w.ConflictingProjectID, &w.ConflictingTitle, &w.ConflictingStatus, &w.Kind, &w.Message, &w.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		warnings[w.ProjectID] = append(warnings[w.ProjectID], w)
+	}
+	return warnings, rows.Err()
 }
 
 func (r *ProjectRepository) Delete(ctx context.Context, id string) error {
@@ -320,38 +699,256 @@ func (r *ProjectRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// UpdateStatus remains same but scan must include all fields
-func (r *ProjectRepository) UpdateStatus(ctx context.Context, id string, status project.ProjectStatus) (*project.Project, error) {
+// ErrInvalidTransition is returned when a status change is not an edge of the
+// project lifecycle.
+var ErrInvalidTransition = errors.New("invalid project status transition")
+
+const statusChangeColumns = `id, project_id, actor_id, from_status, to_status, reason_code, reason, created_at`
+
+func scanStatusChange(row pgx.Row) (*project.StatusChange, error) {
+	var c project.StatusChange
+	err := row.Scan(&c.ID, &c.ProjectID, &c.ActorID, &c.FromStatus, &c.ToStatus, &c.ReasonCode, &c.Reason, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// TransitionStatus moves a project from one status to another and records the
+// change in its history. It returns ErrInvalidTransition when the lifecycle
+// does not allow the move, and nil when the project is no longer in from.
+func (r *ProjectRepository) TransitionStatus(ctx context.Context, id string, from project.ProjectStatus, to project.ProjectStatus, actorID *string, reason *project.StatusReason) (*project.Project, error) {
+	if !from.CanTransitionTo(to) {
+		return nil, ErrInvalidTransition
+	}
+
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
         UPDATE projects
-        SET status = @status, updated_at = NOW()
-        WHERE id = @id
-        RETURNING 
-            id, supplier_id, title, description, image_url, audit_report_url,
-            location_lat, location_lng, area, 
-            carbon_amount_total, price_per_tonne,
-            contract_address, token_symbol,
-            status, created_at, updated_at
+        SET status = @to_status, updated_at = NOW()
+        WHERE id = @id AND status = @from_status
+        RETURNING ` + projectColumns + `
     `
 
 	args := pgx.NamedArgs{
-		"id":     id,
-		"status": status,
+		"id":          id,
+		"from_status": from,
+		"to_status":   to,
 	}
 
-	var p project.Project
-	err := r.s.DB.Pool.QueryRow(ctx, query, args).Scan(
-		&p.ID, &p.SupplierID, &p.Title, &p.Description, &p.ImageURL, &p.AuditReportURL,
-		&p.LocationLat, &p.LocationLng, &p.Area,
-		&p.CarbonAmount, &p.PricePerTonne,
-		&p.ContractAddress, &p.TokenSymbol,
-		&p.Status, &p.CreatedAt, &p.UpdatedAt,
-	)
+	p, err := scanProject(tx.QueryRow(ctx, query, args))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &p, nil
-}
\ No newline at end of file
+
+	if err := recordStatusChange(ctx, tx, id, from, to, actorID, reason); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func recordStatusChange(ctx context.Context, tx pgx.Tx, id string, from project.ProjectStatus, to project.ProjectStatus, actorID *string, reason *project.StatusReason) error {
+	query := `
+        INSERT INTO project_status_history (project_id, actor_id, from_status, to_status, reason_code, reason)
+        VALUES (@project_id, @actor_id, @from_status, @to_status, @reason_code, @reason)
+    `
+
+	args := pgx.NamedArgs{
+		"project_id":  id,
+		"actor_id":    actorID,
+		"from_status": from,
+		"to_status":   to,
+		"reason_code": nil,
+		"reason":      nil,
+	}
+	if reason != nil {
+		args["reason_code"] = reason.Code
+		args["reason"] = reason.Message
+	}
+
+	_, err := tx.Exec(ctx, query, args)
+	return err
+}
+
+// ListStatusHistory returns a project's status changes, oldest first.
+func (r *ProjectRepository) ListStatusHistory(ctx context.Context, id string) ([]project.StatusChange, error) {
+	query := `
+        SELECT ` + statusChangeColumns + `
+        FROM project_status_history
+        WHERE project_id = @project_id
+        ORDER BY created_at ASC, id ASC
+    `
+
+	rows, err := r.s.DB.Pool.Query(ctx, query, pgx.NamedArgs{"project_id": id})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []project.StatusChange{}
+	for rows.Next() {
+		c, err := scanStatusChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, *c)
+	}
+	return history, rows.Err()
+}
+
+// SetDeployTxHash records the pending createAsset transaction of an APPROVED
+// project. It only succeeds while no deployment has been recorded yet.
+func (r *ProjectRepository) SetDeployTxHash(ctx context.Context, id string, txHash string) (bool, error) {
+	query := `
+        UPDATE projects
+        SET deploy_tx_hash = @deploy_tx_hash, updated_at = NOW()
+        WHERE id = @id AND status = 'APPROVED' AND deploy_tx_hash IS NULL
+    `
+
+	args := pgx.NamedArgs{
+		"id":             id,
+		"deploy_tx_hash": txHash,
+	}
+
+	cmd, err := r.s.DB.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+// ClearDeployTxHash forgets the createAsset transaction of an APPROVED project
+// after it reverted, so the next deployment submits a new one. It only
+// succeeds while txHash is still the recorded transaction.
+func (r *ProjectRepository) ClearDeployTxHash(ctx context.Context, id string, txHash string) (bool, error) {
+	query := `
+        UPDATE projects
+        SET deploy_tx_hash = NULL, updated_at = NOW()
+        WHERE id = @id AND status = 'APPROVED' AND deploy_tx_hash = @deploy_tx_hash
+    `
+
+	args := pgx.NamedArgs{
+		"id":             id,
+		"deploy_tx_hash": txHash,
+	}
+
+	cmd, err := r.s.DB.Pool.Exec(ctx, query, args)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+// MarkDeployed stores the on-chain token of an APPROVED project and moves it
+// to DEPLOYED. Returns nil when the project is not in the APPROVED state.
+func (r *ProjectRepository) MarkDeployed(ctx context.Context, id string, contractAddress string, tokenSymbol string, txHash string) (*project.Project, error) {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        UPDATE projects
+        SET
+            contract_address = @contract_address,
+            token_symbol = @token_symbol,
+            deploy_tx_hash = @deploy_tx_hash,
+            status = 'DEPLOYED',
+            updated_at = NOW()
+        WHERE id = @id AND status = 'APPROVED'
+        RETURNING ` + projectColumns + `
+    `
+
+	args := pgx.NamedArgs{
+		"id":               id,
+		"contract_address": contractAddress,
+		"token_symbol":     tokenSymbol,
+		"deploy_tx_hash":   txHash,
+	}
+
+	p, err := scanProject(tx.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := recordStatusChange(ctx, tx, id, project.ProjectStatusApproved, project.ProjectStatusDeployed, nil, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// LinkDeployedAsset attaches a token seen on chain to the project whose
+// createAsset transaction produced it, moving an APPROVED project to
+// DEPLOYED. It lets the indexer reconcile deployments whose job never got to
+// MarkDeployed. Reports whether a project was updated.
+func (r *ProjectRepository) LinkDeployedAsset(ctx context.Context, deployTxHash string, contractAddress string, tokenSymbol string) (bool, error) {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        WITH prev AS (
+            SELECT id, status
+            FROM projects
+            WHERE deploy_tx_hash = @deploy_tx_hash AND contract_address IS NULL
+            FOR UPDATE
+        )
+        UPDATE projects p
+        SET
+            contract_address = @contract_address,
+            token_symbol = @token_symbol,
+            status = CASE WHEN prev.status = 'APPROVED' THEN 'DEPLOYED'::project_status ELSE prev.status END,
+            updated_at = NOW()
+        FROM prev
+        WHERE p.id = prev.id
+        RETURNING p.id::text, prev.status
+    `
+
+	args := pgx.NamedArgs{
+		"deploy_tx_hash":   deployTxHash,
+		"contract_address": contractAddress,
+		"token_symbol":     tokenSymbol,
+	}
+
+	var id string
+	var previous project.ProjectStatus
+	if err := tx.QueryRow(ctx, query, args).Scan(&id, &previous); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if previous == project.ProjectStatusApproved {
+		if err := recordStatusChange(ctx, tx, id, project.ProjectStatusApproved, project.ProjectStatusDeployed, nil, nil); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrDocumentOrderMismatch is returned when a reorder does not list exactly
+// the project's documents of that type.
+var ErrDocumentOrderMismatch = errors.New("reorder must list every document of the type exactly once")
+
+const documentColumns = `
+            id, project_id, type, object_key, url, file_name, content_type, size_bytes,
+            uploaded_by, position, created_at, updated_at`
+
+func scanDocument(row pgx.Row) (*project.Document, error) {
+	var d project.Document
+	err := row.Scan(
+		&d.ID, &d.ProjectID, &d.Type, &d.ObjectKey, &d.URL, &d.FileName, &d.ContentType, &d.SizeBytes,
+		&d.UploadedBy, &d.Position, &d.CreatedAt, &d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+type ProjectDocumentRepository struct {
+	s *server.Server
+}
+
+func NewProjectDocumentRepository(s *server.Server) *ProjectDocumentRepository {
+	return &ProjectDocumentRepository{s: s}
+}
+
+// Create adds d after the project's other documents of its type.
+func (r *ProjectDocumentRepository) Create(ctx context.Context, d project.Document) (*project.Document, error) {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        INSERT INTO project_documents (project_id, type, object_key, url, file_name, content_type, size_bytes, uploaded_by, position)
+        SELECT @project_id::uuid, @type::project_document_type, @object_key::text, @url::text,
+            @file_name::text, @content_type::text, @size_bytes::bigint, @uploaded_by::text,
+            COALESCE(MAX(position) + 1, 0)
+        FROM project_documents
+        WHERE project_id = @project_id AND type = @type
+        RETURNING ` + documentColumns + `
+    `
+
+	created, err := scanDocument(tx.QueryRow(ctx, query, documentArgs(d)))
+	if err != nil {
+		return nil, err
+	}
+	if err := syncPrimaryDocument(ctx, tx, d.ProjectID, d.Type); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ReplacePrimary puts d in place of the first document of its type and
+// returns the document it replaced, if any, so its object can be deleted.
+func (r *ProjectDocumentRepository) ReplacePrimary(ctx context.Context, d project.Document) (*project.Document, *project.Document, error) {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        DELETE FROM project_documents
+        WHERE id = (
+            SELECT id FROM project_documents
+            WHERE project_id = @project_id AND type = @type
+            ORDER BY position, created_at
+            LIMIT 1
+            FOR UPDATE
+        )
+        RETURNING ` + documentColumns + `
+    `
+
+	replaced, err := scanDocument(tx.QueryRow(ctx, query, documentArgs(d)))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, err
+		}
+		replaced = nil
+	}
+
+	args := documentArgs(d)
+	args["position"] = 0
+	if replaced != nil {
+		args["position"] = replaced.Position
+	}
+
+	query = `
+        INSERT INTO project_documents (project_id, type, object_key, url, file_name, content_type, size_bytes, uploaded_by, position)
+        VALUES (@project_id, @type, @object_key, @url, @file_name, @content_type, @size_bytes, @uploaded_by, @position)
+        RETURNING ` + documentColumns + `
+    `
+
+	created, err := scanDocument(tx.QueryRow(ctx, query, args))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := syncPrimaryDocument(ctx, tx, d.ProjectID, d.Type); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return created, replaced, nil
+}
+
+func documentArgs(d project.Document) pgx.NamedArgs {
+	return pgx.NamedArgs{
+		"project_id":   d.ProjectID,
+		"type":         d.Type,
+		"object_key":   d.ObjectKey,
+		"url":          d.URL,
+		"file_name":    d.FileName,
+		"content_type": d.ContentType,
+		"size_bytes":   d.SizeBytes,
+		"uploaded_by":  d.UploadedBy,
+	}
+}
+
+func (r *ProjectDocumentRepository) FindByID(ctx context.Context, id string) (*project.Document, error) {
+	query := `
+        SELECT ` + documentColumns + `
+        FROM project_documents
+        WHERE id = @id
+    `
+
+	d, err := scanDocument(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// ExistsByObjectKey reports whether any document refers to the object at key.
+func (r *ProjectDocumentRepository) ExistsByObjectKey(ctx context.Context, key string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM project_documents WHERE object_key = @object_key)`
+	err := r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"object_key": key}).Scan(&exists)
+	return exists, err
+}
+
+// ListByProject returns a project's documents grouped by type, each type in
+// position order.
+func (r *ProjectDocumentRepository) ListByProject(ctx context.Context, projectID string) ([]project.Document, error) {
+	query := `
+        SELECT ` + documentColumns + `
+        FROM project_documents
+        WHERE project_id = @project_id
+        ORDER BY type, position, created_at
+    `
+
+	rows, err := r.s.DB.Pool.Query(ctx, query, pgx.NamedArgs{"project_id": projectID})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	documents := []project.Document{}
+	for rows.Next() {
+		d, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, *d)
+	}
+	return documents, rows.Err()
+}
+
+// Delete removes a document. The caller deletes its object from the bucket.
+func (r *ProjectDocumentRepository) Delete(ctx context.Context, d project.Document) error {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM project_documents WHERE id = @id`, pgx.NamedArgs{"id": d.ID}); err != nil {
+		return err
+	}
+	if err := syncPrimaryDocument(ctx, tx, d.ProjectID, d.Type); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Reorder sets the order of a project's documents of type t to ids, which
+// must list each of them once.
+func (r *ProjectDocumentRepository) Reorder(ctx context.Context, projectID uuid.UUID, t project.DocumentType, ids []uuid.UUID) ([]project.Document, error) {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id.String()
+	}
+
+	query := `
+        UPDATE project_documents d
+        SET position = o.position - 1
+        FROM unnest(@ids::text[]::uuid[]) WITH ORDINALITY AS o(id, position)
+        WHERE d.id = o.id AND d.project_id = @project_id AND d.type = @type
+    `
+
+	args := pgx.NamedArgs{
+		"project_id": projectID,
+		"type":       t,
+		"ids":        names,
+	}
+
+	cmd, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM project_documents WHERE project_id = @project_id AND type = @type`
+	if err := tx.QueryRow(ctx, countQuery, args).Scan(&total); err != nil {
+		return nil, err
+	}
+	if int(cmd.RowsAffected()) != len(ids) || total != len(ids) {
+		return nil, ErrDocumentOrderMismatch
+	}
+
+	if err := syncPrimaryDocument(ctx, tx, projectID, t); err != nil {
+		return nil, err
+	}
+
+	query = `
+        SELECT ` + documentColumns + `
+        FROM project_documents
+        WHERE project_id = @project_id AND type = @type
+        ORDER BY position, created_at
+    `
+
+	rows, err := tx.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	documents := []project.Document{}
+	for rows.Next() {
+		d, err := scanDocument(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		documents = append(documents, *d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// syncPrimaryDocument mirrors the first image or audit report of a project
+// into projects.image_url or projects.audit_report_url, which the rest of the
+// backend reads.
+func syncPrimaryDocument(ctx context.Context, tx pgx.Tx, projectID uuid.UUID, t project.DocumentType) error {
+	var column string
+	switch t {
+	case project.DocumentImage:
+		column = "image_url"
+	case project.DocumentAuditReport:
+		column = "audit_report_url"
+	default:
+		return nil
+	}
+
+	query := `
+        UPDATE projects
+        SET ` + column + ` = COALESCE((
+            SELECT url FROM project_documents
+            WHERE project_id = @project_id AND type = @type
+            ORDER BY position, created_at
+            LIMIT 1
+        ), ''), updated_at = NOW()
+        WHERE id = @project_id
+    `
+
+	_, err := tx.Exec(ctx, query, pgx.NamedArgs{"project_id": projectID, "type": t})
+	return err
+}
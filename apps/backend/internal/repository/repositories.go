@@ -3,13 +3,27 @@ package repository
 import "github.com/inventedsarawak/ledgera/internal/server"
 
 type Repositories struct {
-	User    *UserRepository
-	Project *ProjectRepository
+	User            *UserRepository
+	Project         *ProjectRepository
+	Token           *TokenRepository
+	Listing         *ListingRepository
+	Order           *OrderRepository
+	Certificate     *CertificateRepository
+	ReviewComment   *ReviewCommentRepository
+	ProjectDocument *ProjectDocumentRepository
+	WebhookEvent    *WebhookEventRepository
 }
 
 func NewRepositories(s *server.Server) *Repositories {
 	return &Repositories{
-		User:    NewUserRepository(s),
-		Project: NewProjectRepository(s),
+		User:            NewUserRepository(s),
+		Project:         NewProjectRepository(s),
+		Token:           NewTokenRepository(s),
+		Listing:         NewListingRepository(s),
+		Order:           NewOrderRepository(s),
+		Certificate:     NewCertificateRepository(s),
+		ReviewComment:   NewReviewCommentRepository(s),
+		ProjectDocument: NewProjectDocumentRepository(s),
+		WebhookEvent:    NewWebhookEventRepository(s),
 	}
 }
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+const reviewCommentColumns = `
+            id, project_id, parent_id, author_id, author_role, field, body,
+            created_at, updated_at`
+
+func scanReviewComment(row pgx.Row) (*project.ReviewComment, error) {
+	var c project.ReviewComment
+	err := row.Scan(
+		&c.ID, &c.ProjectID, &c.ParentID, &c.AuthorID, &c.AuthorRole, &c.Field, &c.Body,
+		&c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+type ReviewCommentRepository struct {
+	s *server.Server
+}
+
+func NewReviewCommentRepository(s *server.Server) *ReviewCommentRepository {
+	return &ReviewCommentRepository{s: s}
+}
+
+func (r *ReviewCommentRepository) Create(ctx context.Context, c project.ReviewComment) (*project.ReviewComment, error) {
+	query := `
+        INSERT INTO project_review_comments (project_id, parent_id, author_id, author_role, field, body)
+        VALUES (@project_id, @parent_id, @author_id, @author_role, @field, @body)
+        RETURNING ` + reviewCommentColumns + `
+    `
+
+	args := pgx.NamedArgs{
+		"project_id":  c.ProjectID,
+		"parent_id":   c.ParentID,
+		"author_id":   c.AuthorID,
+		"author_role": c.AuthorRole,
+		"field":       c.Field,
+		"body":        c.Body,
+	}
+
+	return scanReviewComment(r.s.DB.Pool.QueryRow(ctx, query, args))
+}
+
+func (r *ReviewCommentRepository) FindByID(ctx context.Context, id string) (*project.ReviewComment, error) {
+	query := `
+        SELECT ` + reviewCommentColumns + `
+        FROM project_review_comments
+        WHERE id = @id
+    `
+
+	c, err := scanReviewComment(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListByProject returns every comment on a project, oldest first.
+func (r *ReviewCommentRepository) ListByProject(ctx context.Context, projectID string) ([]project.ReviewComment, error) {
+	query := `
+        SELECT ` + reviewCommentColumns + `
+        FROM project_review_comments
+        WHERE project_id = @project_id
+        ORDER BY created_at ASC, id ASC
+    `
+
+	rows, err := r.s.DB.Pool.Query(ctx, query, pgx.NamedArgs{"project_id": projectID})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []project.ReviewComment{}
+	for rows.Next() {
+		c, err := scanReviewComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *c)
+	}
+	return comments, rows.Err()
+}
@@ -0,0 +1,414 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/inventedsarawak/ledgera/internal/model/token"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrIssuanceExceedsTotal is returned by ReserveIssuance when the project's
+// verified carbon amount cannot cover the requested tonnes.
+var ErrIssuanceExceedsTotal = errors.New("issuance exceeds the verified carbon amount")
+
+const issuanceColumns = `
+            id, project_id, issued_by, recipient_address, tonnes, amount::text, decimals,
+            tx_hash, status, created_at, updated_at`
+
+func scanIssuance(row pgx.Row) (*token.Issuance, error) {
+	var i token.Issuance
+	var issuedBy *string
+	err := row.Scan(
+		&i.ID, &i.ProjectID, &issuedBy, &i.RecipientAddress, &i.Tonnes, &i.Amount, &i.Decimals,
+		&i.TxHash, &i.Status, &i.CreatedAt, &i.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if issuedBy != nil {
+		i.IssuedBy = *issuedBy
+	}
+	return &i, nil
+}
+
+type TokenRepository struct {
+	s *server.Server
+}
+
+func NewTokenRepository(s *server.Server) *TokenRepository {
+	return &TokenRepository{s: s}
+}
+
+// recomputeHoldingsQuery rebuilds token_holdings for the given
+// (contract, holder) pairs from the indexed transfers, which keeps it correct
+// no matter how often a block range is re-applied or rewound.
+const recomputeHoldingsQuery = `
+        INSERT INTO token_holdings (contract_address, holder_address, project_id, balance, updated_at)
+        SELECT
+            pair.contract_address,
+            pair.holder_address,
+            (SELECT p.id FROM projects p WHERE p.contract_address = pair.contract_address),
+            COALESCE((
+                SELECT SUM(t.amount) FROM token_transfers t
+                WHERE t.contract_address = pair.contract_address AND t.to_address = pair.holder_address
+            ), 0) - COALESCE((
+                SELECT SUM(t.amount) FROM token_transfers t
+                WHERE t.contract_address = pair.contract_address AND t.from_address = pair.holder_address
+            ), 0),
+            NOW()
+        FROM unnest(@contracts::text[], @holders::text[]) AS pair(contract_address, holder_address)
+        WHERE pair.holder_address NOT IN ('` + token.ZeroAddress + `', '` + token.RetirementAddress + `')
+        ON CONFLICT (contract_address, holder_address) DO UPDATE
+        SET
+            balance = EXCLUDED.balance,
+            project_id = EXCLUDED.project_id,
+            updated_at = NOW()
+    `
+
+func (r *TokenRepository) GetCheckpoint(ctx context.Context, name string) (*token.Checkpoint, error) {
+	query := `
+        SELECT name, last_block, last_block_hash, updated_at
+        FROM indexer_checkpoints
+        WHERE name = @name
+    `
+
+	var c token.Checkpoint
+	err := r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"name": name}).
+		Scan(&c.Name, &c.LastBlock, &c.LastBlockHash, &c.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// ListTokenContracts returns the token address of every deployed project.
+func (r *TokenRepository) ListTokenContracts(ctx context.Context) ([]string, error) {
+	rows, err := r.s.DB.Pool.Query(ctx, `SELECT contract_address FROM projects WHERE contract_address IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// SaveBatch stores the transfers of a processed block range, refreshes the
+// affected holdings and advances the checkpoint, all in one transaction.
+// Transfers that are already stored are ignored.
+func (r *TokenRepository) SaveBatch(ctx context.Context, transfers []token.Transfer, checkpoint token.Checkpoint) error {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := saveTransfers(ctx, tx, transfers); err != nil {
+		return err
+	}
+
+	if err := saveCheckpoint(ctx, tx, checkpoint); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Rewind drops every transfer above checkpoint.LastBlock, refreshes the
+// holdings they touched and resets the checkpoint, so the range is indexed
+// again from the canonical chain.
+func (r *TokenRepository) Rewind(ctx context.Context, checkpoint token.Checkpoint) error {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	deleteQuery := `
+        DELETE FROM token_transfers
+        WHERE block_number > @block_number
+        RETURNING contract_address, from_address, to_address
+    `
+
+	rows, err := tx.Query(ctx, deleteQuery, pgx.NamedArgs{"block_number": checkpoint.LastBlock})
+	if err != nil {
+		return err
+	}
+
+	var contracts, holders []string
+	for rows.Next() {
+		var contract, from, to string
+		if err := rows.Scan(&contract, &from, &to); err != nil {
+			rows.Close()
+			return err
+		}
+		contracts = append(contracts, contract, contract)
+		holders = append(holders, from, to)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(contracts) > 0 {
+		args := pgx.NamedArgs{"contracts": contracts, "holders": holders}
+		if _, err := tx.Exec(ctx, recomputeHoldingsQuery, args); err != nil {
+			return err
+		}
+	}
+
+	if err := saveCheckpoint(ctx, tx, checkpoint); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TokenRepository) FindHolding(ctx context.Context, contractAddress string, holderAddress string) (*token.Holding, error) {
+	query := `
+        SELECT contract_address, holder_address, project_id, balance::text, updated_at
+        FROM token_holdings
+        WHERE contract_address = @contract_address AND holder_address = @holder_address
+    `
+
+	args := pgx.NamedArgs{
+		"contract_address": contractAddress,
+		"holder_address":   holderAddress,
+	}
+
+	var h token.Holding
+	err := r.s.DB.Pool.QueryRow(ctx, query, args).
+		Scan(&h.ContractAddress, &h.HolderAddress, &h.ProjectID, &h.Balance, &h.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &h, nil
+}
+
+// saveTransfers inserts transfers that are not stored yet and refreshes the
+// holdings of every address they touch. A transfer is identified by its
+// (tx_hash, log_index), so recording the same one twice changes nothing.
+func saveTransfers(ctx context.Context, tx pgx.Tx, transfers []token.Transfer) error {
+	insertQuery := `
+        INSERT INTO token_transfers (
+            project_id, contract_address, from_address, to_address, amount,
+            tx_hash, log_index, block_number, block_hash
+        ) VALUES (
+            (SELECT id FROM projects WHERE contract_address = @contract_address),
+            @contract_address, @from_address, @to_address, @amount::numeric,
+            @tx_hash, @log_index, @block_number, @block_hash
+        )
+        ON CONFLICT (tx_hash, log_index) DO NOTHING
+    `
+
+	var contracts, holders []string
+	for _, t := range transfers {
+		args := pgx.NamedArgs{
+			"contract_address": t.ContractAddress,
+			"from_address":     t.FromAddress,
+			"to_address":       t.ToAddress,
+			"amount":           t.Amount,
+			"tx_hash":          t.TxHash,
+			"log_index":        t.LogIndex,
+			"block_number":     t.BlockNumber,
+			"block_hash":       t.BlockHash,
+		}
+		if _, err := tx.Exec(ctx, insertQuery, args); err != nil {
+			return err
+		}
+
+		contracts = append(contracts, t.ContractAddress, t.ContractAddress)
+		holders = append(holders, t.FromAddress, t.ToAddress)
+	}
+
+	if len(contracts) == 0 {
+		return nil
+	}
+	args := pgx.NamedArgs{"contracts": contracts, "holders": holders}
+	_, err := tx.Exec(ctx, recomputeHoldingsQuery, args)
+	return err
+}
+
+func saveCheckpoint(ctx context.Context, tx pgx.Tx, c token.Checkpoint) error {
+	query := `
+        INSERT INTO indexer_checkpoints (name, last_block, last_block_hash, updated_at)
+        VALUES (@name, @last_block, @last_block_hash, NOW())
+        ON CONFLICT (name) DO UPDATE
+        SET
+            last_block = EXCLUDED.last_block,
+            last_block_hash = EXCLUDED.last_block_hash,
+            updated_at = NOW()
+    `
+
+	args := pgx.NamedArgs{
+		"name":            c.Name,
+		"last_block":      c.LastBlock,
+		"last_block_hash": c.LastBlockHash,
+	}
+
+	_, err := tx.Exec(ctx, query, args)
+	return err
+}
+
+// RemainingIssuance returns how many verified tonnes of a project have not
+// been issued or reserved by a pending issuance yet.
+func (r *TokenRepository) RemainingIssuance(ctx context.Context, projectID string) (float64, error) {
+	query := `
+        SELECT p.carbon_amount_total - COALESCE((
+            SELECT SUM(i.tonnes) FROM token_issuances i
+            WHERE i.project_id = p.id AND i.status <> 'FAILED'
+        ), 0)
+        FROM projects p
+        WHERE p.id = @project_id
+    `
+
+	var remaining float64
+	err := r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"project_id": projectID}).Scan(&remaining)
+	if err != nil {
+		return 0, err
+	}
+	return remaining, nil
+}
+
+// ReserveIssuance inserts a PENDING issuance. The project row is locked while
+// the already issued tonnes are summed, so concurrent requests cannot issue
+// more than carbon_amount_total between them.
+func (r *TokenRepository) ReserveIssuance(ctx context.Context, i token.Issuance, tonnes string) (*token.Issuance, error) {
+	tx, err := r.s.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	lockQuery := `
+        SELECT carbon_amount_total >= @tonnes::numeric + COALESCE((
+            SELECT SUM(tonnes) FROM token_issuances
+            WHERE project_id = @project_id AND status <> 'FAILED'
+        ), 0)
+        FROM projects
+        WHERE id = @project_id
+        FOR UPDATE
+    `
+
+	var fits bool
+	err = tx.QueryRow(ctx, lockQuery, pgx.NamedArgs{"project_id": i.ProjectID, "tonnes": tonnes}).Scan(&fits)
+	if err != nil {
+		return nil, err
+	}
+	if !fits {
+		return nil, ErrIssuanceExceedsTotal
+	}
+
+	insertQuery := `
+        INSERT INTO token_issuances (
+            project_id, issued_by, recipient_address, tonnes, amount, decimals, status
+        ) VALUES (
+            @project_id, @issued_by, @recipient_address, @tonnes::numeric, @amount::numeric, @decimals, 'PENDING'
+        )
+        RETURNING ` + issuanceColumns + `
+    `
+
+	args := pgx.NamedArgs{
+		"project_id":        i.ProjectID,
+		"issued_by":         i.IssuedBy,
+		"recipient_address": i.RecipientAddress,
+		"tonnes":            tonnes,
+		"amount":            i.Amount,
+		"decimals":          i.Decimals,
+	}
+
+	issuance, err := scanIssuance(tx.QueryRow(ctx, insertQuery, args))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return issuance, nil
+}
+
+func (r *TokenRepository) FindIssuance(ctx context.Context, id string) (*token.Issuance, error) {
+	query := `
+        SELECT ` + issuanceColumns + `
+        FROM token_issuances
+        WHERE id = @id
+    `
+
+	i, err := scanIssuance(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return i, nil
+}
+
+func (r *TokenRepository) ListIssuancesByProject(ctx context.Context, projectID string) ([]token.Issuance, error) {
+	query := `
+        SELECT ` + issuanceColumns + `
+        FROM token_issuances
+        WHERE project_id = @project_id
+        ORDER BY created_at DESC
+    `
+
+	rows, err := r.s.DB.Pool.Query(ctx, query, pgx.NamedArgs{"project_id": projectID})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	issuances := []token.Issuance{}
+	for rows.Next() {
+		i, err := scanIssuance(rows)
+		if err != nil {
+			return nil, err
+		}
+		issuances = append(issuances, *i)
+	}
+	return issuances, rows.Err()
+}
+
+// SetIssuanceTxHash records the mint transaction of a PENDING issuance that
+// has none yet.
+func (r *TokenRepository) SetIssuanceTxHash(ctx context.Context, id string, txHash string) (bool, error) {
+	query := `
+        UPDATE token_issuances
+        SET tx_hash = @tx_hash
+        WHERE id = @id AND status = 'PENDING' AND tx_hash IS NULL
+    `
+
+	cmd, err := r.s.DB.Pool.Exec(ctx, query, pgx.NamedArgs{"id": id, "tx_hash": txHash})
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+// CompleteIssuance moves a PENDING issuance to CONFIRMED or FAILED.
+func (r *TokenRepository) CompleteIssuance(ctx context.Context, id string, status token.IssuanceStatus) (*token.Issuance, error) {
+	query := `
+        UPDATE token_issuances
+        SET status = @status
+        WHERE id = @id AND status = 'PENDING'
+        RETURNING ` + issuanceColumns + `
+    `
+
+	i, err := scanIssuance(r.s.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id, "status": status}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return i, nil
+}
@@ -3,12 +3,36 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/inventedsarawak/ledgera/internal/model/user"
 	"github.com/inventedsarawak/ledgera/internal/server"
 	"github.com/jackc/pgx/v5"
 )
 
+const userColumns = `id, clerk_id, email, wallet_address, wallet_verified_at, role, deleted_at, created_at, updated_at`
+
+// scanUser scans userColumns followed by any extra selected columns.
+func scanUser(row pgx.Row, extra ...any) (*user.User, error) {
+	var u user.User
+	dest := []any{
+		&u.ID,
+		&u.ClerkID,
+		&u.Email,
+		&u.WalletAddress,
+		&u.WalletVerifiedAt,
+		&u.Role,
+		&u.DeletedAt,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
 type UserRepository struct {
 	server *server.Server
 }
@@ -17,11 +41,17 @@ func NewUserRepository(server *server.Server) *UserRepository {
 	return &UserRepository{server: server}
 }
 
-func (r *UserRepository) UpsertUser(ctx context.Context, clerkID string, email string, role user.UserRole) (*user.User, error) {
+// UpsertUser inserts or refreshes the user with clerkID and reports whether
+// the row was newly inserted. A deleted user is left untouched and nil is
+// returned: deleted accounts are never revived.
+func (r *UserRepository) UpsertUser(ctx context.Context, clerkID string, email string, role user.UserRole) (*user.User, bool, error) {
 	// The Logic:
 	// 1. Insert the user with the provided role (defaults to BUYER when empty).
 	// 2. On conflict, update Email and Role to reflect the latest Clerk metadata.
 	// 3. Return the full User object (so we know their ID and Role).
+	// 4. xmax is 0 only for a freshly inserted row version; the ON CONFLICT
+	//    update path locks and rewrites the row, setting it.
+	// 5. A soft-deleted row fails the DO UPDATE condition and returns nothing.
 
 	if role == "" {
 		role = user.RoleBuyer
@@ -35,7 +65,8 @@ func (r *UserRepository) UpsertUser(ctx context.Context, clerkID string, email s
 			email = EXCLUDED.email,
 			role = EXCLUDED.role,
 			updated_at = NOW()
-		RETURNING id, clerk_id, email, wallet_address, role, deleted_at, created_at, updated_at
+		WHERE users.deleted_at IS NULL
+		RETURNING ` + userColumns + `, (xmax = 0) AS inserted
 	`
 
 	args := pgx.NamedArgs{
@@ -44,54 +75,417 @@ func (r *UserRepository) UpsertUser(ctx context.Context, clerkID string, email s
 		"role":     role,
 	}
 
-	var u user.User
 	// Persist user with resolved role from Clerk metadata
-	err := r.server.DB.Pool.QueryRow(ctx, query, args).Scan(
-		&u.ID,
-		&u.ClerkID,
-		&u.Email,
-		&u.WalletAddress,
-		&u.Role,
-		&u.DeletedAt,
-		&u.CreatedAt,
-		&u.UpdatedAt,
-	)
+	var inserted bool
+	u, err := scanUser(r.server.DB.Pool.QueryRow(ctx, query, args), &inserted)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return u, inserted, nil
+}
+
+func (r *UserRepository) FindByClerkID(ctx context.Context, clerkID string) (*user.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE clerk_id = @clerk_id AND deleted_at IS NULL
+	`
 
+	args := pgx.NamedArgs{
+		"clerk_id": clerkID,
+	}
+
+	u, err := scanUser(r.server.DB.Pool.QueryRow(ctx, query, args))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
-	return &u, nil
+	return u, nil
 }
 
-func (r *UserRepository) FindByClerkID(ctx context.Context, clerkID string) (*user.User, error) {
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*user.User, error) {
 	query := `
-		SELECT id, clerk_id, email, wallet_address, role, deleted_at, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE clerk_id = @clerk_id
+		WHERE id = @id AND deleted_at IS NULL
+	`
+
+	u, err := scanUser(r.server.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"id": id}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// ListPaginated returns a page of users, newest first, optionally filtered by
+// role and by a case-insensitive substring of their email.
+func (r *UserRepository) ListPaginated(ctx context.Context, role *user.UserRole, search *string, page int, limit int) ([]user.User, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	offset := (page - 1) * limit
+
+	where := `
+		WHERE deleted_at IS NULL
+		  AND (@role::user_role IS NULL OR role = @role::user_role)
+		  AND (@search::text IS NULL OR email ILIKE '%' || @search::text || '%')`
+
+	listQuery := `
+		SELECT ` + userColumns + `
+		FROM users` + where + `
+		ORDER BY created_at DESC
+		LIMIT @limit OFFSET @offset
 	`
 
 	args := pgx.NamedArgs{
+		"role":   role,
+		"search": search,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, listQuery, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []user.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, *u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	err = r.server.DB.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM users`+where, args).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// Update changes the wallet and/or role of the user with id; nil fields are
+// left as they are. Changing the wallet clears its verification. It returns
+// nil when there is no such user.
+func (r *UserRepository) Update(ctx context.Context, id string, walletAddress *string, role *user.UserRole) (*user.User, error) {
+	query := `
+		UPDATE users
+		SET
+			wallet_address = COALESCE(@wallet_address, wallet_address),
+			wallet_verified_at = CASE
+				WHEN @wallet_address::text IS NOT NULL
+					AND LOWER(@wallet_address::text) IS DISTINCT FROM LOWER(wallet_address) THEN NULL
+				ELSE wallet_verified_at
+			END,
+			role = COALESCE(@role::user_role, role),
+			updated_at = NOW()
+		WHERE id = @id AND deleted_at IS NULL
+		RETURNING ` + userColumns + `
+	`
+
+	u, err := scanUser(r.server.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{
+		"id":             id,
+		"wallet_address": walletAddress,
+		"role":           role,
+	}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// ErrWalletVerifiedElsewhere is returned by VerifyWallet when another account
+// has already proved ownership of the wallet.
+var ErrWalletVerifiedElsewhere = errors.New("wallet is verified by another account")
+
+// VerifyWallet links address to the user with clerkID as a verified wallet.
+// An unverified claim on the same address by another account is released, so
+// a wallet cannot be squatted by typing it in. It returns nil when there is
+// no such user.
+func (r *UserRepository) VerifyWallet(ctx context.Context, clerkID string, address string) (*user.User, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{"clerk_id": clerkID, "wallet_address": address}
+
+	var verifiedElsewhere bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM users
+			WHERE LOWER(wallet_address) = LOWER(@wallet_address)
+				AND clerk_id <> @clerk_id
+				AND wallet_verified_at IS NOT NULL
+				AND deleted_at IS NULL
+		)
+	`, args).Scan(&verifiedElsewhere)
+	if err != nil {
+		return nil, err
+	}
+	if verifiedElsewhere {
+		return nil, ErrWalletVerifiedElsewhere
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE users
+		SET wallet_address = NULL, updated_at = NOW()
+		WHERE LOWER(wallet_address) = LOWER(@wallet_address) AND clerk_id <> @clerk_id
+	`, args)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := scanUser(tx.QueryRow(ctx, `
+		UPDATE users
+		SET wallet_address = @wallet_address, wallet_verified_at = NOW(), updated_at = NOW()
+		WHERE clerk_id = @clerk_id AND deleted_at IS NULL
+		RETURNING `+userColumns+`
+	`, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// UpdateRole sets the role of the user with clerkID. It returns nil when there
+// is no such user.
+func (r *UserRepository) UpdateRole(ctx context.Context, clerkID string, role user.UserRole) (*user.User, error) {
+	query := `
+		UPDATE users
+		SET role = @role, updated_at = NOW()
+		WHERE clerk_id = @clerk_id AND deleted_at IS NULL
+		RETURNING ` + userColumns + `
+	`
+
+	u, err := scanUser(r.server.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{
 		"clerk_id": clerkID,
+		"role":     role,
+	}))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
 	}
+	return u, nil
+}
 
-	var u user.User
-	err := r.server.DB.Pool.QueryRow(ctx, query, args).Scan(
-		&u.ID,
-		&u.ClerkID,
-		&u.Email,
-		&u.WalletAddress,
-		&u.Role,
-		&u.DeletedAt,
-		&u.CreatedAt,
-		&u.UpdatedAt,
-	)
+// WithdrawnPDF is a rendered certificate PDF that SoftDelete took off its
+// certificate because it names the deleted user.
+type WithdrawnPDF struct {
+	CertificateID string
+	URL           string
+}
+
+// SoftDelete marks the user with clerkID as deleted and strips their contact
+// details: the email is replaced by a placeholder and the wallet is unlinked
+// so another account can claim it. Certificates that name the user by their
+// email name the retiring wallet instead, and lose their PDF so it can be
+// rendered again; the PDFs taken off are returned. It reports false when
+// there is no such user or they were already deleted.
+func (r *UserRepository) SoftDelete(ctx context.Context, clerkID string) (bool, []WithdrawnPDF, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return false, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{"clerk_id": clerkID}
+
+	rows, err := tx.Query(ctx, `
+		WITH named AS (
+			SELECT c.id, c.pdf_url
+			FROM certificates c
+			JOIN users u ON u.clerk_id = c.owner_id
+			WHERE u.clerk_id = @clerk_id
+			  AND u.deleted_at IS NULL
+			  AND c.beneficiary_name = u.email
+			FOR UPDATE OF c
+		)
+		UPDATE certificates c
+		SET
+			beneficiary_name = COALESCE(c.wallet_address, 'Anonymous'),
+			pdf_url = NULL
+		FROM named
+		WHERE c.id = named.id
+		RETURNING c.id::text, named.pdf_url
+	`, args)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var withdrawn []WithdrawnPDF
+	for rows.Next() {
+		var id string
+		var url *string
+		if err := rows.Scan(&id, &url); err != nil {
+			rows.Close()
+			return false, nil, err
+		}
+		if url != nil {
+			withdrawn = append(withdrawn, WithdrawnPDF{CertificateID: id, URL: *url})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, nil, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE users
+		SET
+			email = 'deleted-' || id::text || '@users.invalid',
+			wallet_address = NULL,
+			wallet_verified_at = NULL,
+			deleted_at = NOW(),
+			updated_at = NOW()
+		WHERE clerk_id = @clerk_id AND deleted_at IS NULL
+	`, args)
+	if err != nil {
+		return false, nil, err
+	}
+	if tag.RowsAffected() != 1 {
+		return false, nil, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, nil, err
+	}
+	return true, withdrawn, nil
+}
+
+// purgeableProjects selects the unsubmitted projects of users soft-deleted
+// before @cutoff, which PurgeDeleted removes with them.
+const purgeableProjects = `
+	SELECT id FROM projects
+	WHERE status IN ('DRAFT', 'REJECTED')
+	  AND supplier_id IN (
+		SELECT clerk_id FROM users
+		WHERE deleted_at IS NOT NULL AND deleted_at < @cutoff
+	  )
+`
+
+// ListPurgeableDocumentKeys returns the bucket keys of the documents attached
+// to the projects PurgeDeleted would remove for cutoff.
+func (r *UserRepository) ListPurgeableDocumentKeys(ctx context.Context, cutoff time.Time) ([]string, error) {
+	rows, err := r.server.DB.Pool.Query(ctx, `
+		SELECT object_key FROM project_documents
+		WHERE project_id IN (`+purgeableProjects+`)
+	`, pgx.NamedArgs{"cutoff": cutoff})
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// PurgeDeleted hard-deletes users that were soft-deleted before cutoff,
+// together with their unsubmitted (DRAFT or REJECTED) projects. Everything
+// else they took part in, such as orders, certificates and reviewed projects,
+// is kept and still carries their Clerk ID. The projects' documents are
+// removed from the database only; see ListPurgeableDocumentKeys.
+func (r *UserRepository) PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{"cutoff": cutoff}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM projects
+		WHERE id IN (`+purgeableProjects+`)
+	`, args)
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		DELETE FROM users
+		WHERE deleted_at IS NOT NULL AND deleted_at < @cutoff
+	`, args)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// FindByWalletAddress returns the user that linked address, matched
+// case-insensitively.
+func (r *UserRepository) FindByWalletAddress(ctx context.Context, address string) (*user.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE LOWER(wallet_address) = LOWER(@wallet_address) AND deleted_at IS NULL
+	`
+
+	u, err := scanUser(r.server.DB.Pool.QueryRow(ctx, query, pgx.NamedArgs{"wallet_address": address}))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
+	return u, nil
+}
 
-	return &u, nil
+// ListByRole returns every user with role, oldest first.
+func (r *UserRepository) ListByRole(ctx context.Context, role user.UserRole) ([]user.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE role = @role AND deleted_at IS NULL
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, query, pgx.NamedArgs{"role": role})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []user.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *u)
+	}
+	return users, rows.Err()
 }
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type WebhookEventRepository struct {
+	s *server.Server
+}
+
+func NewWebhookEventRepository(s *server.Server) *WebhookEventRepository {
+	return &WebhookEventRepository{s: s}
+}
+
+// Record claims the delivery id from source. It reports false when the
+// delivery was already recorded, i.e. it is a retry or a replay.
+func (r *WebhookEventRepository) Record(ctx context.Context, source, id, eventType string) (bool, error) {
+	query := `
+        INSERT INTO webhook_events (source, id, event_type)
+        VALUES (@source, @id, @event_type)
+        ON CONFLICT (source, id) DO NOTHING
+    `
+
+	tag, err := r.s.DB.Pool.Exec(ctx, query, pgx.NamedArgs{
+		"source":     source,
+		"id":         id,
+		"event_type": eventType,
+	})
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Forget releases a delivery that failed to process so the provider's retry
+// is handled instead of being dropped as a duplicate.
+func (r *WebhookEventRepository) Forget(ctx context.Context, source, id string) error {
+	query := `
+        DELETE FROM webhook_events
+        WHERE source = @source AND id = @id
+    `
+
+	_, err := r.s.DB.Pool.Exec(ctx, query, pgx.NamedArgs{"source": source, "id": id})
+	return err
+}
@@ -57,6 +57,9 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services
 	// register system routes
 	registerSystemRoutes(router, h)
 
+	// register webhook receivers
+	registerWebhookRoutes(router, h)
+
 	// register versioned routes
 	// return welcome to API message at root
 
@@ -67,6 +70,11 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services
 
 	v1.RegisterAuthRoutes(v1Router, h.Auth, middlewares.Auth)
 	v1.RegisterProjectRoutes(v1Router, h.Project, middlewares.Auth)
+	v1.RegisterAssetRoutes(v1Router, h.Asset, middlewares.Auth)
+	v1.RegisterListingRoutes(v1Router, h.Listing, middlewares.Auth)
+	v1.RegisterOrderRoutes(v1Router, h.Order, middlewares.Auth)
+	v1.RegisterCertificateRoutes(v1Router, h.Certificate, middlewares.Auth)
+	v1.RegisterUserRoutes(v1Router, h.User, middlewares.Auth)
 
 	return router
 }
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"github.com/inventedsarawak/ledgera/internal/handler"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterAssetRoutes(g *echo.Group, h *handler.AssetHandler, auth *middleware.AuthMiddleware) {
+	assetGroup := g.Group("/projects")
+
+	// Protected routes (admin checks happen in the service)
+	assetGroup.Use(auth.RequireAuth)
+
+	assetGroup.POST("/:id/deploy", h.Deploy)
+	assetGroup.POST("/:id/mint", h.Mint)
+	assetGroup.GET("/:id/issuances", h.ListIssuances)
+}
@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"github.com/inventedsarawak/ledgera/internal/handler"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterCertificateRoutes(g *echo.Group, h *handler.CertificateHandler, auth *middleware.AuthMiddleware) {
+	retireGroup := g.Group("/projects")
+	retireGroup.Use(auth.RequireAuth)
+	retireGroup.POST("/:id/retire", h.Retire)
+
+	certificateGroup := g.Group("/certificates")
+
+	// Protected routes
+	certificateGroup.Use(auth.RequireAuth)
+
+	certificateGroup.GET("", h.ListMine)
+	certificateGroup.GET("/:id", h.GetByID)
+
+	// Public verification, no auth: anyone holding a certificate can check it
+	publicGroup := g.Group("/public/certificates")
+	publicGroup.GET("/:id", h.Verify)
+	publicGroup.GET("/tx/:txHash", h.VerifyByTxHash)
+}
@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"github.com/inventedsarawak/ledgera/internal/handler"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterListingRoutes(g *echo.Group, h *handler.ListingHandler, auth *middleware.AuthMiddleware) {
+	listingGroup := g.Group("/listings")
+
+	// Protected routes
+	listingGroup.Use(auth.RequireAuth)
+
+	listingGroup.GET("", h.List)
+	listingGroup.POST("", h.Create)
+	listingGroup.GET("/:id", h.GetByID)
+	listingGroup.PATCH("/:id", h.Update)
+	listingGroup.DELETE("/:id", h.Deactivate)
+}
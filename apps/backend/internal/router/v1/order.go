@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/inventedsarawak/ledgera/internal/handler"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterOrderRoutes(g *echo.Group, h *handler.OrderHandler, auth *middleware.AuthMiddleware) {
+	orderGroup := g.Group("/orders")
+
+	// Protected routes
+	orderGroup.Use(auth.RequireAuth)
+
+	orderGroup.GET("", h.ListMine)
+	orderGroup.POST("", h.Place)
+	orderGroup.GET("/:id", h.GetByID)
+	orderGroup.POST("/:id/pay", h.Pay)
+	orderGroup.POST("/:id/settle", h.Settle)
+	orderGroup.POST("/:id/cancel", h.Cancel)
+}
@@ -7,6 +7,12 @@ import (
 )
 
 func RegisterProjectRoutes(g *echo.Group, h *handler.ProjectHandler, auth *middleware.AuthMiddleware) {
+	// Public browse of reviewed projects
+	publicGroup := g.Group("/public/projects")
+	publicGroup.GET("", h.Browse)
+	publicGroup.GET("/map", h.InBBox)
+	publicGroup.GET("/nearby", h.Near)
+
 	projectGroup := g.Group("/projects")
 
 	// Protected routes
@@ -16,9 +22,21 @@ func RegisterProjectRoutes(g *echo.Group, h *handler.ProjectHandler, auth *middl
 	projectGroup.GET("/mine", h.ListMine)
 	projectGroup.GET("/review", h.ListPendingReview)
 	projectGroup.GET("/:id", h.GetByID)
+	projectGroup.GET("/:id/fact-sheet", h.FactSheet)
+	projectGroup.GET("/:id/history", h.History)
+	projectGroup.GET("/:id/boundary", h.Boundary)
+	projectGroup.PUT("/:id/boundary", h.SetBoundary)
+	projectGroup.GET("/:id/documents", h.ListDocuments)
+	projectGroup.POST("/:id/documents", h.AddDocument)
+	projectGroup.PUT("/:id/documents/order", h.ReorderDocuments)
+	projectGroup.POST("/:id/documents/uploads", h.PresignDocument)
+	projectGroup.POST("/:id/documents/uploads/confirm", h.ConfirmDocument)
+	projectGroup.DELETE("/:id/documents/:documentId", h.DeleteDocument)
 	projectGroup.PATCH("/:id", h.Update)
 	projectGroup.DELETE("/:id", h.Delete)
 	projectGroup.POST("/:id/submit", h.SendForApproval)
 	projectGroup.POST("/:id/approve", h.Approve)
 	projectGroup.POST("/:id/reject", h.Reject)
+	projectGroup.GET("/:id/comments", h.ListComments)
+	projectGroup.POST("/:id/comments", h.AddComment)
 }
@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"github.com/inventedsarawak/ledgera/internal/handler"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func RegisterUserRoutes(g *echo.Group, h *handler.UserHandler, auth *middleware.AuthMiddleware) {
+	// The caller's own account
+	meGroup := g.Group("/me")
+	meGroup.Use(auth.RequireAuth)
+
+	meGroup.GET("", h.GetMe)
+	meGroup.PATCH("", h.UpdateMe)
+	meGroup.DELETE("", h.DeleteMe)
+
+	// Wallet ownership: sign the challenge, then submit the signature
+	meGroup.POST("/wallet/challenge", h.WalletChallenge)
+	meGroup.POST("/wallet/verify", h.VerifyWallet)
+
+	// Admin user directory
+	userGroup := g.Group("/users")
+	userGroup.Use(auth.RequireAuth)
+
+	userGroup.GET("", h.List)
+	userGroup.PATCH("/:id", h.Update)
+}
@@ -0,0 +1,14 @@
+package router
+
+import (
+	"github.com/inventedsarawak/ledgera/internal/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// registerWebhookRoutes mounts the endpoints third parties call back into.
+// They authenticate with request signatures rather than user sessions.
+func registerWebhookRoutes(r *echo.Echo, h *handler.Handlers) {
+	webhooks := r.Group("/webhooks")
+
+	webhooks.POST("/clerk", h.Auth.ClerkWebhook)
+}
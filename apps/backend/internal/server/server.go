@@ -10,6 +10,7 @@ import (
 	"github.com/inventedsarawak/ledgera/internal/blockchain"
 	"github.com/inventedsarawak/ledgera/internal/config"
 	"github.com/inventedsarawak/ledgera/internal/database"
+	"github.com/inventedsarawak/ledgera/internal/lib/email"
 	"github.com/inventedsarawak/ledgera/internal/lib/job"
 	"github.com/inventedsarawak/ledgera/internal/lib/upload"
 	loggerPkg "github.com/inventedsarawak/ledgera/internal/logger"
@@ -28,6 +29,7 @@ type Server struct {
 	httpServer    *http.Server
 	Job           *job.JobService
 	Uploader      *upload.Client
+	Email         *email.Client
 }
 
 func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.LoggerService) (*Server, error) {
@@ -55,9 +57,14 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		// Don't fail startup if Redis is unavailable
 	}
 
+	emailClient, err := email.NewClient(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize email client: %w", err)
+	}
+
 	// job service
 	jobService := job.NewJobService(logger, cfg)
-	jobService.InitHandlers(cfg, logger)
+	jobService.InitHandlers(emailClient)
 
 	// Start job server
 	if err := jobService.Start(); err != nil {
@@ -93,6 +100,7 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		Blockchain:    blockchainClient,
 		Job:           jobService,
 		Uploader:      uploader,
+		Email:         emailClient,
 	}
 
 	// Start metrics collection
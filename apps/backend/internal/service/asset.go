@@ -1 +1,470 @@
-package service
\ No newline at end of file
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hibiken/asynq"
+	"github.com/inventedsarawak/ledgera/internal/blockchain"
+	"github.com/inventedsarawak/ledgera/internal/lib/job"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/token"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// AssetService owns everything that touches the chain for a project:
+// deploying its token through the AssetRegistry and minting its verified
+// carbon credits.
+type AssetService struct {
+	server      *server.Server
+	projectRepo *repository.ProjectRepository
+	userRepo    *repository.UserRepository
+	tokenRepo   *repository.TokenRepository
+	notifier    *notifier
+}
+
+func NewAssetService(s *server.Server, projectRepo *repository.ProjectRepository, userRepo *repository.UserRepository, tokenRepo *repository.TokenRepository) *AssetService {
+	return &AssetService{
+		server:      s,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		notifier:    newNotifier(s, userRepo),
+	}
+}
+
+// RequestDeploy is the admin entry point. It validates the project and hands
+// the deployment to the job queue, since waiting for a receipt can take far
+// longer than an HTTP request should.
+func (s *AssetService) RequestDeploy(ctx echo.Context, id string, adminID string, symbol string) error {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("admin_id", adminID).Msg("requesting project deployment")
+
+	if err := ensureAdmin(ctx, s.userRepo, adminID); err != nil {
+		return err
+	}
+
+	existing, err := s.projectRepo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	if existing.Status != project.ProjectStatusApproved {
+		return echo.NewHTTPError(http.StatusBadRequest, "Only approved projects can be deployed")
+	}
+	if s.server.Blockchain == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "Blockchain client is not available")
+	}
+
+	// Without a job server (tests, local scripts) deploy inline.
+	if s.server.Job == nil {
+		return s.DeployProject(ctx.Request().Context(), id, symbol)
+	}
+
+	task, err := job.NewDeployProjectTask(id, symbol)
+	if err != nil {
+		return err
+	}
+
+	// A deploy that failed for good is archived; it is replaced so the
+	// project can be deployed again.
+	if err := s.server.Job.EnqueueRetryable(ctx.Request().Context(), task, job.DeployProjectQueue, job.DeployProjectTaskID(id)); err != nil {
+		if errors.Is(err, asynq.ErrTaskIDConflict) {
+			return echo.NewHTTPError(http.StatusConflict, "Deployment already in progress")
+		}
+		logger.Error().Err(err).Msg("failed to enqueue deploy task")
+		return err
+	}
+
+	return nil
+}
+
+// HandleDeployProjectTask is the asynq handler for job.TaskDeployProject.
+func (s *AssetService) HandleDeployProjectTask(ctx context.Context, t *asynq.Task) error {
+	var p job.DeployProjectPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal deploy project payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return s.DeployProject(ctx, p.ProjectID, p.Symbol)
+}
+
+// DeployProject creates the project's token through AssetRegistry.createAsset
+// and records the resulting address and symbol. It is safe to retry: once the
+// transaction hash is stored, later attempts only wait for that transaction.
+// A reverted transaction is forgotten so the project can be deployed again.
+func (s *AssetService) DeployProject(ctx context.Context, id string, symbol string) error {
+	logger := s.server.Logger.With().Str("project_id", id).Logger()
+
+	p, err := s.projectRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		logger.Warn().Msg("project to deploy no longer exists")
+		return nil
+	}
+	if p.Status == project.ProjectStatusDeployed {
+		// The indexer may have linked the token before this job got to it;
+		// the supplier is still told, once.
+		logger.Info().Msg("project already deployed")
+		s.notifier.ProjectDeployed(ctx, p)
+		return nil
+	}
+	if p.Status != project.ProjectStatusApproved {
+		return fmt.Errorf("project %s is %s, not APPROVED: %w", id, p.Status, asynq.SkipRetry)
+	}
+
+	if s.server.Blockchain == nil {
+		return errors.New("blockchain client is not available")
+	}
+	registry, err := s.server.Blockchain.Registry()
+	if err != nil {
+		return fmt.Errorf("failed to bind asset registry: %v: %w", err, asynq.SkipRetry)
+	}
+
+	var txHash common.Hash
+	if p.DeployTxHash != nil {
+		txHash = common.HexToHash(*p.DeployTxHash)
+		logger.Info().Str("tx_hash", txHash.Hex()).Msg("resuming pending deployment")
+	} else {
+		if symbol == "" {
+			symbol = tokenSymbol(p)
+		}
+
+		txHash, err = registry.SendCreateAsset(ctx, p.Title, symbol)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to submit createAsset")
+			return err
+		}
+
+		stored, err := s.projectRepo.SetDeployTxHash(ctx, id, txHash.Hex())
+		if err != nil {
+			return err
+		}
+		if !stored {
+			return fmt.Errorf("project %s changed while deploying: %w", id, asynq.SkipRetry)
+		}
+		logger.Info().Str("tx_hash", txHash.Hex()).Msg("createAsset submitted")
+	}
+
+	created, err := registry.WaitAssetCreated(ctx, txHash)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrTransactionReverted) {
+			return s.failDeploy(ctx, id, txHash, err)
+		}
+		logger.Error().Err(err).Str("tx_hash", txHash.Hex()).Msg("createAsset did not complete")
+		return err
+	}
+
+	updated, err := s.projectRepo.MarkDeployed(ctx, id, created.AssetAddress.Hex(), created.Symbol, txHash.Hex())
+	if err != nil {
+		return err
+	}
+	if updated == nil {
+		// The indexer saw AssetCreated first and already linked this token
+		updated, err = s.projectRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if updated == nil || updated.Status != project.ProjectStatusDeployed ||
+			updated.DeployTxHash == nil || *updated.DeployTxHash != txHash.Hex() ||
+			updated.ContractAddress == nil || !strings.EqualFold(*updated.ContractAddress, created.AssetAddress.Hex()) {
+			return fmt.Errorf("project %s left APPROVED during deployment: %w", id, asynq.SkipRetry)
+		}
+		logger.Info().Msg("deployment already linked by the indexer")
+	}
+
+	logger.Info().
+		Str("contract_address", created.AssetAddress.Hex()).
+		Str("token_symbol", created.Symbol).
+		Msg("project deployed")
+
+	s.notifier.ProjectDeployed(ctx, updated)
+	return nil
+}
+
+// failDeploy forgets a reverted createAsset transaction, leaving the project
+// APPROVED so a new deployment can be requested, and stops the job from
+// retrying.
+func (s *AssetService) failDeploy(ctx context.Context, id string, txHash common.Hash, cause error) error {
+	s.server.Logger.Error().Err(cause).Str("project_id", id).Str("tx_hash", txHash.Hex()).Msg("deployment failed")
+
+	if _, err := s.projectRepo.ClearDeployTxHash(ctx, id, txHash.Hex()); err != nil {
+		return err
+	}
+	return fmt.Errorf("project %s deployment failed: %v: %w", id, cause, asynq.SkipRetry)
+}
+
+// RequestMint reserves an issuance of the project's verified carbon credits
+// for the supplier's wallet and hands the mint to the job queue. Tonnes
+// defaults to everything not issued yet; asking for more than that is refused.
+func (s *AssetService) RequestMint(ctx echo.Context, id string, adminID string, tonnes float64) (*token.Issuance, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("admin_id", adminID).Msg("requesting token mint")
+
+	if err := ensureAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	reqCtx := ctx.Request().Context()
+	existing, err := s.projectRepo.FindByID(reqCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	if existing.Status != project.ProjectStatusDeployed || existing.ContractAddress == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Only deployed projects can be minted")
+	}
+	if s.server.Blockchain == nil {
+		return nil, echo.NewHTTPError(http.StatusServiceUnavailable, "Blockchain client is not available")
+	}
+
+	supplier, err := s.userRepo.FindByClerkID(reqCtx, existing.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil || supplier.WalletAddress == nil || !common.IsHexAddress(*supplier.WalletAddress) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Supplier has no wallet address")
+	}
+	// Credits are only minted to a wallet the supplier proved they control
+	if !supplier.HasVerifiedWallet() {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Supplier has not verified their wallet")
+	}
+
+	if tonnes == 0 {
+		remaining, err := s.tokenRepo.RemainingIssuance(reqCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		tonnes = remaining
+	}
+	// carbon_amount_total and issuances are NUMERIC(20, 2)
+	amountTonnes := strconv.FormatFloat(tonnes, 'f', 2, 64)
+	if tonnes <= 0 || amountTonnes == "0.00" {
+		return nil, echo.NewHTTPError(http.StatusConflict, "Verified carbon amount is already fully issued")
+	}
+
+	tokenContract, err := s.server.Blockchain.Token(common.HexToAddress(*existing.ContractAddress))
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := tokenDecimals(reqCtx, s.server.Blockchain, tokenContract)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to read token decimals")
+		return nil, err
+	}
+
+	amount, err := blockchain.ToBaseUnits(amountTonnes, decimals)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Tonnes cannot be represented with the token's decimals")
+	}
+
+	issuance, err := s.tokenRepo.ReserveIssuance(reqCtx, token.Issuance{
+		ProjectID:        existing.ID,
+		IssuedBy:         adminID,
+		RecipientAddress: common.HexToAddress(*supplier.WalletAddress).Hex(),
+		Amount:           amount.String(),
+		Decimals:         decimals,
+	}, amountTonnes)
+	if err != nil {
+		if errors.Is(err, repository.ErrIssuanceExceedsTotal) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "Requested tonnes exceed the verified carbon amount")
+		}
+		logger.Error().Err(err).Msg("failed to reserve issuance")
+		return nil, err
+	}
+
+	// Without a job server (tests, local scripts) mint inline.
+	if s.server.Job == nil {
+		if err := s.MintIssuance(reqCtx, issuance.ID.String()); err != nil {
+			return nil, err
+		}
+		return s.tokenRepo.FindIssuance(reqCtx, issuance.ID.String())
+	}
+
+	task, err := job.NewMintTokensTask(issuance.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.server.Job.Client.EnqueueContext(reqCtx, task); err != nil {
+		logger.Error().Err(err).Msg("failed to enqueue mint task")
+		return nil, err
+	}
+
+	return issuance, nil
+}
+
+// ListIssuances returns a project's issuances to an admin or its supplier.
+func (s *AssetService) ListIssuances(ctx echo.Context, id string, userID string) ([]token.Issuance, error) {
+	existing, err := s.projectRepo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	if existing.SupplierID != userID {
+		if err := ensureAdmin(ctx, s.userRepo, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.tokenRepo.ListIssuancesByProject(ctx.Request().Context(), id)
+}
+
+// HandleMintTokensTask is the asynq handler for job.TaskMintTokens.
+func (s *AssetService) HandleMintTokensTask(ctx context.Context, t *asynq.Task) error {
+	var p job.MintTokensPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal mint tokens payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return s.MintIssuance(ctx, p.IssuanceID)
+}
+
+// MintIssuance mints a reserved issuance and records the outcome. Like
+// DeployProject it is safe to retry: once the transaction hash is stored,
+// later attempts only wait for that transaction. A reverted or rejected mint
+// marks the issuance FAILED, which releases its tonnes.
+func (s *AssetService) MintIssuance(ctx context.Context, issuanceID string) error {
+	logger := s.server.Logger.With().Str("issuance_id", issuanceID).Logger()
+
+	issuance, err := s.tokenRepo.FindIssuance(ctx, issuanceID)
+	if err != nil {
+		return err
+	}
+	if issuance == nil {
+		logger.Warn().Msg("issuance to mint no longer exists")
+		return nil
+	}
+	if issuance.Status != token.IssuanceStatusPending {
+		logger.Info().Str("status", string(issuance.Status)).Msg("issuance already completed")
+		return nil
+	}
+
+	p, err := s.projectRepo.FindByID(ctx, issuance.ProjectID.String())
+	if err != nil {
+		return err
+	}
+	if p == nil || p.ContractAddress == nil {
+		return s.failIssuance(ctx, issuanceID, errors.New("project has no token contract"))
+	}
+
+	if s.server.Blockchain == nil {
+		return errors.New("blockchain client is not available")
+	}
+	tokenContract, err := s.server.Blockchain.Token(common.HexToAddress(*p.ContractAddress))
+	if err != nil {
+		return s.failIssuance(ctx, issuanceID, err)
+	}
+
+	var txHash common.Hash
+	if issuance.TxHash != nil {
+		txHash = common.HexToHash(*issuance.TxHash)
+		logger.Info().Str("tx_hash", txHash.Hex()).Msg("resuming pending mint")
+	} else {
+		amount, ok := new(big.Int).SetString(issuance.Amount, 10)
+		if !ok {
+			return s.failIssuance(ctx, issuanceID, fmt.Errorf("invalid issuance amount %q", issuance.Amount))
+		}
+
+		txHash, err = tokenContract.SendMint(ctx, common.HexToAddress(issuance.RecipientAddress), amount)
+		if err != nil {
+			if isPermanentChainError(err) {
+				return s.failIssuance(ctx, issuanceID, err)
+			}
+			logger.Error().Err(err).Msg("failed to submit mint")
+			return err
+		}
+
+		stored, err := s.tokenRepo.SetIssuanceTxHash(ctx, issuanceID, txHash.Hex())
+		if err != nil {
+			return err
+		}
+		if !stored {
+			return fmt.Errorf("issuance %s changed while minting: %w", issuanceID, asynq.SkipRetry)
+		}
+		logger.Info().Str("tx_hash", txHash.Hex()).Msg("mint submitted")
+	}
+
+	if _, err := s.server.Blockchain.WaitMined(ctx, txHash); err != nil {
+		if errors.Is(err, blockchain.ErrTransactionReverted) {
+			return s.failIssuance(ctx, issuanceID, err)
+		}
+		logger.Error().Err(err).Str("tx_hash", txHash.Hex()).Msg("mint did not complete")
+		return err
+	}
+
+	if _, err := s.tokenRepo.CompleteIssuance(ctx, issuanceID, token.IssuanceStatusConfirmed); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Str("tx_hash", txHash.Hex()).
+		Str("recipient", issuance.RecipientAddress).
+		Str("amount", issuance.Amount).
+		Msg("tokens minted")
+
+	return nil
+}
+
+// failIssuance marks an issuance FAILED and stops the job from retrying.
+func (s *AssetService) failIssuance(ctx context.Context, issuanceID string, cause error) error {
+	s.server.Logger.Error().Err(cause).Str("issuance_id", issuanceID).Msg("mint failed")
+
+	if _, err := s.tokenRepo.CompleteIssuance(ctx, issuanceID, token.IssuanceStatusFailed); err != nil {
+		return err
+	}
+	return fmt.Errorf("issuance %s failed: %v: %w", issuanceID, cause, asynq.SkipRetry)
+}
+
+// isPermanentChainError reports errors that retrying the same call cannot fix.
+func isPermanentChainError(err error) bool {
+	return errors.Is(err, blockchain.ErrNotOwner) ||
+		errors.Is(err, blockchain.ErrInvalidAddress) ||
+		errors.Is(err, blockchain.ErrInvalidAmount) ||
+		errors.Is(err, blockchain.ErrNoContract)
+}
+
+// tokenSymbol derives a short, stable ERC20 symbol from the project: the
+// initials of its title followed by the start of its ID, e.g. "MR3F2A".
+func tokenSymbol(p *project.Project) string {
+	var initials strings.Builder
+	for _, word := range strings.Fields(p.Title) {
+		r := []rune(word)[0]
+		if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			initials.WriteRune(unicode.ToUpper(r))
+		}
+		if initials.Len() == 4 {
+			break
+		}
+	}
+
+	suffix := strings.ToUpper(strings.ReplaceAll(p.ID.String(), "-", "")[:4])
+	return initials.String() + suffix
+}
+
+// tokenDecimals is the precision used to convert tonnes into token units:
+// the configured override, or the token's own decimals().
+func tokenDecimals(ctx context.Context, chain *blockchain.Client, t *blockchain.Token) (uint8, error) {
+	if chain.Cfg.TokenDecimals != 0 {
+		return chain.Cfg.TokenDecimals, nil
+	}
+	return t.Decimals(ctx)
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"net/http"
 	"strings"
 
+	"github.com/inventedsarawak/ledgera/internal/lib/webhook"
 	"github.com/inventedsarawak/ledgera/internal/middleware"
 	"github.com/inventedsarawak/ledgera/internal/repository"
 	"github.com/inventedsarawak/ledgera/internal/server"
@@ -13,16 +15,33 @@ import (
 )
 
 type AuthService struct {
-	server   *server.Server
-	userRepo *repository.UserRepository
+	server       *server.Server
+	userRepo     *repository.UserRepository
+	webhookRepo  *repository.WebhookEventRepository
+	notifier     *notifier
+	clerkWebhook *webhook.SvixVerifier
 }
 
-func NewAuthService(s *server.Server, userRepo *repository.UserRepository) *AuthService {
+func NewAuthService(s *server.Server, userRepo *repository.UserRepository, webhookRepo *repository.WebhookEventRepository) (*AuthService, error) {
 	clerk.SetKey(s.Config.Auth.SecretKey)
-	return &AuthService{
-		server:   s,
-		userRepo: userRepo,
+
+	// The Clerk webhook stays disabled until its signing secret is configured
+	var clerkWebhook *webhook.SvixVerifier
+	if secret := s.Config.Auth.WebhookSigningSecret; secret != "" {
+		v, err := webhook.NewSvixVerifier(secret)
+		if err != nil {
+			return nil, err
+		}
+		clerkWebhook = v
 	}
+
+	return &AuthService{
+		server:       s,
+		userRepo:     userRepo,
+		webhookRepo:  webhookRepo,
+		notifier:     newNotifier(s, userRepo),
+		clerkWebhook: clerkWebhook,
+	}, nil
 }
 
 func (s *AuthService) SyncUser(ctx echo.Context, clerkID string, email string) (*user.User, error) {
@@ -32,17 +51,24 @@ func (s *AuthService) SyncUser(ctx echo.Context, clerkID string, email string) (
 	role := normalizeRole(middleware.GetUserRole(ctx))
 
 	// Upsert User in DB
-	u, err := s.userRepo.UpsertUser(ctx.Request().Context(), clerkID, email, role)
+	u, inserted, err := s.userRepo.UpsertUser(ctx.Request().Context(), clerkID, email, role)
 	if err != nil {
 		return nil, err
 	}
+	if u == nil {
+		return nil, echo.NewHTTPError(http.StatusGone, "Account has been deleted")
+	}
 
-	// Future-proofing: This is where you would add "Side Effects"
-	// Example: s.emailService.SendWelcomeEmail(u.Email)
+	// Only a newly inserted user is welcomed. The task ID is per Clerk user, so
+	// a row that is inserted again later does not get a second email.
+	if inserted {
+		s.notifier.Welcome(ctx.Request().Context(), u, "")
+	}
 
 	logger.Info().
 		Str("user_id", u.ID.String()).
 		Str("role", string(u.Role)).
+		Bool("created", inserted).
 		Msg("user synced")
 
 	return u, nil
@@ -0,0 +1,461 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hibiken/asynq"
+	"github.com/inventedsarawak/ledgera/internal/blockchain"
+	"github.com/inventedsarawak/ledgera/internal/lib/job"
+	"github.com/inventedsarawak/ledgera/internal/lib/pdf"
+	"github.com/inventedsarawak/ledgera/internal/lib/upload"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/certificate"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/token"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// CertificateFolder is the storage folder retirement certificates are
+// uploaded to.
+const CertificateFolder = "certificates"
+
+// CertificateService retires credits. The holder burns their project tokens
+// or sends them to token.RetirementAddress themselves, then submits the
+// transaction here; once it checks out on chain a certificate is recorded and
+// its PDF is rendered in the background.
+type CertificateService struct {
+	server      *server.Server
+	repo        *repository.CertificateRepository
+	projectRepo *repository.ProjectRepository
+	userRepo    *repository.UserRepository
+	notifier    *notifier
+}
+
+func NewCertificateService(s *server.Server, repo *repository.CertificateRepository, projectRepo *repository.ProjectRepository, userRepo *repository.UserRepository) *CertificateService {
+	return &CertificateService{
+		server:      s,
+		repo:        repo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		notifier:    newNotifier(s, userRepo),
+	}
+}
+
+// Retire records the retirement made by txHash: every Transfer of the
+// project's token in that transaction from the caller's wallet to the zero or
+// retirement address counts towards it.
+func (s *CertificateService) Retire(ctx echo.Context, projectID string, payload certificate.RetirePayload, userID string) (*certificate.Certificate, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("user_id", userID).Str("project_id", projectID).Str("tx_hash", payload.TxHash).Msg("retiring credits")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	reqCtx := ctx.Request().Context()
+
+	u, err := s.userRepo.FindByClerkID(reqCtx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "User not found")
+	}
+	if u.WalletAddress == nil || !common.IsHexAddress(*u.WalletAddress) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Link a wallet before retiring credits")
+	}
+	// Retirements are credited in the caller's name, so only from a wallet
+	// they proved they control
+	if !u.HasVerifiedWallet() {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Verify your wallet before retiring credits")
+	}
+	wallet := common.HexToAddress(*u.WalletAddress)
+
+	p, err := s.projectRepo.FindByID(reqCtx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	// Only a token recorded by the deploy job or the indexer is trusted
+	if p.Status != project.ProjectStatusDeployed || p.ContractAddress == nil || p.DeployTxHash == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Project has no deployed token")
+	}
+
+	chain := s.server.Blockchain
+	if chain == nil {
+		return nil, echo.NewHTTPError(http.StatusServiceUnavailable, "Blockchain client is not available")
+	}
+	tokenAddress := common.HexToAddress(*p.ContractAddress)
+	txHash := common.HexToHash(payload.TxHash)
+
+	logs, err := chain.TransfersInTx(reqCtx, txHash, tokenAddress)
+	if err != nil {
+		switch {
+		case errors.Is(err, blockchain.ErrTransactionNotFound):
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Retirement transaction is not mined yet")
+		case errors.Is(err, blockchain.ErrTransactionReverted):
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Retirement transaction reverted")
+		}
+		return nil, err
+	}
+
+	retired := new(big.Int)
+	transfers := []token.Transfer{}
+	var blockNumber uint64
+	for _, l := range logs {
+		if l.From != wallet || !isRetirementSink(l.To) {
+			continue
+		}
+		retired.Add(retired, l.Value)
+		blockNumber = l.BlockNumber
+		transfers = append(transfers, token.Transfer{
+			ContractAddress: l.Token.Hex(),
+			FromAddress:     l.From.Hex(),
+			ToAddress:       l.To.Hex(),
+			Amount:          l.Value.String(),
+			TxHash:          l.TxHash.Hex(),
+			LogIndex:        l.LogIndex,
+			BlockNumber:     l.BlockNumber,
+			BlockHash:       l.BlockHash.Hex(),
+		})
+	}
+	if retired.Sign() == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Transaction does not burn or lock this project's tokens from your wallet")
+	}
+
+	tokenContract, err := chain.Token(tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := tokenDecimals(reqCtx, chain, tokenContract)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to read token decimals")
+		return nil, err
+	}
+
+	// The beneficiary is public on the certificate, so it is never the
+	// account email.
+	beneficiary := wallet.Hex()
+	if payload.BeneficiaryName != nil {
+		beneficiary = *payload.BeneficiaryName
+	}
+
+	created, err := s.repo.Create(reqCtx, certificate.Certificate{
+		OwnerID:          userID,
+		ProjectID:        p.ID,
+		TxHash:           txHash.Hex(),
+		BlockNumber:      blockNumber,
+		WalletAddress:    wallet.Hex(),
+		BeneficiaryName:  beneficiary,
+		RetirementReason: payload.RetirementReason,
+	}, blockchain.FromBaseUnits(retired, decimals), transfers)
+	if err != nil {
+		if errors.Is(err, repository.ErrCertificateExists) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "Transaction has already been retired")
+		}
+		logger.Error().Err(err).Msg("failed to create certificate")
+		return nil, err
+	}
+
+	s.notifier.CertificateIssued(reqCtx, created, p.Title, s.verifyURL(created.ID.String()))
+
+	// Without a job server (tests, local scripts) render inline. The
+	// certificate stands on its own, so a failed render is only logged.
+	if s.server.Job == nil {
+		if err := s.RenderCertificate(reqCtx, created.ID.String()); err != nil {
+			logger.Warn().Err(err).Str("certificate_id", created.ID.String()).Msg("failed to render certificate")
+		}
+		return s.repo.FindByID(reqCtx, created.ID.String())
+	}
+
+	task, err := job.NewRenderCertificateTask(created.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.server.Job.Client.EnqueueContext(reqCtx, task); err != nil {
+		logger.Error().Err(err).Msg("failed to enqueue certificate render task")
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetByID returns a certificate to its owner or an admin.
+func (s *CertificateService) GetByID(ctx echo.Context, id string, userID string) (*certificate.Certificate, error) {
+	c, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Certificate not found")
+	}
+	if c.OwnerID != userID {
+		if err := ensureAdmin(ctx, s.userRepo, userID); err != nil {
+			return nil, echo.NewHTTPError(http.StatusForbidden, "You do not own this certificate")
+		}
+	}
+	return c, nil
+}
+
+// ListMine returns one page of the caller's certificates.
+func (s *CertificateService) ListMine(ctx echo.Context, q certificate.GetCertificatesQuery, userID string) (*model.PaginatedResponse[certificate.Certificate], error) {
+	items, total, err := s.repo.ListByOwnerPaginated(ctx.Request().Context(), userID, q)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := 0
+	if q.Limit > 0 {
+		totalPages = int((total + int64(q.Limit) - 1) / int64(q.Limit))
+	}
+
+	return &model.PaginatedResponse[certificate.Certificate]{
+		Data:       items,
+		Page:       q.Page,
+		Limit:      q.Limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Verify returns the public verification view of a certificate.
+func (s *CertificateService) Verify(ctx echo.Context, id string) (*certificate.Verification, error) {
+	c, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return s.verification(ctx, c)
+}
+
+// VerifyByTxHash returns the public verification view of the certificate for
+// a retirement transaction.
+func (s *CertificateService) VerifyByTxHash(ctx echo.Context, txHash string) (*certificate.Verification, error) {
+	c, err := s.repo.FindByTxHash(ctx.Request().Context(), common.HexToHash(txHash).Hex())
+	if err != nil {
+		return nil, err
+	}
+	return s.verification(ctx, c)
+}
+
+func (s *CertificateService) verification(ctx echo.Context, c *certificate.Certificate) (*certificate.Verification, error) {
+	if c == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Certificate not found")
+	}
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	p, err := s.projectRepo.FindByID(reqCtx, c.ProjectID.String())
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Certificate not found")
+	}
+
+	v := &certificate.Verification{
+		ID:               c.ID,
+		BeneficiaryName:  c.BeneficiaryName,
+		AmountRetired:    c.AmountRetired,
+		RetirementReason: c.RetirementReason,
+		WalletAddress:    c.WalletAddress,
+		TxHash:           c.TxHash,
+		RetiredAt:        c.CreatedAt,
+		PDFURL:           c.PDFURL,
+		Project: certificate.ProjectSummary{
+			ID:              p.ID,
+			Title:           p.Title,
+			Status:          p.Status,
+			LocationLat:     p.LocationLat,
+			LocationLng:     p.LocationLng,
+			TokenSymbol:     p.TokenSymbol,
+			ContractAddress: p.ContractAddress,
+		},
+		Proof:       certificate.OnChainProof{Status: certificate.ProofStatusUnavailable},
+		ContentHash: certificate.ContentHash(*c),
+	}
+
+	chain := s.server.Blockchain
+	if chain == nil {
+		return v, nil
+	}
+
+	signature, err := chain.SignText([]byte(v.ContentHash))
+	if err != nil {
+		return nil, err
+	}
+	v.Signature = hexutil.Encode(signature)
+	v.Signer = chain.Address().Hex()
+
+	// The proof is best effort: a flaky RPC must not hide the certificate.
+	proof, err := s.onChainProof(reqCtx, chain, common.HexToHash(c.TxHash))
+	if err != nil {
+		logger.Warn().Err(err).Str("tx_hash", c.TxHash).Msg("failed to check retirement transaction")
+		return v, nil
+	}
+	v.Proof = *proof
+
+	return v, nil
+}
+
+func (s *CertificateService) onChainProof(ctx context.Context, chain *blockchain.Client, txHash common.Hash) (*certificate.OnChainProof, error) {
+	receipt, err := chain.Receipt(ctx, txHash)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrTransactionNotFound) {
+			return &certificate.OnChainProof{Status: certificate.ProofStatusNotFound}, nil
+		}
+		return nil, err
+	}
+
+	proof := &certificate.OnChainProof{
+		Status:      certificate.ProofStatusConfirmed,
+		BlockNumber: receipt.BlockNumber.Uint64(),
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		proof.Status = certificate.ProofStatusReverted
+	}
+
+	head, err := chain.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head >= proof.BlockNumber {
+		proof.Confirmations = head - proof.BlockNumber + 1
+	}
+	return proof, nil
+}
+
+// HandleRenderCertificateTask is the asynq handler for job.TaskRenderCertificate.
+func (s *CertificateService) HandleRenderCertificateTask(ctx context.Context, t *asynq.Task) error {
+	var p job.RenderCertificatePayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal render certificate payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return s.RenderCertificate(ctx, p.CertificateID)
+}
+
+// RenderCertificate renders a certificate's PDF, uploads it to the
+// certificates folder and stores its URL. Certificates that already have a
+// PDF are left alone.
+func (s *CertificateService) RenderCertificate(ctx context.Context, certificateID string) error {
+	logger := s.server.Logger.With().Str("certificate_id", certificateID).Logger()
+
+	c, err := s.repo.FindByID(ctx, certificateID)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		logger.Warn().Msg("certificate to render no longer exists")
+		return nil
+	}
+	if c.PDFURL != nil {
+		return nil
+	}
+	if s.server.Uploader == nil {
+		return errors.New("storage uploader is not available")
+	}
+
+	p, err := s.projectRepo.FindByID(ctx, c.ProjectID.String())
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return fmt.Errorf("project %s of certificate no longer exists: %w", c.ProjectID, asynq.SkipRetry)
+	}
+
+	data := pdf.CertificateData{
+		CertificateID:   c.ID.String(),
+		BeneficiaryName: c.BeneficiaryName,
+		Tonnes:          c.AmountRetired,
+		ProjectTitle:    p.Title,
+		TxHash:          c.TxHash,
+		WalletAddress:   c.WalletAddress,
+		RetiredAt:       c.CreatedAt,
+		VerifyURL:       s.verifyURL(c.ID.String()),
+		ContentHash:     certificate.ContentHash(*c),
+	}
+	if c.RetirementReason != nil {
+		data.Reason = *c.RetirementReason
+	}
+
+	document, err := pdf.RenderCertificate(data)
+	if err != nil {
+		return err
+	}
+
+	url, err := s.server.Uploader.Upload(ctx, upload.UploadParams{
+		File:        bytes.NewReader(document),
+		Folder:      CertificateFolder,
+		Filename:    c.ID.String() + ".pdf",
+		UserID:      c.OwnerID,
+		ContentType: "application/pdf",
+		Size:        int64(len(document)),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.SetPDFURL(ctx, certificateID, url); err != nil {
+		return err
+	}
+	logger.Info().Str("pdf_url", url).Msg("certificate rendered")
+	return nil
+}
+
+// verifyURL is the public verification link printed on a certificate, or
+// empty when no public URL is configured.
+func (s *CertificateService) verifyURL(certificateID string) string {
+	base := strings.TrimRight(s.server.Config.Server.PublicURL, "/")
+	if base == "" {
+		return ""
+	}
+	return base + "/api/v1/public/certificates/" + certificateID
+}
+
+// isRetirementSink reports whether tokens sent to addr are out of circulation.
+func isRetirementSink(addr common.Address) bool {
+	return addr == common.HexToAddress(token.ZeroAddress) || addr == common.HexToAddress(token.RetirementAddress)
+}
+
+// withdrawCertificatePDFs deletes PDFs that were taken off their
+// certificates and queues the certificates to be rendered again. Failures
+// are only logged: the certificates are already corrected.
+func withdrawCertificatePDFs(ctx context.Context, s *server.Server, withdrawn []repository.WithdrawnPDF) {
+	for _, w := range withdrawn {
+		logger := s.Logger.With().Str("certificate_id", w.CertificateID).Logger()
+
+		if s.Uploader != nil {
+			if key, ok := s.Uploader.Key(w.URL); ok {
+				if err := s.Uploader.Delete(ctx, key); err != nil {
+					logger.Error().Err(err).Str("key", key).Msg("failed to delete withdrawn certificate pdf")
+				}
+			}
+		}
+
+		if s.Job == nil {
+			continue
+		}
+		task, err := job.NewRenderCertificateTask(w.CertificateID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to build certificate render task")
+			continue
+		}
+		if _, err := s.Job.Client.EnqueueContext(ctx, task); err != nil {
+			logger.Error().Err(err).Msg("failed to enqueue certificate render task")
+		}
+	}
+}
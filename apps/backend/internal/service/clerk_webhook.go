@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// clerkWebhookSource namespaces Clerk deliveries in webhook_events.
+const clerkWebhookSource = "clerk"
+
+// ReceiveClerkWebhook verifies a Clerk webhook delivery and applies it to the
+// local users table. Each delivery is applied at most once: retries and
+// replays of an already handled svix-id are acknowledged and ignored.
+func (s *AuthService) ReceiveClerkWebhook(ctx echo.Context, body []byte) error {
+	if s.clerkWebhook == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "Clerk webhooks are not configured")
+	}
+
+	msgID, err := s.clerkWebhook.Verify(ctx.Request().Header, body)
+	if err != nil {
+		middleware.GetLogger(ctx).Warn().Err(err).Msg("rejected clerk webhook")
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid webhook signature")
+	}
+
+	var event user.ClerkEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.Type == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid webhook payload")
+	}
+
+	logger := middleware.GetLogger(ctx).With().Str("svix_id", msgID).Str("event", event.Type).Logger()
+	reqCtx := ctx.Request().Context()
+
+	fresh, err := s.webhookRepo.Record(reqCtx, clerkWebhookSource, msgID, event.Type)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to record clerk webhook")
+		return err
+	}
+	if !fresh {
+		logger.Info().Msg("clerk webhook already handled")
+		return nil
+	}
+
+	if err := s.applyClerkEvent(reqCtx, &logger, event); err != nil {
+		// Release the delivery so Clerk's retry is processed
+		if forgetErr := s.webhookRepo.Forget(reqCtx, clerkWebhookSource, msgID); forgetErr != nil {
+			logger.Error().Err(forgetErr).Msg("failed to release clerk webhook")
+		}
+		return err
+	}
+
+	logger.Info().Msg("clerk webhook handled")
+	return nil
+}
+
+func (s *AuthService) applyClerkEvent(ctx context.Context, logger *zerolog.Logger, event user.ClerkEvent) error {
+	switch event.Type {
+	case user.ClerkUserCreated, user.ClerkUserUpdated:
+		var data clerk.User
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid user payload")
+		}
+		return s.syncClerkUser(ctx, logger, &data)
+
+	case user.ClerkUserDeleted:
+		var data clerk.DeletedResource
+		if err := json.Unmarshal(event.Data, &data); err != nil || data.ID == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid user payload")
+		}
+		deleted, withdrawn, err := s.userRepo.SoftDelete(ctx, data.ID)
+		if err != nil {
+			return err
+		}
+		withdrawCertificatePDFs(ctx, s.server, withdrawn)
+		logger.Info().Str("clerk_id", data.ID).Bool("deleted", deleted).Msg("clerk user deleted")
+		return nil
+
+	case user.ClerkMembershipCreated, user.ClerkMembershipUpdated, user.ClerkMembershipDeleted:
+		var data clerk.OrganizationMembership
+		if err := json.Unmarshal(event.Data, &data); err != nil || data.PublicUserData == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid membership payload")
+		}
+		return s.syncClerkMembership(ctx, logger, event.Type == user.ClerkMembershipDeleted, &data)
+
+	default:
+		logger.Debug().Msg("ignoring clerk webhook event")
+		return nil
+	}
+}
+
+// syncClerkUser upserts a Clerk user. The role comes from public metadata,
+// the same place the session claims read it from; without one the user keeps
+// their current role, which may have come from an organization membership.
+func (s *AuthService) syncClerkUser(ctx context.Context, logger *zerolog.Logger, data *clerk.User) error {
+	email := primaryEmail(data)
+	if data.ID == "" || email == "" {
+		logger.Warn().Str("clerk_id", data.ID).Msg("clerk user has no primary email, skipping")
+		return nil
+	}
+
+	role := user.RoleBuyer
+	if raw := metadataRole(data.PublicMetadata); raw != "" {
+		role = normalizeRole(raw)
+	} else {
+		existing, err := s.userRepo.FindByClerkID(ctx, data.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			role = existing.Role
+		}
+	}
+
+	u, inserted, err := s.userRepo.UpsertUser(ctx, data.ID, email, role)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		logger.Info().Str("clerk_id", data.ID).Msg("clerk user was deleted, skipping")
+		return nil
+	}
+	if inserted {
+		firstName := ""
+		if data.FirstName != nil {
+			firstName = *data.FirstName
+		}
+		s.notifier.Welcome(ctx, u, firstName)
+	}
+
+	logger.Info().
+		Str("user_id", u.ID.String()).
+		Str("role", string(u.Role)).
+		Bool("created", inserted).
+		Msg("clerk user synced")
+	return nil
+}
+
+// syncClerkMembership applies an organization role such as
+// "org:ledgera_supplier" to the member. Only memberships of the configured
+// organization count, and they never grant or take away ADMIN: admins are
+// made through public metadata. Removing a membership demotes the member to
+// BUYER unless their role has since come from somewhere else.
+func (s *AuthService) syncClerkMembership(ctx context.Context, logger *zerolog.Logger, removed bool, data *clerk.OrganizationMembership) error {
+	orgID := ""
+	if data.Organization != nil {
+		orgID = data.Organization.ID
+	}
+	if want := s.server.Config.Auth.OrganizationID; want == "" || orgID != want {
+		logger.Debug().Str("org_id", orgID).Msg("ignoring clerk membership of another organization")
+		return nil
+	}
+
+	clerkID := data.PublicUserData.UserID
+	role, ok := organizationRole(data.Role)
+	if clerkID == "" || !ok {
+		logger.Debug().Str("org_role", data.Role).Msg("ignoring clerk membership with unmapped role")
+		return nil
+	}
+
+	existing, err := s.userRepo.FindByClerkID(ctx, clerkID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Role == user.RoleAdmin {
+		logger.Info().Str("clerk_id", clerkID).Msg("ignoring clerk membership of an admin")
+		return nil
+	}
+
+	if removed {
+		if existing == nil || existing.Role != role {
+			return nil
+		}
+		role = user.RoleBuyer
+	}
+
+	u, err := s.userRepo.UpdateRole(ctx, clerkID, role)
+	if err != nil {
+		return err
+	}
+
+	// The membership can arrive before the user.created event
+	if u == nil && !removed && strings.Contains(data.PublicUserData.Identifier, "@") {
+		var inserted bool
+		u, inserted, err = s.userRepo.UpsertUser(ctx, clerkID, data.PublicUserData.Identifier, role)
+		if err != nil {
+			return err
+		}
+		if u != nil && inserted {
+			firstName := ""
+			if data.PublicUserData.FirstName != nil {
+				firstName = *data.PublicUserData.FirstName
+			}
+			s.notifier.Welcome(ctx, u, firstName)
+		}
+	}
+	if u == nil {
+		logger.Info().Str("clerk_id", clerkID).Msg("clerk membership for unknown or deleted user, skipping")
+		return nil
+	}
+
+	logger.Info().
+		Str("user_id", u.ID.String()).
+		Str("role", string(u.Role)).
+		Msg("clerk membership synced")
+	return nil
+}
+
+func primaryEmail(u *clerk.User) string {
+	for _, e := range u.EmailAddresses {
+		if e == nil {
+			continue
+		}
+		if u.PrimaryEmailAddressID == nil || e.ID == *u.PrimaryEmailAddressID {
+			return e.EmailAddress
+		}
+	}
+	return ""
+}
+
+func metadataRole(raw json.RawMessage) string {
+	var metadata struct {
+		Role string `json:"role"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &metadata) != nil {
+		return ""
+	}
+	return strings.TrimSpace(metadata.Role)
+}
+
+// organizationRole maps one of our custom Clerk organization role keys to a
+// user role. Clerk's built-in keys such as "org:admin" and "org:member" are
+// not mapped, and no key maps to ADMIN.
+func organizationRole(raw string) (user.UserRole, bool) {
+	switch strings.ToUpper(strings.TrimSpace(raw)) {
+	case "ORG:LEDGERA_SUPPLIER":
+		return user.RoleSupplier, true
+	case "ORG:LEDGERA_BUYER":
+		return user.RoleBuyer, true
+	default:
+		return "", false
+	}
+}
@@ -1 +1,207 @@
-package service
\ No newline at end of file
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hibiken/asynq"
+	"github.com/inventedsarawak/ledgera/internal/blockchain"
+	"github.com/inventedsarawak/ledgera/internal/model/token"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/server"
+)
+
+const (
+	// IndexerName is the checkpoint row the chain indexer owns.
+	IndexerName = "chain"
+
+	defaultIndexerBatchSize = 2000
+	defaultReorgDepth       = 12
+)
+
+// IndexerService mirrors on-chain state into the database: AssetCreated
+// events from the registry link tokens to projects, and Transfer events of
+// those tokens feed token_transfers and token_holdings.
+//
+// It only reads blocks at least ReorgDepth below the head, and stores the hash
+// of the last block it processed. If that hash is no longer canonical the
+// chain reorganised deeper than expected, so it rewinds ReorgDepth blocks and
+// indexes them again.
+type IndexerService struct {
+	server      *server.Server
+	tokenRepo   *repository.TokenRepository
+	projectRepo *repository.ProjectRepository
+}
+
+func NewIndexerService(s *server.Server, tokenRepo *repository.TokenRepository, projectRepo *repository.ProjectRepository) *IndexerService {
+	return &IndexerService{
+		server:      s,
+		tokenRepo:   tokenRepo,
+		projectRepo: projectRepo,
+	}
+}
+
+// HandleSyncTask is the asynq handler for job.TaskIndexerSync.
+func (s *IndexerService) HandleSyncTask(ctx context.Context, t *asynq.Task) error {
+	_, err := s.Sync(ctx)
+	return err
+}
+
+// Sync indexes every safe block after the checkpoint and returns the new
+// checkpoint. It is safe to interrupt: each block range is committed together
+// with its checkpoint.
+func (s *IndexerService) Sync(ctx context.Context) (*token.Checkpoint, error) {
+	chain := s.server.Blockchain
+	if chain == nil {
+		return nil, errors.New("blockchain client is not available")
+	}
+	cfg := chain.Cfg
+	logger := s.server.Logger.With().Str("indexer", IndexerName).Logger()
+
+	registry, err := chain.Registry()
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := cfg.IndexerBatchSize
+	if batchSize == 0 {
+		batchSize = defaultIndexerBatchSize
+	}
+	reorgDepth := cfg.ReorgDepth
+	if reorgDepth == 0 {
+		reorgDepth = defaultReorgDepth
+	}
+
+	checkpoint, err := s.tokenRepo.GetCheckpoint(ctx, IndexerName)
+	if err != nil {
+		return nil, err
+	}
+
+	next := cfg.IndexerStartBlock
+	if checkpoint != nil {
+		checkpoint, err = s.checkReorg(ctx, chain, *checkpoint, reorgDepth)
+		if err != nil {
+			return nil, err
+		}
+		next = checkpoint.LastBlock + 1
+	}
+
+	head, err := chain.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head < reorgDepth {
+		return checkpoint, nil
+	}
+	safeHead := head - reorgDepth
+
+	for from := next; from <= safeHead; from += batchSize {
+		to := min(from+batchSize-1, safeHead)
+
+		cp, err := s.indexRange(ctx, chain, registry, from, to)
+		if err != nil {
+			logger.Error().Err(err).Uint64("from", from).Uint64("to", to).Msg("failed to index block range")
+			return checkpoint, err
+		}
+		checkpoint = cp
+	}
+
+	return checkpoint, nil
+}
+
+// checkReorg verifies the checkpoint is still on the canonical chain and, if
+// not, rewinds it by reorgDepth blocks (never before the start block).
+func (s *IndexerService) checkReorg(ctx context.Context, chain *blockchain.Client, checkpoint token.Checkpoint, reorgDepth uint64) (*token.Checkpoint, error) {
+	hash, err := chain.BlockHash(ctx, checkpoint.LastBlock)
+	if err != nil {
+		return nil, err
+	}
+	if hash.Hex() == checkpoint.LastBlockHash {
+		return &checkpoint, nil
+	}
+
+	target := chain.Cfg.IndexerStartBlock
+	if checkpoint.LastBlock > target+reorgDepth {
+		target = checkpoint.LastBlock - reorgDepth
+	}
+
+	s.server.Logger.Warn().
+		Uint64("block", checkpoint.LastBlock).
+		Uint64("rewind_to", target).
+		Str("stored_hash", checkpoint.LastBlockHash).
+		Str("canonical_hash", hash.Hex()).
+		Msg("indexer checkpoint is no longer canonical, rewinding")
+
+	targetHash, err := chain.BlockHash(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	rewound := token.Checkpoint{Name: IndexerName, LastBlock: target, LastBlockHash: targetHash.Hex()}
+
+	if err := s.tokenRepo.Rewind(ctx, rewound); err != nil {
+		return nil, err
+	}
+	return &rewound, nil
+}
+
+func (s *IndexerService) indexRange(ctx context.Context, chain *blockchain.Client, registry *blockchain.Registry, from uint64, to uint64) (*token.Checkpoint, error) {
+	created, err := registry.AssetCreatedLogs(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Link new tokens first so their transfers in the same range are picked up.
+	for _, event := range created {
+		linked, err := s.projectRepo.LinkDeployedAsset(ctx, event.TxHash.Hex(), event.AssetAddress.Hex(), event.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if linked {
+			s.server.Logger.Info().
+				Str("contract_address", event.AssetAddress.Hex()).
+				Str("tx_hash", event.TxHash.Hex()).
+				Msg("indexer linked token to project")
+		}
+	}
+
+	contracts, err := s.tokenRepo.ListTokenContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]common.Address, 0, len(contracts))
+	for _, c := range contracts {
+		tokens = append(tokens, common.HexToAddress(c))
+	}
+
+	logs, err := chain.TransferLogs(ctx, tokens, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := make([]token.Transfer, 0, len(logs))
+	for _, l := range logs {
+		transfers = append(transfers, token.Transfer{
+			ContractAddress: l.Token.Hex(),
+			FromAddress:     l.From.Hex(),
+			ToAddress:       l.To.Hex(),
+			Amount:          l.Value.String(),
+			TxHash:          l.TxHash.Hex(),
+			LogIndex:        l.LogIndex,
+			BlockNumber:     l.BlockNumber,
+			BlockHash:       l.BlockHash.Hex(),
+		})
+	}
+
+	hash, err := chain.BlockHash(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := token.Checkpoint{Name: IndexerName, LastBlock: to, LastBlockHash: hash.Hex()}
+
+	if err := s.tokenRepo.SaveBatch(ctx, transfers, checkpoint); err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
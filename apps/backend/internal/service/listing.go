@@ -0,0 +1,220 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/listing"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+type ListingService struct {
+	server      *server.Server
+	repo        *repository.ListingRepository
+	projectRepo *repository.ProjectRepository
+	userRepo    *repository.UserRepository
+}
+
+func NewListingService(s *server.Server, repo *repository.ListingRepository, projectRepo *repository.ProjectRepository, userRepo *repository.UserRepository) *ListingService {
+	return &ListingService{
+		server:      s,
+		repo:        repo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// Create lists the caller's tokens of a deployed project. The seller is the
+// wallet linked to the caller's account.
+func (s *ListingService) Create(ctx echo.Context, payload listing.CreateListingPayload, userID string) (*listing.Listing, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("user_id", userID).Str("project_id", payload.ProjectID.String()).Msg("creating listing")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	wallet, err := s.sellerWallet(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.projectRepo.FindByID(ctx.Request().Context(), payload.ProjectID.String())
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	if p.Status != project.ProjectStatusDeployed {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Only deployed projects can be listed")
+	}
+
+	created, err := s.repo.Create(ctx.Request().Context(), listing.Listing{
+		ProjectID:         payload.ProjectID,
+		SellerAddress:     wallet,
+		PricePerToken:     payload.PricePerToken,
+		QuantityAvailable: payload.QuantityAvailable,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create listing in db")
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *ListingService) GetByID(ctx echo.Context, id string) (*listing.Listing, error) {
+	l, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	return l, nil
+}
+
+// Browse returns one page of active listings, optionally for a single project.
+func (s *ListingService) Browse(ctx echo.Context, q listing.GetListingsQuery) (*model.PaginatedResponse[listing.Listing], error) {
+	if q.Cursor != nil {
+		return s.browseAfter(ctx, q)
+	}
+
+	items, total, err := s.repo.ListActivePaginated(ctx.Request().Context(), q)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := 0
+	if q.Limit > 0 {
+		totalPages = int((total + int64(q.Limit) - 1) / int64(q.Limit))
+	}
+
+	return &model.PaginatedResponse[listing.Listing]{
+		Data:       items,
+		Page:       q.Page,
+		Limit:      q.Limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}, nil
+}
+
+// browseAfter is Browse in cursor mode: newest first, with the cursor of the
+// next page in NextCursor.
+func (s *ListingService) browseAfter(ctx echo.Context, q listing.GetListingsQuery) (*model.PaginatedResponse[listing.Listing], error) {
+	after, err := decodeCursor(*q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	items, total, next, err := s.repo.ListActiveAfter(ctx.Request().Context(), q.ProjectID, after, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := 0
+	if q.Limit > 0 {
+		totalPages = int((total + int64(q.Limit) - 1) / int64(q.Limit))
+	}
+
+	resp := &model.PaginatedResponse[listing.Listing]{
+		Data:       items,
+		Limit:      q.Limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}
+	if next != nil {
+		encoded := next.Encode()
+		resp.NextCursor = &encoded
+	}
+	return resp, nil
+}
+
+// Update changes the price and/or quantity of one of the caller's listings.
+func (s *ListingService) Update(ctx echo.Context, id string, payload listing.UpdateListingPayload, userID string) (*listing.Listing, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("user_id", userID).Str("listing_id", id).Msg("updating listing")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.ownedListing(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.Active {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Listing is no longer active")
+	}
+
+	updated, err := s.repo.Update(ctx.Request().Context(), id, payload)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Listing is no longer active")
+	}
+
+	return updated, nil
+}
+
+// Deactivate takes one of the caller's listings off the marketplace.
+func (s *ListingService) Deactivate(ctx echo.Context, id string, userID string) error {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("user_id", userID).Str("listing_id", id).Msg("deactivating listing")
+
+	if _, err := s.ownedListing(ctx, id, userID); err != nil {
+		return err
+	}
+
+	_, err := s.repo.Deactivate(ctx.Request().Context(), id)
+	return err
+}
+
+// ownedListing loads a listing and checks that its seller address is the
+// caller's wallet.
+func (s *ListingService) ownedListing(ctx echo.Context, id string, userID string) (*listing.Listing, error) {
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+
+	wallet, err := s.sellerWallet(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(existing.SellerAddress, wallet) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "You do not own this listing")
+	}
+
+	return existing, nil
+}
+
+// sellerWallet returns the caller's verified wallet in checksum form.
+func (s *ListingService) sellerWallet(ctx echo.Context, userID string) (string, error) {
+	u, err := s.userRepo.FindByClerkID(ctx.Request().Context(), userID)
+	if err != nil {
+		return "", err
+	}
+	if u == nil {
+		return "", echo.NewHTTPError(http.StatusUnauthorized, "User not found")
+	}
+	if u.WalletAddress == nil || !common.IsHexAddress(*u.WalletAddress) {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Link a wallet before listing tokens")
+	}
+	if !u.HasVerifiedWallet() {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Verify your wallet before listing tokens")
+	}
+
+	return common.HexToAddress(*u.WalletAddress).Hex(), nil
+}
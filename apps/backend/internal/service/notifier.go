@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/hibiken/asynq"
+	"github.com/inventedsarawak/ledgera/internal/lib/job"
+	"github.com/inventedsarawak/ledgera/internal/model/certificate"
+	"github.com/inventedsarawak/ledgera/internal/model/order"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/server"
+)
+
+// notifier sends the transactional emails of the project and marketplace
+// lifecycle. Emails are queued when there is a job server and sent inline
+// otherwise. They never fail the operation that triggered them: errors are
+// logged and dropped.
+type notifier struct {
+	server   *server.Server
+	userRepo *repository.UserRepository
+}
+
+func newNotifier(s *server.Server, userRepo *repository.UserRepository) *notifier {
+	return &notifier{server: s, userRepo: userRepo}
+}
+
+// Welcome greets a newly registered user with the onboarding email for their
+// role. It is keyed on the user's Clerk ID, so it is queued at most once.
+func (n *notifier) Welcome(ctx context.Context, u *user.User, firstName string) {
+	task, err := job.NewWelcomeEmailTask(u.ClerkID, job.WelcomeEmailPayload{
+		To:        u.Email,
+		FirstName: firstName,
+		Role:      string(u.Role),
+	})
+	n.send(ctx, task, err)
+}
+
+// ProjectSubmitted tells every admin that p is waiting for review.
+func (n *notifier) ProjectSubmitted(ctx context.Context, p *project.Project) {
+	admins, err := n.userRepo.ListByRole(ctx, user.RoleAdmin)
+	if err != nil {
+		n.server.Logger.Error().Err(err).Msg("failed to list admins to notify")
+		return
+	}
+
+	supplierEmail := ""
+	if supplier := n.findUser(ctx, p.SupplierID); supplier != nil {
+		supplierEmail = supplier.Email
+	}
+
+	for _, admin := range admins {
+		task, err := job.NewProjectSubmittedEmailTask(job.ProjectSubmittedEmailPayload{
+			To:            admin.Email,
+			ProjectTitle:  p.Title,
+			SupplierEmail: supplierEmail,
+			CarbonAmount:  formatAmount(p.CarbonAmount),
+		})
+		n.send(ctx, task, err)
+	}
+}
+
+func (n *notifier) ProjectApproved(ctx context.Context, p *project.Project) {
+	supplier := n.findUser(ctx, p.SupplierID)
+	if supplier == nil {
+		return
+	}
+
+	task, err := job.NewProjectApprovedEmailTask(job.ProjectApprovedEmailPayload{
+		To:           supplier.Email,
+		ProjectTitle: p.Title,
+	})
+	n.send(ctx, task, err)
+}
+
+func (n *notifier) ProjectRejected(ctx context.Context, p *project.Project, reason string, feedback string) {
+	supplier := n.findUser(ctx, p.SupplierID)
+	if supplier == nil {
+		return
+	}
+
+	task, err := job.NewProjectRejectedEmailTask(job.ProjectRejectedEmailPayload{
+		To:           supplier.Email,
+		ProjectTitle: p.Title,
+		Reason:       reason,
+		Feedback:     feedback,
+	})
+	n.send(ctx, task, err)
+}
+
+func (n *notifier) ProjectDeployed(ctx context.Context, p *project.Project) {
+	supplier := n.findUser(ctx, p.SupplierID)
+	if supplier == nil || p.ContractAddress == nil {
+		return
+	}
+
+	symbol := ""
+	if p.TokenSymbol != nil {
+		symbol = *p.TokenSymbol
+	}
+
+	task, err := job.NewProjectDeployedEmailTask(p.ID.String(), job.ProjectDeployedEmailPayload{
+		To:              supplier.Email,
+		ProjectTitle:    p.Title,
+		TokenSymbol:     symbol,
+		ContractAddress: *p.ContractAddress,
+	})
+	n.send(ctx, task, err)
+}
+
+// ListingSold tells the seller of a settled order that their credits sold.
+func (n *notifier) ListingSold(ctx context.Context, o *order.Order, projectTitle string) {
+	seller, err := n.userRepo.FindByWalletAddress(ctx, o.SellerAddress)
+	if err != nil || seller == nil {
+		n.server.Logger.Warn().Err(err).Str("order_id", o.ID.String()).Msg("no seller to notify of sale")
+		return
+	}
+
+	txHash := ""
+	if o.SettlementTxHash != nil {
+		txHash = *o.SettlementTxHash
+	}
+
+	task, err := job.NewListingSoldEmailTask(job.ListingSoldEmailPayload{
+		To:           seller.Email,
+		ProjectTitle: projectTitle,
+		Quantity:     formatAmount(o.Quantity),
+		TotalPrice:   formatAmount(o.TotalPrice),
+		TxHash:       txHash,
+	})
+	n.send(ctx, task, err)
+}
+
+func (n *notifier) CertificateIssued(ctx context.Context, c *certificate.Certificate, projectTitle string, verifyURL string) {
+	owner := n.findUser(ctx, c.OwnerID)
+	if owner == nil {
+		return
+	}
+
+	task, err := job.NewCertificateIssuedEmailTask(job.CertificateIssuedEmailPayload{
+		To:              owner.Email,
+		BeneficiaryName: c.BeneficiaryName,
+		ProjectTitle:    projectTitle,
+		Tonnes:          formatAmount(c.AmountRetired),
+		VerifyURL:       verifyURL,
+	})
+	n.send(ctx, task, err)
+}
+
+func (n *notifier) findUser(ctx context.Context, clerkID string) *user.User {
+	u, err := n.userRepo.FindByClerkID(ctx, clerkID)
+	if err != nil || u == nil {
+		n.server.Logger.Warn().Err(err).Str("clerk_id", clerkID).Msg("no user to notify")
+		return nil
+	}
+	return u
+}
+
+func (n *notifier) send(ctx context.Context, task *asynq.Task, err error) {
+	if err != nil {
+		n.server.Logger.Error().Err(err).Msg("failed to build email task")
+		return
+	}
+
+	// Without a job server (tests, local scripts) send inline.
+	if n.server.Job == nil {
+		if n.server.Email == nil {
+			return
+		}
+		if err := job.SendEmailTask(n.server.Email, task); err != nil {
+			n.server.Logger.Error().Err(err).Str("type", task.Type()).Msg("failed to send email")
+		}
+		return
+	}
+
+	_, err = n.server.Job.Client.EnqueueContext(ctx, task)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		n.server.Logger.Debug().Str("type", task.Type()).Msg("email already queued")
+		return
+	}
+	if err != nil {
+		n.server.Logger.Error().Err(err).Str("type", task.Type()).Msg("failed to enqueue email")
+	}
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hibiken/asynq"
+	"github.com/inventedsarawak/ledgera/internal/blockchain"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/order"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultReservationTTL is how long a placed order holds its quantity when
+// marketplace.reservation_ttl is not configured.
+const DefaultReservationTTL = 15 * time.Minute
+
+// OrderService runs the buyer side of the marketplace. An order moves
+// through:
+//
+//	RESERVED -> PAID -> SETTLED
+//	RESERVED -> CANCELLED (by the buyer)
+//	RESERVED -> EXPIRED   (by the scheduled expiry job)
+//
+// Placing an order takes its quantity out of the listing; cancelling or
+// expiring gives it back. Settlement records the on-chain transfer of the
+// tokens from the seller to the buyer.
+type OrderService struct {
+	server      *server.Server
+	repo        *repository.OrderRepository
+	listingRepo *repository.ListingRepository
+	projectRepo *repository.ProjectRepository
+	userRepo    *repository.UserRepository
+	notifier    *notifier
+}
+
+func NewOrderService(s *server.Server, repo *repository.OrderRepository, listingRepo *repository.ListingRepository, projectRepo *repository.ProjectRepository, userRepo *repository.UserRepository) *OrderService {
+	return &OrderService{
+		server:      s,
+		repo:        repo,
+		listingRepo: listingRepo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		notifier:    newNotifier(s, userRepo),
+	}
+}
+
+// Place reserves quantity tokens of a listing for the calling buyer.
+func (s *OrderService) Place(ctx echo.Context, payload order.PlaceOrderPayload, userID string) (*order.Order, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("user_id", userID).Str("listing_id", payload.ListingID.String()).Msg("placing order")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	buyer, err := s.userRepo.FindByClerkID(ctx.Request().Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if buyer == nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "User not found")
+	}
+	if buyer.Role != user.RoleBuyer {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Only buyers can place orders")
+	}
+	if buyer.WalletAddress == nil || !common.IsHexAddress(*buyer.WalletAddress) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Link a wallet before placing orders")
+	}
+	buyerAddress := common.HexToAddress(*buyer.WalletAddress).Hex()
+
+	l, err := s.listingRepo.FindByID(ctx.Request().Context(), payload.ListingID.String())
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Listing not found")
+	}
+	if strings.EqualFold(l.SellerAddress, buyerAddress) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "You cannot buy your own listing")
+	}
+
+	placed, err := s.repo.Reserve(ctx.Request().Context(), l.ID.String(), userID, buyerAddress, payload.Quantity, s.reservationTTL())
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrListingUnavailable):
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Listing is no longer active")
+		case errors.Is(err, repository.ErrInsufficientQuantity):
+			return nil, echo.NewHTTPError(http.StatusConflict, "Listing does not have enough quantity available")
+		}
+		logger.Error().Err(err).Msg("failed to reserve order")
+		return nil, err
+	}
+
+	return placed, nil
+}
+
+// GetByID returns an order to its buyer, its seller or an admin.
+func (s *OrderService) GetByID(ctx echo.Context, id string, userID string) (*order.Order, error) {
+	existing, err := s.findOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.BuyerID == userID {
+		return existing, nil
+	}
+
+	u, err := s.userRepo.FindByClerkID(ctx.Request().Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+	if u != nil && u.WalletAddress != nil && strings.EqualFold(*u.WalletAddress, existing.SellerAddress) {
+		return existing, nil
+	}
+	if err := ensureAdmin(ctx, s.userRepo, userID); err != nil {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "You cannot view this order")
+	}
+	return existing, nil
+}
+
+// ListMine returns one page of the caller's orders.
+func (s *OrderService) ListMine(ctx echo.Context, q order.GetOrdersQuery, userID string) (*model.PaginatedResponse[order.Order], error) {
+	items, total, err := s.repo.ListByBuyerPaginated(ctx.Request().Context(), userID, q)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := 0
+	if q.Limit > 0 {
+		totalPages = int((total + int64(q.Limit) - 1) / int64(q.Limit))
+	}
+
+	return &model.PaginatedResponse[order.Order]{
+		Data:       items,
+		Page:       q.Page,
+		Limit:      q.Limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}, nil
+}
+
+// MarkPaid records the buyer's payment for a reserved order. Once paid the
+// order no longer expires.
+func (s *OrderService) MarkPaid(ctx echo.Context, id string, payload order.PayOrderPayload, userID string) (*order.Order, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("user_id", userID).Str("order_id", id).Msg("marking order paid")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.buyerOrder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != order.OrderStatusReserved {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Only reserved orders can be paid")
+	}
+
+	paid, err := s.repo.MarkPaid(ctx.Request().Context(), id, payload.PaymentReference)
+	if err != nil {
+		return nil, err
+	}
+	if paid == nil {
+		return nil, echo.NewHTTPError(http.StatusConflict, "Order reservation has expired")
+	}
+	return paid, nil
+}
+
+// Cancel releases a reserved order back to its listing.
+func (s *OrderService) Cancel(ctx echo.Context, id string, userID string) (*order.Order, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("user_id", userID).Str("order_id", id).Msg("cancelling order")
+
+	if _, err := s.buyerOrder(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	cancelled, err := s.repo.Cancel(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if cancelled == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Only reserved orders can be cancelled")
+	}
+	return cancelled, nil
+}
+
+// Settle completes a paid order with the hash of the transaction that moved
+// its tokens from the seller to the buyer. Only the seller or an admin can
+// settle, and the transfer is checked on chain before it is recorded.
+func (s *OrderService) Settle(ctx echo.Context, id string, payload order.SettleOrderPayload, userID string) (*order.Order, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("user_id", userID).Str("order_id", id).Msg("settling order")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.FindByClerkID(ctx.Request().Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+	isSeller := u != nil && u.WalletAddress != nil && strings.EqualFold(*u.WalletAddress, existing.SellerAddress)
+	if !isSeller {
+		if err := ensureAdmin(ctx, s.userRepo, userID); err != nil {
+			return nil, echo.NewHTTPError(http.StatusForbidden, "Only the seller can settle this order")
+		}
+	}
+
+	if existing.Status != order.OrderStatusPaid {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Only paid orders can be settled")
+	}
+
+	txHash := common.HexToHash(payload.TxHash)
+	if err := s.verifySettlement(ctx.Request().Context(), existing, txHash); err != nil {
+		return nil, err
+	}
+
+	settled, err := s.repo.Settle(ctx.Request().Context(), id, txHash.Hex())
+	if err != nil {
+		if errors.Is(err, repository.ErrSettlementTxUsed) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "Settlement transaction already used")
+		}
+		return nil, err
+	}
+	if settled == nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Only paid orders can be settled")
+	}
+
+	if p, err := s.projectRepo.FindByID(ctx.Request().Context(), settled.ProjectID.String()); err == nil && p != nil {
+		s.notifier.ListingSold(ctx.Request().Context(), settled, p.Title)
+	}
+	return settled, nil
+}
+
+// HandleExpireOrdersTask is the asynq handler for job.TaskExpireOrders.
+func (s *OrderService) HandleExpireOrdersTask(ctx context.Context, t *asynq.Task) error {
+	_, err := s.ExpireReservations(ctx)
+	return err
+}
+
+// ExpireReservations expires every lapsed RESERVED order and returns its
+// quantity to the listing.
+func (s *OrderService) ExpireReservations(ctx context.Context) (int64, error) {
+	expired, err := s.repo.ExpireReservations(ctx)
+	if err != nil {
+		s.server.Logger.Error().Err(err).Msg("failed to expire order reservations")
+		return 0, err
+	}
+	if expired > 0 {
+		s.server.Logger.Info().Int64("count", expired).Msg("expired order reservations")
+	}
+	return expired, nil
+}
+
+// verifySettlement checks that txHash transferred at least the order's
+// quantity of the project's token from the seller to the buyer.
+func (s *OrderService) verifySettlement(ctx context.Context, o *order.Order, txHash common.Hash) error {
+	chain := s.server.Blockchain
+	if chain == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "Blockchain client is not available")
+	}
+
+	p, err := s.projectRepo.FindByID(ctx, o.ProjectID.String())
+	if err != nil {
+		return err
+	}
+	if p == nil || p.ContractAddress == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Project has no deployed token")
+	}
+	tokenAddress := common.HexToAddress(*p.ContractAddress)
+
+	tokenContract, err := chain.Token(tokenAddress)
+	if err != nil {
+		return err
+	}
+	decimals, err := tokenDecimals(ctx, chain, tokenContract)
+	if err != nil {
+		return err
+	}
+	want, err := blockchain.ToBaseUnits(strconv.FormatFloat(o.Quantity, 'f', -1, 64), decimals)
+	if err != nil {
+		return err
+	}
+
+	transfers, err := chain.TransfersInTx(ctx, txHash, tokenAddress)
+	if err != nil {
+		switch {
+		case errors.Is(err, blockchain.ErrTransactionNotFound):
+			return echo.NewHTTPError(http.StatusBadRequest, "Settlement transaction is not mined yet")
+		case errors.Is(err, blockchain.ErrTransactionReverted):
+			return echo.NewHTTPError(http.StatusBadRequest, "Settlement transaction reverted")
+		}
+		return err
+	}
+
+	seller := common.HexToAddress(o.SellerAddress)
+	buyer := common.HexToAddress(o.BuyerAddress)
+	delivered := new(big.Int)
+	for _, t := range transfers {
+		if t.From == seller && t.To == buyer {
+			delivered.Add(delivered, t.Value)
+		}
+	}
+	if delivered.Cmp(want) < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Settlement transaction does not transfer the ordered tokens to the buyer")
+	}
+	return nil
+}
+
+func (s *OrderService) findOrder(ctx echo.Context, id string) (*order.Order, error) {
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Order not found")
+	}
+	return existing, nil
+}
+
+// buyerOrder loads an order and checks that the caller placed it.
+func (s *OrderService) buyerOrder(ctx echo.Context, id string, userID string) (*order.Order, error) {
+	existing, err := s.findOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.BuyerID != userID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "You do not own this order")
+	}
+	return existing, nil
+}
+
+func (s *OrderService) reservationTTL() time.Duration {
+	if ttl := s.server.Config.Marketplace.ReservationTTL; ttl > 0 {
+		return time.Duration(ttl) * time.Second
+	}
+	return DefaultReservationTTL
+}
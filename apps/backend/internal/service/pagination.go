@@ -0,0 +1,18 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// decodeCursor parses a client supplied pagination cursor. The empty cursor
+// is the first page and decodes to nil.
+func decodeCursor(raw string) (*model.Cursor, error) {
+	after, err := model.DecodeCursor(raw)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid cursor")
+	}
+	return after, nil
+}
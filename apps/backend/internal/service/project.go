@@ -1,13 +1,19 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"strings"
 
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/lib/geo"
+	"github.com/inventedsarawak/ledgera/internal/lib/pdf"
 	"github.com/inventedsarawak/ledgera/internal/lib/upload"
 	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
 	"github.com/inventedsarawak/ledgera/internal/model/project"
 	"github.com/inventedsarawak/ledgera/internal/model/user"
 	"github.com/inventedsarawak/ledgera/internal/repository"
@@ -16,16 +22,24 @@ import (
 )
 
 type ProjectService struct {
-	repo     *repository.ProjectRepository
-	userRepo *repository.UserRepository
-	uploader *upload.Client
+	server       *server.Server
+	repo         *repository.ProjectRepository
+	userRepo     *repository.UserRepository
+	commentRepo  *repository.ReviewCommentRepository
+	documentRepo *repository.ProjectDocumentRepository
+	uploader     *upload.Client
+	notifier     *notifier
 }
 
-func NewProjectService(s *server.Server, repo *repository.ProjectRepository, userRepo *repository.UserRepository) *ProjectService {
+func NewProjectService(s *server.Server, repo *repository.ProjectRepository, userRepo *repository.UserRepository, commentRepo *repository.ReviewCommentRepository, documentRepo *repository.ProjectDocumentRepository) *ProjectService {
 	return &ProjectService{
-		repo:     repo,
-		userRepo: userRepo,
-		uploader: s.Uploader,
+		server:       s,
+		repo:         repo,
+		userRepo:     userRepo,
+		commentRepo:  commentRepo,
+		documentRepo: documentRepo,
+		uploader:     s.Uploader,
+		notifier:     newNotifier(s, userRepo),
 	}
 }
 
@@ -39,19 +53,21 @@ func (s *ProjectService) Create(ctx echo.Context, payload project.CreateProjectP
 	}
 
 	// 1. Upload Image (Required)
-	imageURL, err := s.uploadFile(ctx, imageFile, "projects", supplierID)
+	image, err := s.uploadDocument(ctx, imageFile, project.DocumentImage, supplierID)
 	if err != nil {
 		return nil, err
 	}
+	documents := []*project.Document{image}
 
 	// 2. Upload Audit Report (Optional)
 	var auditReportURL string
 	if auditFile != nil {
-		url, err := s.uploadFile(ctx, auditFile, "documents", supplierID)
+		audit, err := s.uploadDocument(ctx, auditFile, project.DocumentAuditReport, supplierID)
 		if err != nil {
 			return nil, err
 		}
-		auditReportURL = url
+		auditReportURL = audit.URL
+		documents = append(documents, audit)
 	}
 
 	// Define Base Price (Hardcoded for MVP)
@@ -61,16 +77,16 @@ func (s *ProjectService) Create(ctx echo.Context, payload project.CreateProjectP
 		SupplierID:  supplierID,
 		Title:       payload.Title,
 		Description: payload.Description,
-		
-		ImageURL:       imageURL,
+
+		ImageURL:       image.URL,
 		AuditReportURL: auditReportURL, // Save the URL
 
 		LocationLat: payload.LocationLat,
 		LocationLng: payload.LocationLng,
 		Area:        payload.Area,
 
-		CarbonAmount: payload.CarbonAmount,
-		PricePerTonne:     INITIAL_MARKET_PRICE,
+		CarbonAmount:  payload.CarbonAmount,
+		PricePerTonne: INITIAL_MARKET_PRICE,
 
 		Status: project.ProjectStatusDraft,
 	}
@@ -81,18 +97,159 @@ func (s *ProjectService) Create(ctx echo.Context, payload project.CreateProjectP
 		return nil, err
 	}
 
+	for _, d := range documents {
+		d.ProjectID = createdProject.ID
+		if _, err := s.documentRepo.Create(ctx.Request().Context(), *d); err != nil {
+			logger.Error().Err(err).Msg("failed to record project document")
+			return nil, err
+		}
+	}
+
 	logger.Info().Str("project_id", createdProject.ID.String()).Msg("project created successfully")
 	return createdProject, nil
 }
 
-func (s *ProjectService) GetByID(ctx echo.Context, id string) (*project.Project, error) {
-	return s.repo.FindByID(ctx.Request().Context(), id)
+// GetByID returns a project. Its supplier and admins also get the review
+// comments.
+func (s *ProjectService) GetByID(ctx echo.Context, id string, userID string) (*project.ProjectDetail, error) {
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+
+	detail := &project.ProjectDetail{Project: *existing}
+	if _, err := s.reviewerRole(ctx, existing, userID); err != nil {
+		return detail, nil
+	}
+
+	comments, err := s.commentRepo.ListByProject(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	detail.ReviewComments = project.ThreadComments(comments)
+	return detail, nil
+}
+
+// FactSheet renders the one-page PDF summary of a project.
+func (s *ProjectService) FactSheet(ctx echo.Context, id string) ([]byte, error) {
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+
+	return pdf.RenderFactSheet(*existing)
 }
 
 func (s *ProjectService) ListBySupplier(ctx echo.Context, supplierID string, page int, limit int) ([]project.Project, int64, error) {
 	return s.repo.ListBySupplierPaginated(ctx.Request().Context(), supplierID, page, limit)
 }
 
+// ListBySupplierAfter is the keyset-paginated form of ListBySupplier. It
+// also returns the cursor of the next page, if any.
+func (s *ProjectService) ListBySupplierAfter(ctx echo.Context, supplierID string, cursor string, limit int) ([]project.Project, int64, *model.Cursor, error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return s.repo.ListBySupplierAfter(ctx.Request().Context(), supplierID, after, limit)
+}
+
+// publicProjectStatuses are the statuses anyone may browse: projects that
+// passed review.
+var publicProjectStatuses = []project.ProjectStatus{project.ProjectStatusApproved, project.ProjectStatusDeployed}
+
+// Browse is the public project listing. Only approved and deployed projects
+// are included, whatever q.Status asks for. In cursor mode the cursor of the
+// next page, if any, is returned too.
+func (s *ProjectService) Browse(ctx echo.Context, q project.GetProjectsQuery) ([]project.Project, int64, *model.Cursor, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if q.Status != nil && *q.Status != project.ProjectStatusApproved && *q.Status != project.ProjectStatusDeployed {
+		return nil, 0, nil, echo.NewHTTPError(http.StatusBadRequest, "Only approved and deployed projects can be browsed")
+	}
+	if q.MinPrice != nil && q.MaxPrice != nil && *q.MinPrice > *q.MaxPrice {
+		return nil, 0, nil, echo.NewHTTPError(http.StatusBadRequest, "minPrice must not be greater than maxPrice")
+	}
+	if q.MinCarbon != nil && q.MaxCarbon != nil && *q.MinCarbon > *q.MaxCarbon {
+		return nil, 0, nil, echo.NewHTTPError(http.StatusBadRequest, "minCarbon must not be greater than maxCarbon")
+	}
+	if q.CreatedAfter != nil && q.CreatedBefore != nil && q.CreatedAfter.After(*q.CreatedBefore) {
+		return nil, 0, nil, echo.NewHTTPError(http.StatusBadRequest, "createdAfter must not be later than createdBefore")
+	}
+
+	if q.Cursor != nil {
+		if (q.Sort != nil && *q.Sort != "created_at") || (q.Order != nil && *q.Order != "desc") {
+			return nil, 0, nil, echo.NewHTTPError(http.StatusBadRequest, "Cursor pagination is only supported when sorting by newest first")
+		}
+		after, err := decodeCursor(*q.Cursor)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		limit := 0
+		if q.Limit != nil {
+			limit = *q.Limit
+		}
+		items, total, next, err := s.repo.SearchAfter(ctx.Request().Context(), q, publicProjectStatuses, after, limit)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to browse projects")
+			return nil, 0, nil, err
+		}
+		return items, total, next, nil
+	}
+
+	items, total, err := s.repo.Search(ctx.Request().Context(), q, publicProjectStatuses)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to browse projects")
+		return nil, 0, nil, err
+	}
+	return items, total, nil, nil
+}
+
+// InBBox returns the public projects inside a map viewport as GeoJSON.
+func (s *ProjectService) InBBox(ctx echo.Context, q project.GetProjectsInBBoxQuery) (*project.FeatureCollection, error) {
+	bbox, err := project.ParseBBox(q.BBox)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	items, err := s.repo.ListInBBox(ctx.Request().Context(), bbox, publicProjectStatuses, *q.Limit)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to list projects in bbox")
+		return nil, err
+	}
+
+	features := make([]project.Feature, 0, len(items))
+	for _, p := range items {
+		features = append(features, project.NewFeature(p))
+	}
+	return project.NewFeatureCollection(features), nil
+}
+
+// Near returns the public projects within a radius as GeoJSON, nearest
+// first, each with its distance.
+func (s *ProjectService) Near(ctx echo.Context, q project.GetProjectsNearQuery) (*project.FeatureCollection, error) {
+	items, err := s.repo.ListNear(ctx.Request().Context(), *q.Lat, *q.Lng, q.RadiusKm, publicProjectStatuses, *q.Limit)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to list projects near point")
+		return nil, err
+	}
+
+	features := make([]project.Feature, 0, len(items))
+	for _, p := range items {
+		f := project.NewFeature(p.Project)
+		distance := p.DistanceKm
+		f.Properties.DistanceKm = &distance
+		features = append(features, f)
+	}
+	return project.NewFeatureCollection(features), nil
+}
+
 // Update now accepts optional auditFile
 func (s *ProjectService) Update(ctx echo.Context, id string, payload project.UpdateProjectPayload, userID string, imageFile *multipart.FileHeader, auditFile *multipart.FileHeader) (*project.Project, error) {
 	logger := middleware.GetLogger(ctx)
@@ -112,61 +269,125 @@ func (s *ProjectService) Update(ctx echo.Context, id string, payload project.Upd
 	if existing.SupplierID != userID {
 		return nil, echo.NewHTTPError(http.StatusForbidden, "You do not own this project")
 	}
-	if existing.Status == project.ProjectStatusPending || existing.Status == project.ProjectStatusApproved || existing.Status == project.ProjectStatusDeployed {
+	if !existing.Status.Editable() {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, "Project cannot be edited after submission")
 	}
 
-	// Handle Image Update
-	var newImageURL *string
+	// A new image or audit report replaces the current one, whose object is
+	// deleted rather than left behind in the bucket.
 	if imageFile != nil {
-		url, err := s.uploadFile(ctx, imageFile, "projects", userID)
-		if err != nil {
+		if err := s.replacePrimaryDocument(ctx, existing, imageFile, project.DocumentImage, userID); err != nil {
 			return nil, err
 		}
-		newImageURL = &url
 	}
-
-	// Handle Audit Report Update
-	var newAuditURL *string
 	if auditFile != nil {
-		url, err := s.uploadFile(ctx, auditFile, "documents", userID)
-		if err != nil {
+		if err := s.replacePrimaryDocument(ctx, existing, auditFile, project.DocumentAuditReport, userID); err != nil {
 			return nil, err
 		}
-		newAuditURL = &url
 	}
 
-	// Pass both URLs to the Repo
-	updated, err := s.repo.Update(ctx.Request().Context(), id, payload, newImageURL, newAuditURL)
+	updated, err := s.repo.Update(ctx.Request().Context(), id, payload)
 	if err != nil {
 		return nil, err
 	}
+
+	// Revising a rejected project reopens it as a draft.
+	if updated != nil && updated.Status == project.ProjectStatusRejected {
+		return s.transition(ctx, updated, project.ProjectStatusDraft, userID, nil)
+	}
 	return updated, nil
 }
 
-// Helper to reduce duplication
-func (s *ProjectService) uploadFile(ctx echo.Context, file *multipart.FileHeader, folder string, userID string) (string, error) {
-	if s.uploader == nil {
-		return fmt.Sprintf("https://example.com/%s/%s", folder, file.Filename), nil
+// maxBoundaryFileSize bounds boundary uploads; geo.MaxPositions vertices fit
+// comfortably in it as GeoJSON or KML.
+const maxBoundaryFileSize = 2 << 20
+
+// SetBoundary replaces the boundary of a project with the polygon in file, a
+// GeoJSON or KML document. The area is measured server-side and compared with
+// the declared one.
+func (s *ProjectService) SetBoundary(ctx echo.Context, id string, userID string, file *multipart.FileHeader) (*project.Project, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("user_id", userID).Msg("setting project boundary")
+
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	if existing.SupplierID != userID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "You do not own this project")
+	}
+	if !existing.Status.Editable() {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Project cannot be edited after submission")
 	}
 
-	fileContent, err := file.Open()
+	if file.Size > maxBoundaryFileSize {
+		return nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Boundary file must not exceed 2 MB")
+	}
+	f, err := file.Open()
 	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxBoundaryFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > maxBoundaryFileSize {
+		return nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge, "Boundary file must not exceed 2 MB")
 	}
-	defer fileContent.Close()
 
-	return s.uploader.Upload(ctx.Request().Context(), upload.UploadParams{
-		File:        fileContent,
-		Folder:      folder,
-		Filename:    file.Filename,
-		UserID:      userID,
-		ContentType: file.Header.Get("Content-Type"),
-		Size:        file.Size,
-	})
+	boundary, err := geo.Parse(data)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := boundary.Validate(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	updated, err := s.repo.SetBoundary(ctx.Request().Context(), id, boundary.GeoJSON(), boundary.AreaHectares())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to store project boundary")
+		return nil, err
+	}
+	if updated == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	if updated.AreaMismatch {
+		logger.Warn().Str("project_id", id).Float64("declared_ha", updated.Area).Float64("measured_ha", *updated.BoundaryAreaHa).Msg("project boundary does not match declared area")
+	}
+
+	// Revising a rejected project reopens it as a draft.
+	if updated.Status == project.ProjectStatusRejected {
+		return s.transition(ctx, updated, project.ProjectStatusDraft, userID, nil)
+	}
+	return updated, nil
+}
+
+// Boundary returns the boundary of a project as a GeoJSON feature.
+func (s *ProjectService) Boundary(ctx echo.Context, id string) (*project.BoundaryFeature, error) {
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+
+	geometry, err := s.repo.Boundary(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if geometry == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project has no boundary")
+	}
+
+	feature := project.NewBoundaryFeature(*existing, geometry)
+	return &feature, nil
 }
 
-// ... (Rest of the service methods: Delete, SendForApproval, ListPendingForReview, Approve, Reject, ensureAdmin remain unchanged) ...
 func (s *ProjectService) Delete(ctx echo.Context, id string, userID string) error {
 	logger := middleware.GetLogger(ctx)
 	logger.Info().Str("project_id", id).Str("user_id", userID).Msg("deleting project")
@@ -181,12 +402,23 @@ func (s *ProjectService) Delete(ctx echo.Context, id string, userID string) erro
 	if existing.SupplierID != userID {
 		return echo.NewHTTPError(http.StatusForbidden, "You do not own this project")
 	}
-	// Allow delete only when editable (DRAFT or REJECTED)
-	if !(existing.Status == project.ProjectStatusDraft || existing.Status == project.ProjectStatusRejected) {
+	if !existing.Status.Editable() {
 		return echo.NewHTTPError(http.StatusBadRequest, "Project cannot be deleted after submission")
 	}
 
-	return s.repo.Delete(ctx.Request().Context(), id)
+	documents, err := s.documentRepo.ListByProject(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx.Request().Context(), id); err != nil {
+		return err
+	}
+
+	// The rows went with the project; their objects have to be removed here.
+	for _, d := range documents {
+		s.deleteObject(ctx, d.ObjectKey)
+	}
+	return nil
 }
 
 func (s *ProjectService) SendForApproval(ctx echo.Context, id string, userID string) error {
@@ -203,15 +435,23 @@ func (s *ProjectService) SendForApproval(ctx echo.Context, id string, userID str
 	if existing.SupplierID != userID {
 		return echo.NewHTTPError(http.StatusForbidden, "You do not own this project")
 	}
-	if existing.Status == project.ProjectStatusPending {
-		return echo.NewHTTPError(http.StatusBadRequest, "Project is already submitted")
+	// Checked up front so a refused submission leaves the warnings alone.
+	if !existing.Status.CanTransitionTo(project.ProjectStatusPending) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project cannot move from %s to %s", existing.Status, project.ProjectStatusPending))
 	}
-	if existing.Status == project.ProjectStatusApproved || existing.Status == project.ProjectStatusDeployed {
-		return echo.NewHTTPError(http.StatusBadRequest, "Approved or deployed project cannot be re-submitted")
+
+	if err := s.flagConflicts(ctx, existing); err != nil {
+		logger.Error().Err(err).Msg("failed to check project for conflicts")
+		return err
+	}
+
+	submitted, err := s.transition(ctx, existing, project.ProjectStatusPending, userID, nil)
+	if err != nil {
+		return err
 	}
 
-	_, err = s.repo.UpdateStatus(ctx.Request().Context(), id, project.ProjectStatusPending)
-	return err
+	s.notifier.ProjectSubmitted(ctx.Request().Context(), submitted)
+	return nil
 }
 
 func (s *ProjectService) ListPendingForReview(ctx echo.Context, adminID string, page int, limit int) ([]project.ProjectWithSupplier, int64, error) {
@@ -228,6 +468,75 @@ func (s *ProjectService) ListPendingForReview(ctx echo.Context, adminID string,
 		return nil, 0, err
 	}
 
+	results, err := s.forReview(ctx, projectsList)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load review warnings")
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+// ListPendingForReviewAfter is the keyset-paginated form of
+// ListPendingForReview.
+func (s *ProjectService) ListPendingForReviewAfter(ctx echo.Context, adminID string, cursor string, limit int) ([]project.ProjectWithSupplier, int64, *model.Cursor, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("admin_id", adminID).Msg("listing pending projects for review")
+
+	if err := s.ensureAdmin(ctx, adminID); err != nil {
+		return nil, 0, nil, err
+	}
+
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	projectsList, total, next, err := s.repo.ListByStatusAfter(ctx.Request().Context(), project.ProjectStatusPending, after, limit)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list pending projects")
+		return nil, 0, nil, err
+	}
+
+	results, err := s.forReview(ctx, projectsList)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load review warnings")
+		return nil, 0, nil, err
+	}
+	return results, total, next, nil
+}
+
+// flagConflicts records, as review warnings, the approved and deployed
+// projects p may double count: shared land, nearby locations or a similar
+// title.
+func (s *ProjectService) flagConflicts(ctx echo.Context, p *project.Project) error {
+	conflicts, err := s.repo.FindConflicts(ctx.Request().Context(), p.ID.String(), publicProjectStatuses)
+	if err != nil {
+		return err
+	}
+
+	var warnings []project.ReviewWarning
+	for _, c := range conflicts {
+		warnings = append(warnings, c.Warnings(p.ID)...)
+	}
+	if len(warnings) > 0 {
+		middleware.GetLogger(ctx).Warn().Str("project_id", p.ID.String()).Int("warnings", len(warnings)).Msg("submitted project may duplicate existing projects")
+	}
+
+	return s.repo.ReplaceReviewWarnings(ctx.Request().Context(), p.ID.String(), warnings)
+}
+
+// forReview attaches each project's supplier email and review warnings for
+// reviewers.
+func (s *ProjectService) forReview(ctx echo.Context, projectsList []project.Project) ([]project.ProjectWithSupplier, error) {
+	ids := make([]uuid.UUID, len(projectsList))
+	for i, p := range projectsList {
+		ids[i] = p.ID
+	}
+	warnings, err := s.repo.ListReviewWarnings(ctx.Request().Context(), ids)
+	if err != nil {
+		return nil, err
+	}
+
 	var results []project.ProjectWithSupplier
 	for _, p := range projectsList {
 		email := "unknown"
@@ -236,13 +545,18 @@ func (s *ProjectService) ListPendingForReview(ctx echo.Context, adminID string,
 			email = supplier.Email
 		}
 
+		projectWarnings := warnings[p.ID]
+		if projectWarnings == nil {
+			projectWarnings = []project.ReviewWarning{}
+		}
+
 		results = append(results, project.ProjectWithSupplier{
 			Project:       p,
 			SupplierEmail: email,
+			Warnings:      projectWarnings,
 		})
 	}
-
-	return results, total, nil
+	return results, nil
 }
 
 func (s *ProjectService) Approve(ctx echo.Context, id string, adminID string) (*project.Project, error) {
@@ -260,85 +574,245 @@ func (s *ProjectService) Approve(ctx echo.Context, id string, adminID string) (*
 	if existing == nil {
 		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
 	}
-	if existing.Status != project.ProjectStatusPending {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, "Only pending projects can be approved")
-	}
 
-	updated, err := s.repo.UpdateStatus(ctx.Request().Context(), id, project.ProjectStatusApproved)
+	updated, err := s.transition(ctx, existing, project.ProjectStatusApproved, adminID, nil)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to approve project")
-        return nil, err
-    }
+		return nil, err
+	}
+
+	s.notifier.ProjectApproved(ctx.Request().Context(), updated)
+	return updated, nil
+}
+
+// Reject sends a pending project back to its supplier. The reason is kept in
+// the status history and opens a review thread, anchored to payload.Field
+// when the problem is with a specific attribute.
+func (s *ProjectService) Reject(ctx echo.Context, id string, adminID string, payload project.RejectProjectPayload) (*project.Project, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("admin_id", adminID).Msg("rejecting project")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.ensureAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+
+	reason := &project.StatusReason{Code: &payload.Code, Message: payload.Message}
+	updated, err := s.transition(ctx, existing, project.ProjectStatusRejected, adminID, reason)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to reject project")
+		return nil, err
+	}
+
+	_, err = s.commentRepo.Create(ctx.Request().Context(), project.ReviewComment{
+		ProjectID:  updated.ID,
+		AuthorID:   adminID,
+		AuthorRole: user.RoleAdmin,
+		Field:      payload.Field,
+		Body:       payload.Message,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to record rejection comment")
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.ListByProject(ctx.Request().Context(), id)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load review comments for rejection email")
+		return updated, nil
+	}
+
+	reasonText := fmt.Sprintf("%s: %s", payload.Code.Label(), payload.Message)
+	s.notifier.ProjectRejected(ctx.Request().Context(), updated, reasonText, formatFeedback(project.ThreadComments(comments)))
+	return updated, nil
+}
+
+// ListComments returns a project's review threads to its supplier or an
+// admin.
+func (s *ProjectService) ListComments(ctx echo.Context, id string, userID string) ([]project.ReviewComment, error) {
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	if _, err := s.reviewerRole(ctx, existing, userID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.ListByProject(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return project.ThreadComments(comments), nil
+}
+
+// AddComment posts review feedback on a PENDING or REJECTED project. Replies
+// always attach to the top-level comment of their thread and inherit its
+// field anchor unless they name one.
+func (s *ProjectService) AddComment(ctx echo.Context, id string, userID string, payload project.CreateReviewCommentPayload) (*project.ReviewComment, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("user_id", userID).Msg("adding review comment")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	role, err := s.reviewerRole(ctx, existing, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.Status.Reviewable() {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Comments are only open while a project is pending or rejected")
+	}
+
+	comment := project.ReviewComment{
+		ProjectID:  existing.ID,
+		AuthorID:   userID,
+		AuthorRole: role,
+		Field:      payload.Field,
+		Body:       payload.Body,
+	}
+
+	if payload.ParentID != nil {
+		parent, err := s.commentRepo.FindByID(ctx.Request().Context(), payload.ParentID.String())
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.ProjectID != existing.ID {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Parent comment not found on this project")
+		}
+
+		comment.ParentID = &parent.ID
+		if parent.ParentID != nil {
+			comment.ParentID = parent.ParentID
+		}
+		if comment.Field == nil {
+			comment.Field = parent.Field
+		}
+	}
+
+	return s.commentRepo.Create(ctx.Request().Context(), comment)
+}
+
+// reviewerRole returns the role userID takes part in a project's review with:
+// SUPPLIER for its owner, ADMIN for admins. Anyone else is refused.
+func (s *ProjectService) reviewerRole(ctx echo.Context, p *project.Project, userID string) (user.UserRole, error) {
+	if p.SupplierID == userID {
+		return user.RoleSupplier, nil
+	}
+	if err := s.ensureAdmin(ctx, userID); err != nil {
+		return "", err
+	}
+	return user.RoleAdmin, nil
+}
+
+// formatFeedback renders review threads as plain text for emails, one line
+// per comment with replies indented under their thread.
+func formatFeedback(threads []project.ReviewComment) string {
+	var b strings.Builder
+	line := func(indent string, c project.ReviewComment) {
+		b.WriteString(indent)
+		if c.Field != nil {
+			fmt.Fprintf(&b, "[%s] ", *c.Field)
+		}
+		fmt.Fprintf(&b, "%s: %s\n", c.AuthorRole, c.Body)
+	}
+
+	for _, thread := range threads {
+		line("", thread)
+		for _, reply := range thread.Replies {
+			line("  ", reply)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
 
-    return updated, nil
+// History returns the status changes of a project to its supplier or an
+// admin.
+func (s *ProjectService) History(ctx echo.Context, id string, userID string) ([]project.StatusChange, error) {
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	if existing.SupplierID != userID {
+		if err := s.ensureAdmin(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.ListStatusHistory(ctx.Request().Context(), id)
 }
 
-func (s *ProjectService) Reject(ctx echo.Context, id string, adminID string) (*project.Project, error) {
-    logger := middleware.GetLogger(ctx)
-    logger.Info().Str("project_id", id).Str("admin_id", adminID).Msg("rejecting project")
-
-    if err := s.ensureAdmin(ctx, adminID); err != nil {
-        return nil, err
-    }
-
-    existing, err := s.repo.FindByID(ctx.Request().Context(), id)
-    if err != nil {
-        return nil, err
-    }
-    if existing == nil {
-        return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
-    }
-    if existing.Status != project.ProjectStatusPending {
-        return nil, echo.NewHTTPError(http.StatusBadRequest, "Only pending projects can be rejected")
-    }
-
-    updated, err := s.repo.UpdateStatus(ctx.Request().Context(), id, project.ProjectStatusRejected)
-    if err != nil {
-        logger.Error().Err(err).Msg("failed to reject project")
-        return nil, err
-    }
-
-    return updated, nil
+// transition moves a project along the lifecycle on behalf of actorID. Moves
+// the lifecycle does not allow are refused with 400, and a project that
+// changed status in the meantime with 409.
+func (s *ProjectService) transition(ctx echo.Context, existing *project.Project, to project.ProjectStatus, actorID string, reason *project.StatusReason) (*project.Project, error) {
+	updated, err := s.repo.TransitionStatus(ctx.Request().Context(), existing.ID.String(), existing.Status, to, &actorID, reason)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidTransition) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project cannot move from %s to %s", existing.Status, to))
+		}
+		return nil, err
+	}
+	if updated == nil {
+		return nil, echo.NewHTTPError(http.StatusConflict, "Project status changed, please retry")
+	}
+	return updated, nil
 }
 
 func (s *ProjectService) ensureAdmin(ctx echo.Context, clerkID string) error {
-    ctxRole := strings.ToUpper(strings.TrimSpace(middleware.GetUserRole(ctx)))
-    logger := middleware.GetLogger(ctx)
-
-    // DEBUG LOGGING
-    logger.Info().
-        Str("context_role", ctxRole).
-        Str("clerk_id", clerkID).
-        Msg("DEBUG: EnsureAdmin Check")
-
-    if ctxRole == string(user.RoleAdmin) {
-        return nil
-    }
-
-    if clerkID == "" {
-        return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
-    }
-
-    logger.Debug().Str("clerk_id", clerkID).Msg("verifying admin access via repository")
-
-    adminUser, err := s.userRepo.FindByClerkID(ctx.Request().Context(), clerkID)
-    if err != nil {
-        logger.Error().Err(err).Msg("failed to load user for admin verification")
-        return err
-    }
-    if adminUser == nil {
-        return echo.NewHTTPError(http.StatusForbidden, "User record missing")
-    }
-
-    // DEBUG LOGGING
-    logger.Info().
-        Str("db_role", string(adminUser.Role)).
-        Str("db_user_id", adminUser.ID.String()).
-        Msg("DEBUG: EnsureAdmin DB Result")
-
-    if adminUser.Role != user.RoleAdmin {
-        return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Admin access required. ContextRole=%s, DBRole=%s", ctxRole, adminUser.Role))
-    }
-
-    return nil
-}
\ No newline at end of file
+	return ensureAdmin(ctx, s.userRepo, clerkID)
+}
+
+// ensureAdmin is shared by every service exposing admin-only operations.
+func ensureAdmin(ctx echo.Context, userRepo *repository.UserRepository, clerkID string) error {
+	ctxRole := strings.ToUpper(strings.TrimSpace(middleware.GetUserRole(ctx)))
+	logger := middleware.GetLogger(ctx)
+
+	if ctxRole == string(user.RoleAdmin) {
+		return nil
+	}
+
+	if clerkID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
+	}
+
+	logger.Debug().Str("clerk_id", clerkID).Msg("verifying admin access via repository")
+
+	adminUser, err := userRepo.FindByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load user for admin verification")
+		return err
+	}
+	if adminUser == nil {
+		return echo.NewHTTPError(http.StatusForbidden, "User record missing")
+	}
+
+	if adminUser.Role != user.RoleAdmin {
+		return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Admin access required. ContextRole=%s, DBRole=%s", ctxRole, adminUser.Role))
+	}
+
+	return nil
+}
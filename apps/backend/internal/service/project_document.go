@@ -0,0 +1,337 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/inventedsarawak/ledgera/internal/lib/upload"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+// ListDocuments returns the documents of a project, grouped by type.
+func (s *ProjectService) ListDocuments(ctx echo.Context, id string) ([]project.Document, error) {
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+
+	return s.documentRepo.ListByProject(ctx.Request().Context(), id)
+}
+
+// AddDocument uploads file and attaches it to a project after its other
+// documents of type t.
+func (s *ProjectService) AddDocument(ctx echo.Context, id string, userID string, t project.DocumentType, file *multipart.FileHeader) (*project.Document, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("user_id", userID).Str("type", string(t)).Msg("adding project document")
+
+	existing, err := s.findForDocumentChange(ctx, id, userID, t)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.uploadDocument(ctx, file, t, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attachDocument(ctx, existing, d)
+}
+
+// PresignDocument issues a URL the client PUTs a file of type t to directly,
+// so large files do not pass through the API. The upload takes effect once
+// ConfirmDocument attaches it.
+func (s *ProjectService) PresignDocument(ctx echo.Context, id string, userID string, payload project.PresignDocumentPayload) (*upload.PresignedUpload, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("user_id", userID).Str("type", string(payload.Type)).Msg("presigning project document upload")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	if s.uploader == nil {
+		return nil, echo.NewHTTPError(http.StatusServiceUnavailable, "File storage is not available")
+	}
+
+	if _, err := s.findForDocumentChange(ctx, id, userID, payload.Type); err != nil {
+		return nil, err
+	}
+	if err := checkDocument(payload.Type, payload.ContentType, payload.Size); err != nil {
+		return nil, err
+	}
+
+	presigned, err := s.uploader.PresignPut(ctx.Request().Context(), upload.PresignParams{
+		Folder:      payload.Type.Folder(),
+		Filename:    payload.FileName,
+		UserID:      userID,
+		ContentType: payload.ContentType,
+		Size:        payload.Size,
+		Expires:     presignExpiry,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to presign upload")
+		return nil, err
+	}
+	return presigned, nil
+}
+
+// presignExpiry is how long a presigned upload URL stays valid.
+const presignExpiry = 15 * time.Minute
+
+// ConfirmDocument attaches an object uploaded through a URL from
+// PresignDocument, after checking the bucket actually holds it and that it
+// is within the limits of its type.
+func (s *ProjectService) ConfirmDocument(ctx echo.Context, id string, userID string, payload project.ConfirmDocumentPayload) (*project.Document, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("user_id", userID).Str("key", payload.Key).Msg("confirming project document upload")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	if s.uploader == nil {
+		return nil, echo.NewHTTPError(http.StatusServiceUnavailable, "File storage is not available")
+	}
+
+	existing, err := s.findForDocumentChange(ctx, id, userID, payload.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only keys issued to this user for this type, and each only once
+	if !upload.IssuedTo(payload.Key, payload.Type.Folder(), userID) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Upload was not issued to you for this document type")
+	}
+	attached, err := s.documentRepo.ExistsByObjectKey(ctx.Request().Context(), payload.Key)
+	if err != nil {
+		return nil, err
+	}
+	if attached {
+		return nil, echo.NewHTTPError(http.StatusConflict, "Upload is already attached")
+	}
+
+	info, err := s.uploader.Head(ctx.Request().Context(), payload.Key)
+	if err != nil {
+		if errors.Is(err, upload.ErrObjectNotFound) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Upload not found; PUT the file to the presigned URL first")
+		}
+		logger.Error().Err(err).Msg("failed to look up uploaded object")
+		return nil, err
+	}
+	if err := checkDocument(payload.Type, info.ContentType, info.Size); err != nil {
+		s.deleteObject(ctx, payload.Key)
+		return nil, err
+	}
+
+	return s.attachDocument(ctx, existing, &project.Document{
+		Type:        payload.Type,
+		ObjectKey:   payload.Key,
+		URL:         s.uploader.URL(payload.Key),
+		FileName:    payload.FileName,
+		ContentType: &info.ContentType,
+		SizeBytes:   &info.Size,
+		UploadedBy:  userID,
+	})
+}
+
+// attachDocument records d, whose object is already stored, after the other
+// documents of its type on existing.
+func (s *ProjectService) attachDocument(ctx echo.Context, existing *project.Project, d *project.Document) (*project.Document, error) {
+	d.ProjectID = existing.ID
+
+	created, err := s.documentRepo.Create(ctx.Request().Context(), *d)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to record project document")
+		s.deleteObject(ctx, d.ObjectKey)
+		return nil, err
+	}
+
+	if err := s.reopenIfRejected(ctx, existing, d.Type, d.UploadedBy); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// DeleteDocument removes a document from a project and its object from the
+// bucket.
+func (s *ProjectService) DeleteDocument(ctx echo.Context, id string, documentID string, userID string) error {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("document_id", documentID).Str("user_id", userID).Msg("deleting project document")
+
+	d, err := s.documentRepo.FindByID(ctx.Request().Context(), documentID)
+	if err != nil {
+		return err
+	}
+	if d == nil || d.ProjectID.String() != id {
+		return echo.NewHTTPError(http.StatusNotFound, "Document not found")
+	}
+
+	existing, err := s.findForDocumentChange(ctx, id, userID, d.Type)
+	if err != nil {
+		return err
+	}
+
+	if err := s.documentRepo.Delete(ctx.Request().Context(), *d); err != nil {
+		logger.Error().Err(err).Msg("failed to delete project document")
+		return err
+	}
+	s.deleteObject(ctx, d.ObjectKey)
+
+	return s.reopenIfRejected(ctx, existing, d.Type, userID)
+}
+
+// ReorderDocuments sets the order of a project's documents of one type. The
+// first image and audit report are the ones shown on the project.
+func (s *ProjectService) ReorderDocuments(ctx echo.Context, id string, userID string, payload project.ReorderDocumentsPayload) ([]project.Document, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info().Str("project_id", id).Str("user_id", userID).Str("type", string(payload.Type)).Msg("reordering project documents")
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findForDocumentChange(ctx, id, userID, payload.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := s.documentRepo.Reorder(ctx.Request().Context(), existing.ID, payload.Type, payload.IDs)
+	if err != nil {
+		if errors.Is(err, repository.ErrDocumentOrderMismatch) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "List every document of the type exactly once")
+		}
+		return nil, err
+	}
+
+	if err := s.reopenIfRejected(ctx, existing, payload.Type, userID); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// findForDocumentChange loads a project whose documents of type t userID is
+// about to change. Only its supplier may, and reviewed documents only while
+// the project can still be edited.
+func (s *ProjectService) findForDocumentChange(ctx echo.Context, id string, userID string, t project.DocumentType) (*project.Project, error) {
+	existing, err := s.repo.FindByID(ctx.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Project not found")
+	}
+	if existing.SupplierID != userID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "You do not own this project")
+	}
+	if t.Reviewed() && !existing.Status.Editable() {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Project documents cannot be changed after submission")
+	}
+	return existing, nil
+}
+
+// reopenIfRejected moves a rejected project back to draft once its reviewed
+// documents are revised, as Update does for its fields.
+func (s *ProjectService) reopenIfRejected(ctx echo.Context, existing *project.Project, t project.DocumentType, userID string) error {
+	if !t.Reviewed() || existing.Status != project.ProjectStatusRejected {
+		return nil
+	}
+	_, err := s.transition(ctx, existing, project.ProjectStatusDraft, userID, nil)
+	return err
+}
+
+// replacePrimaryDocument uploads file as the first document of type t of
+// existing, deleting the object of the document it replaces.
+func (s *ProjectService) replacePrimaryDocument(ctx echo.Context, existing *project.Project, file *multipart.FileHeader, t project.DocumentType, userID string) error {
+	d, err := s.uploadDocument(ctx, file, t, userID)
+	if err != nil {
+		return err
+	}
+	d.ProjectID = existing.ID
+
+	_, replaced, err := s.documentRepo.ReplacePrimary(ctx.Request().Context(), *d)
+	if err != nil {
+		s.deleteObject(ctx, d.ObjectKey)
+		return err
+	}
+	if replaced != nil {
+		s.deleteObject(ctx, replaced.ObjectKey)
+	}
+	return nil
+}
+
+// uploadDocument checks file against the limits of type t and stores it in
+// the bucket. The returned document is not attached to a project yet.
+func (s *ProjectService) uploadDocument(ctx echo.Context, file *multipart.FileHeader, t project.DocumentType, userID string) (*project.Document, error) {
+	// Generic types say nothing; fall back to the file extension.
+	contentType := file.Header.Get("Content-Type")
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = mime.TypeByExtension(path.Ext(file.Filename))
+	}
+	if err := checkDocument(t, contentType, file.Size); err != nil {
+		return nil, err
+	}
+
+	d := &project.Document{
+		Type:        t,
+		FileName:    file.Filename,
+		ContentType: &contentType,
+		SizeBytes:   &file.Size,
+		UploadedBy:  userID,
+	}
+
+	if s.uploader == nil {
+		d.ObjectKey = path.Join(t.Folder(), file.Filename)
+		d.URL = "https://example.com/" + d.ObjectKey
+		return d, nil
+	}
+
+	fileContent, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer fileContent.Close()
+
+	d.ObjectKey, err = s.uploader.Store(ctx.Request().Context(), upload.UploadParams{
+		File:        fileContent,
+		Folder:      t.Folder(),
+		Filename:    file.Filename,
+		UserID:      userID,
+		ContentType: contentType,
+		Size:        file.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.URL = s.uploader.URL(d.ObjectKey)
+	return d, nil
+}
+
+// checkDocument rejects files of type t with a content type or size it does
+// not allow.
+func checkDocument(t project.DocumentType, contentType string, size int64) error {
+	if !t.Accepts(contentType) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s files are not accepted as %s", contentType, t))
+	}
+	if size > t.MaxSize() {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("%s files must not exceed %d MB", t, t.MaxSize()>>20))
+	}
+	return nil
+}
+
+// deleteObject removes an object that is no longer referenced. Failures are
+// only logged: the row is already gone and the caller's change stands.
+func (s *ProjectService) deleteObject(ctx echo.Context, key string) {
+	if s.uploader == nil || key == "" {
+		return
+	}
+	if err := s.uploader.Delete(ctx.Request().Context(), key); err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Str("key", key).Msg("failed to delete object from bucket")
+	}
+}
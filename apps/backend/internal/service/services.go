@@ -7,18 +7,63 @@ import (
 )
 
 type Services struct {
-	Auth    *AuthService
-	Job     *job.JobService
-	Project *ProjectService
+	Auth        *AuthService
+	Job         *job.JobService
+	Project     *ProjectService
+	Asset       *AssetService
+	Indexer     *IndexerService
+	Listing     *ListingService
+	Order       *OrderService
+	Certificate *CertificateService
+	User        *UserService
 }
 
 func NewServices(s *server.Server, repos *repository.Repositories) (*Services, error) {
-	authService := NewAuthService(s, repos.User)
-	projectService := NewProjectService(s, repos.Project, repos.User)
+	authService, err := NewAuthService(s, repos.User, repos.WebhookEvent)
+	if err != nil {
+		return nil, err
+	}
+	projectService := NewProjectService(s, repos.Project, repos.User, repos.ReviewComment, repos.ProjectDocument)
+	assetService := NewAssetService(s, repos.Project, repos.User, repos.Token)
+	indexerService := NewIndexerService(s, repos.Token, repos.Project)
+	listingService := NewListingService(s, repos.Listing, repos.Project, repos.User)
+	orderService := NewOrderService(s, repos.Order, repos.Listing, repos.Project, repos.User)
+	certificateService := NewCertificateService(s, repos.Certificate, repos.Project, repos.User)
+	userService := NewUserService(s, repos.User, repos.Project, repos.Listing)
+
+	// Job handlers that need repositories are owned by their service
+	if s.Job != nil {
+		s.Job.Register(job.TaskDeployProject, assetService.HandleDeployProjectTask)
+		s.Job.Register(job.TaskMintTokens, assetService.HandleMintTokensTask)
+		s.Job.Register(job.TaskIndexerSync, indexerService.HandleSyncTask)
+		s.Job.Register(job.TaskExpireOrders, orderService.HandleExpireOrdersTask)
+		s.Job.Register(job.TaskRenderCertificate, certificateService.HandleRenderCertificateTask)
+		s.Job.Register(job.TaskPurgeDeletedUsers, userService.HandlePurgeDeletedUsersTask)
+
+		if err := s.Job.Schedule(job.ExpireOrdersInterval, job.NewExpireOrdersTask()); err != nil {
+			return nil, err
+		}
+
+		if err := s.Job.Schedule(job.PurgeDeletedUsersInterval, job.NewPurgeDeletedUsersTask()); err != nil {
+			return nil, err
+		}
+
+		if s.Blockchain != nil {
+			if err := s.Job.Schedule(job.IndexerSyncInterval, job.NewIndexerSyncTask()); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	return &Services{
-		Job:     s.Job,
-		Auth:    authService,
-		Project: projectService,
+		Job:         s.Job,
+		Auth:        authService,
+		Project:     projectService,
+		Asset:       assetService,
+		Indexer:     indexerService,
+		Listing:     listingService,
+		Order:       orderService,
+		Certificate: certificateService,
+		User:        userService,
 	}, nil
 }
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	clerkuser "github.com/clerk/clerk-sdk-go/v2/user"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hibiken/asynq"
+	"github.com/inventedsarawak/ledgera/internal/lib/siwe"
+	"github.com/inventedsarawak/ledgera/internal/middleware"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/server"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultAccountRetention is how long a deleted account is kept before it is
+// purged when auth.account_retention_days is not configured.
+const DefaultAccountRetention = 30 * 24 * time.Hour
+
+// WalletChallengeTTL is how long a wallet challenge can be answered.
+const WalletChallengeTTL = 5 * time.Minute
+
+// walletChallengePrefix namespaces wallet challenges in Redis; the key is
+// completed with the nonce.
+const walletChallengePrefix = "wallet_challenge:"
+
+// walletChallenge is what is kept in Redis for an issued nonce.
+type walletChallenge struct {
+	ClerkID string `json:"clerkId"`
+	Address string `json:"address"`
+	Message string `json:"message"`
+}
+
+// UserService manages accounts: the caller's own profile and, for admins,
+// the user directory. Roles are owned by Clerk, so role changes are written
+// to the user's Clerk public metadata before they are stored here.
+type UserService struct {
+	server      *server.Server
+	userRepo    *repository.UserRepository
+	projectRepo *repository.ProjectRepository
+	listingRepo *repository.ListingRepository
+}
+
+func NewUserService(s *server.Server, userRepo *repository.UserRepository, projectRepo *repository.ProjectRepository, listingRepo *repository.ListingRepository) *UserService {
+	return &UserService{
+		server:      s,
+		userRepo:    userRepo,
+		projectRepo: projectRepo,
+		listingRepo: listingRepo,
+	}
+}
+
+func (s *UserService) GetMe(ctx echo.Context, clerkID string) (*user.User, error) {
+	u, err := s.userRepo.FindByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+	return u, nil
+}
+
+// UpdateMe links a wallet to the caller's account. The wallet is unverified
+// until the caller answers a wallet challenge for it.
+func (s *UserService) UpdateMe(ctx echo.Context, clerkID string, payload user.UpdateMePayload) (*user.User, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	u, err := s.userRepo.FindByClerkID(reqCtx, clerkID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+
+	if err := s.ensureWalletAvailable(reqCtx, payload.WalletAddress, u); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.userRepo.Update(reqCtx, u.ID.String(), payload.WalletAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+
+	logger.Info().Str("user_id", updated.ID.String()).Msg("profile updated")
+	return updated, nil
+}
+
+// WalletChallenge issues a single-use EIP-4361 message for the caller to sign
+// with the wallet at payload.Address.
+func (s *UserService) WalletChallenge(ctx echo.Context, clerkID string, payload user.WalletChallengePayload) (*user.WalletChallenge, error) {
+	if s.server.Redis == nil {
+		return nil, echo.NewHTTPError(http.StatusServiceUnavailable, "Wallet verification is not available")
+	}
+	if _, err := s.GetMe(ctx, clerkID); err != nil {
+		return nil, err
+	}
+
+	nonce, err := siwe.NewNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	domain, uri := s.siweOrigin(ctx)
+	issuedAt := time.Now()
+	msg := siwe.Message{
+		Domain:         domain,
+		Address:        common.HexToAddress(payload.Address),
+		Statement:      "Link this wallet to your Ledgera account.",
+		URI:            uri,
+		ChainID:        s.server.Config.Blockhain.ChainID,
+		Nonce:          nonce,
+		IssuedAt:       issuedAt,
+		ExpirationTime: issuedAt.Add(WalletChallengeTTL),
+	}
+
+	stored, err := json.Marshal(walletChallenge{
+		ClerkID: clerkID,
+		Address: msg.Address.Hex(),
+		Message: msg.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.server.Redis.Set(ctx.Request().Context(), walletChallengePrefix+nonce, stored, WalletChallengeTTL).Err(); err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to store wallet challenge")
+		return nil, echo.NewHTTPError(http.StatusServiceUnavailable, "Wallet verification is not available")
+	}
+
+	return &user.WalletChallenge{
+		Nonce:     nonce,
+		Message:   msg.String(),
+		ExpiresAt: msg.ExpirationTime,
+	}, nil
+}
+
+// VerifyWallet checks the signature of a challenge issued to the caller and
+// links the signing wallet to their account as verified. A challenge is
+// consumed by the first attempt, whether or not it succeeds.
+func (s *UserService) VerifyWallet(ctx echo.Context, clerkID string, payload user.VerifyWalletPayload) (*user.User, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	if s.server.Redis == nil {
+		return nil, echo.NewHTTPError(http.StatusServiceUnavailable, "Wallet verification is not available")
+	}
+
+	raw, err := s.server.Redis.GetDel(reqCtx, walletChallengePrefix+payload.Nonce).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Challenge is unknown or has expired")
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load wallet challenge")
+		return nil, echo.NewHTTPError(http.StatusServiceUnavailable, "Wallet verification is not available")
+	}
+
+	var challenge walletChallenge
+	if err := json.Unmarshal(raw, &challenge); err != nil {
+		return nil, err
+	}
+	if challenge.ClerkID != clerkID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Challenge is unknown or has expired")
+	}
+
+	if err := siwe.Verify(challenge.Message, payload.Signature, common.HexToAddress(challenge.Address)); err != nil {
+		logger.Warn().Err(err).Str("address", challenge.Address).Msg("wallet signature rejected")
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Signature does not match the wallet")
+	}
+
+	u, err := s.userRepo.VerifyWallet(reqCtx, clerkID, challenge.Address)
+	if err != nil {
+		if errors.Is(err, repository.ErrWalletVerifiedElsewhere) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "Wallet address is already linked to another account")
+		}
+		return nil, err
+	}
+	if u == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+
+	logger.Info().Str("user_id", u.ID.String()).Str("address", challenge.Address).Msg("wallet verified")
+	return u, nil
+}
+
+// List is the admin user directory.
+func (s *UserService) List(ctx echo.Context, adminID string, q user.GetUsersQuery) (*model.PaginatedResponse[user.User], error) {
+	if err := ensureAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	page, limit := *q.Page, *q.Limit
+	items, total, err := s.userRepo.ListPaginated(ctx.Request().Context(), q.Role, q.Search, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := 0
+	if limit > 0 {
+		totalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+
+	return &model.PaginatedResponse[user.User]{
+		Data:       items,
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Update lets an admin change a user's role or wallet. A new role is pushed
+// to Clerk first so the user's session claims agree with the database; if
+// Clerk cannot be updated nothing is changed.
+func (s *UserService) Update(ctx echo.Context, adminID string, payload user.UpdateUserPayload) (*user.User, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	if err := ensureAdmin(ctx, s.userRepo, adminID); err != nil {
+		return nil, err
+	}
+
+	target, err := s.userRepo.FindByID(reqCtx, payload.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+
+	if payload.Role != nil && target.ClerkID == adminID && *payload.Role != user.RoleAdmin {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Admins cannot remove their own admin role")
+	}
+
+	if err := s.ensureWalletAvailable(reqCtx, payload.WalletAddress, target); err != nil {
+		return nil, err
+	}
+
+	if payload.Role != nil && *payload.Role != target.Role {
+		if err := s.pushRole(reqCtx, target.ClerkID, *payload.Role); err != nil {
+			logger.Error().Err(err).Str("clerk_id", target.ClerkID).Msg("failed to update role in clerk")
+			return nil, echo.NewHTTPError(http.StatusBadGateway, "Failed to update role in Clerk")
+		}
+	}
+
+	updated, err := s.userRepo.Update(reqCtx, target.ID.String(), payload.WalletAddress, payload.Role)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+
+	logger.Info().
+		Str("admin_id", adminID).
+		Str("user_id", updated.ID.String()).
+		Str("role", string(updated.Role)).
+		Msg("user updated by admin")
+	return updated, nil
+}
+
+// DeleteMe soft-deletes the caller's account and anonymizes it. It is refused
+// while the account still has projects in review or on the market, or open
+// listings, since buyers and reviewers depend on those. The account is purged
+// for good once the retention period has passed.
+func (s *UserService) DeleteMe(ctx echo.Context, clerkID string) error {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	u, err := s.userRepo.FindByClerkID(reqCtx, clerkID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+
+	live, err := s.projectRepo.CountBySupplierInStatus(reqCtx, clerkID,
+		project.ProjectStatusPending, project.ProjectStatusApproved, project.ProjectStatusDeployed)
+	if err != nil {
+		return err
+	}
+	if live > 0 {
+		return echo.NewHTTPError(http.StatusConflict, "Account has pending, approved or deployed projects and cannot be deleted")
+	}
+
+	if u.WalletAddress != nil {
+		open, err := s.listingRepo.CountActiveBySeller(reqCtx, *u.WalletAddress)
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return echo.NewHTTPError(http.StatusConflict, "Account has open listings and cannot be deleted")
+		}
+	}
+
+	deleted, withdrawn, err := s.userRepo.SoftDelete(reqCtx, clerkID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	}
+	withdrawCertificatePDFs(reqCtx, s.server, withdrawn)
+
+	logger.Info().
+		Str("user_id", u.ID.String()).
+		Dur("retention", s.accountRetention()).
+		Msg("account deleted")
+	return nil
+}
+
+// HandlePurgeDeletedUsersTask is the asynq handler for job.TaskPurgeDeletedUsers.
+func (s *UserService) HandlePurgeDeletedUsersTask(ctx context.Context, t *asynq.Task) error {
+	_, err := s.PurgeDeletedUsers(ctx)
+	return err
+}
+
+// PurgeDeletedUsers hard-deletes every account deleted longer ago than the
+// retention period. The documents of the projects purged with them are
+// deleted from the bucket first; if any of them cannot be, nothing is purged
+// and the next run tries again.
+func (s *UserService) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	cutoff := time.Now().Add(-s.accountRetention())
+
+	keys, err := s.userRepo.ListPurgeableDocumentKeys(ctx, cutoff)
+	if err != nil {
+		s.server.Logger.Error().Err(err).Msg("failed to list documents of deleted users")
+		return 0, err
+	}
+	if len(keys) > 0 && s.server.Uploader == nil {
+		err := errors.New("storage uploader is not available")
+		s.server.Logger.Error().Err(err).Int("documents", len(keys)).Msg("failed to purge deleted users")
+		return 0, err
+	}
+	for _, key := range keys {
+		if err := s.server.Uploader.Delete(ctx, key); err != nil {
+			s.server.Logger.Error().Err(err).Str("key", key).Msg("failed to delete document of deleted user")
+			return 0, err
+		}
+	}
+
+	purged, err := s.userRepo.PurgeDeleted(ctx, cutoff)
+	if err != nil {
+		s.server.Logger.Error().Err(err).Msg("failed to purge deleted users")
+		return 0, err
+	}
+	if purged > 0 {
+		s.server.Logger.Info().Int64("count", purged).Int("documents", len(keys)).Msg("purged deleted users")
+	}
+	return purged, nil
+}
+
+// siweOrigin returns the domain and URI a wallet challenge is issued for: the
+// configured public URL, or the origin of the request.
+func (s *UserService) siweOrigin(ctx echo.Context) (string, string) {
+	if base := strings.TrimRight(s.server.Config.Server.PublicURL, "/"); base != "" {
+		if u, err := url.Parse(base); err == nil && u.Host != "" {
+			return u.Host, base
+		}
+	}
+	req := ctx.Request()
+	return req.Host, ctx.Scheme() + "://" + req.Host
+}
+
+// ensureWalletAvailable rejects linking a wallet that belongs to another
+// account.
+func (s *UserService) ensureWalletAvailable(ctx context.Context, wallet *string, u *user.User) error {
+	if wallet == nil {
+		return nil
+	}
+	owner, err := s.userRepo.FindByWalletAddress(ctx, *wallet)
+	if err != nil {
+		return err
+	}
+	if owner != nil && owner.ID != u.ID {
+		return echo.NewHTTPError(http.StatusConflict, "Wallet address is already linked to another account")
+	}
+	return nil
+}
+
+// pushRole writes role to the Clerk user's public metadata, where the session
+// claims read it from. Clerk merges it into the existing metadata.
+func (s *UserService) pushRole(ctx context.Context, clerkID string, role user.UserRole) error {
+	metadata, err := json.Marshal(map[string]user.UserRole{"role": role})
+	if err != nil {
+		return err
+	}
+	raw := json.RawMessage(metadata)
+
+	_, err = clerkuser.UpdateMetadata(ctx, clerkID, &clerkuser.UpdateMetadataParams{
+		PublicMetadata: &raw,
+	})
+	return err
+}
+
+func (s *UserService) accountRetention() time.Duration {
+	if days := s.server.Config.Auth.AccountRetentionDays; days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	return DefaultAccountRetention
+}
@@ -0,0 +1,288 @@
+package testing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/inventedsarawak/ledgera/internal/blockchain"
+	"github.com/inventedsarawak/ledgera/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// simulatedChainID is the fixed chain ID of go-ethereum's simulated backend.
+const simulatedChainID = 1337
+
+type TestChain struct {
+	Backend  *simulated.Backend
+	Client   *blockchain.Client
+	Registry common.Address
+	// UserKey is a second funded account, e.g. a supplier or buyer wallet.
+	UserKey *ecdsa.PrivateKey
+}
+
+// SetupTestChain starts an in-process simulated chain, funds an admin key,
+// deploys the fixture AssetRegistry and returns a blockchain.Client wired to
+// it. Blocks are sealed continuously so code waiting for receipts progresses.
+func SetupTestChain(t *testing.T) *TestChain {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	admin := crypto.PubkeyToAddress(key.PublicKey)
+	userKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	funds := new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		admin: {Balance: funds},
+		crypto.PubkeyToAddress(userKey.PublicKey): {Balance: funds},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				backend.Commit()
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		_ = backend.Close()
+	})
+
+	cfg := config.BlockchainConfig{
+		RpcUrl:          "simulated://",
+		ChainID:         simulatedChainID,
+		AdminPrivateKey: "0x" + hex.EncodeToString(crypto.FromECDSA(key)),
+	}
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(simulatedChainID))
+	require.NoError(t, err)
+
+	registry, tx, _, err := bind.DeployContract(opts, abi.ABI{}, FixtureRegistryCode(), backend.Client())
+	require.NoError(t, err, "failed to deploy fixture registry")
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer waitCancel()
+	_, err = bind.WaitDeployed(waitCtx, backend.Client(), tx)
+	require.NoError(t, err, "fixture registry was not deployed")
+
+	cfg.FactoryAddress = registry.Hex()
+	client, err := blockchain.NewClientWithBackend(cfg, backend.Client())
+	require.NoError(t, err)
+
+	return &TestChain{
+		Backend:  backend,
+		Client:   client,
+		Registry: registry,
+		UserKey:  userKey,
+	}
+}
+
+// ClientFor returns a client on the same chain that signs with key instead of
+// the admin key.
+func (c *TestChain) ClientFor(t *testing.T, key *ecdsa.PrivateKey) *blockchain.Client {
+	t.Helper()
+
+	cfg := c.Client.Cfg
+	cfg.AdminPrivateKey = hex.EncodeToString(crypto.FromECDSA(key))
+	client, err := blockchain.NewClientWithBackend(cfg, c.Backend.Client())
+	require.NoError(t, err)
+	return client
+}
+
+// The fixture contracts below are hand-assembled stand-ins for
+// contracts/src, built with go-ethereum's core/vm/program. They reproduce the
+// ABI surface and events the backend depends on, not the full Solidity logic,
+// so the chain tests do not need a solc toolchain.
+
+// FixtureRegistryCode returns init code for a contract that, on any call,
+// behaves like AssetRegistry.createAsset(name, symbol): it creates a token,
+// emits AssetCreated(token, name, symbol) and returns the token address.
+func FixtureRegistryCode() []byte {
+	// The token constructor records tx.origin as owner, matching AssetToken
+	// being owned by whoever called createAsset.
+	token := program.New().Op(vm.ORIGIN).Push(1).Op(vm.SSTORE).ReturnViaCodeCopy(fixtureTokenRuntime()).Bytes()
+	assetCreated := crypto.Keccak256Hash([]byte("AssetCreated(address,string,string)"))
+
+	runtime := program.New()
+	// Copy the embedded token init code to memory and CREATE it.
+	runtime.Push(len(token))
+	runtime.Op(vm.PUSH2)
+	offsetPos := runtime.Size()
+	runtime.Append([]byte{0, 0})
+	runtime.Push(0).Op(vm.CODECOPY)
+	runtime.Push(len(token)).Push(0).Push(0).Op(vm.CREATE)
+	// stack: token
+	runtime.Op(vm.DUP1)
+	// The event data, abi.encode(name, symbol), is exactly calldata[4:].
+	runtime.Push(4).Op(vm.CALLDATASIZE, vm.SUB)
+	runtime.Op(vm.DUP1).Push(4).Push(0).Op(vm.CALLDATACOPY)
+	// stack: size, token, token
+	runtime.Push(assetCreated.Bytes()).Op(vm.SWAP1).Push(0).Op(vm.LOG2)
+	// return abi.encode(token)
+	runtime.Push(0).Op(vm.MSTORE)
+	runtime.Return(0, 32)
+
+	code := runtime.Bytes()
+	offset := len(code)
+	code[offsetPos] = byte(offset >> 8)
+	code[offsetPos+1] = byte(offset)
+	code = append(code, token...)
+
+	return program.New().ReturnViaCodeCopy(code).Bytes()
+}
+
+// fixtureTokenRuntime is the runtime code of the tokens created by the
+// fixture registry: an 18-decimal ERC20 subset with an owner-only mint.
+// Storage: slot 0 is totalSupply, slot 1 the owner and balances live at
+// (1<<160 | account). Failing calls revert with the same OpenZeppelin custom
+// errors as AssetToken so error mapping can be exercised.
+func fixtureTokenRuntime() []byte {
+	transferEvent := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	balanceFlag := new(big.Int).Lsh(big.NewInt(1), 160)
+
+	a := newFixtureAsm()
+	// selector := calldata[0:4]
+	a.Push(0).Op(vm.CALLDATALOAD).Push(224).Op(vm.SHR)
+	for _, fn := range []string{"mint(address,uint256)", "transfer(address,uint256)", "balanceOf(address)", "totalSupply()", "decimals()", "owner()"} {
+		a.Op(vm.DUP1).Push(selector(fn)).Op(vm.EQ)
+		a.jumpIf(fn)
+	}
+	a.Push(0).Op(vm.DUP1, vm.REVERT)
+
+	// mint(to, amount): onlyOwner
+	a.label("mint(address,uint256)")
+	a.Push(1).Op(vm.SLOAD, vm.CALLER, vm.EQ)
+	a.jumpIf("mint:authorized")
+	a.Push(selectorWord("OwnableUnauthorizedAccount(address)")).Push(0).Op(vm.MSTORE)
+	a.Op(vm.CALLER).Push(4).Op(vm.MSTORE)
+	a.Push(36).Push(0).Op(vm.REVERT)
+	a.label("mint:authorized")
+	// totalSupply += amount
+	a.Push(36).Op(vm.CALLDATALOAD).Push(0).Op(vm.SLOAD, vm.ADD).Push(0).Op(vm.SSTORE)
+	// balances[to] += amount
+	a.Push(36).Op(vm.CALLDATALOAD)
+	a.Push(4).Op(vm.CALLDATALOAD).Push(balanceFlag).Op(vm.OR)
+	a.Op(vm.DUP1, vm.SLOAD, vm.DUP3, vm.ADD, vm.SWAP1, vm.SSTORE)
+	// emit Transfer(0, to, amount)
+	a.Push(0).Op(vm.MSTORE)
+	a.Push(4).Op(vm.CALLDATALOAD).Push(0).Push(transferEvent.Bytes()).Push(32).Push(0).Op(vm.LOG3)
+	a.Op(vm.STOP)
+
+	// transfer(to, amount)
+	a.label("transfer(address,uint256)")
+	a.Op(vm.CALLER).Push(balanceFlag).Op(vm.OR, vm.SLOAD)
+	a.Push(36).Op(vm.CALLDATALOAD)
+	// stack: amount, balance
+	a.Op(vm.DUP2, vm.DUP2, vm.GT)
+	a.jumpIf("transfer:insufficient")
+	a.Op(vm.SWAP1, vm.SUB)
+	a.Op(vm.CALLER).Push(balanceFlag).Op(vm.OR, vm.SSTORE)
+	a.Push(36).Op(vm.CALLDATALOAD)
+	a.Push(4).Op(vm.CALLDATALOAD).Push(balanceFlag).Op(vm.OR)
+	a.Op(vm.DUP1, vm.SLOAD, vm.DUP3, vm.ADD, vm.SWAP1, vm.SSTORE)
+	// emit Transfer(caller, to, amount)
+	a.Push(0).Op(vm.MSTORE)
+	a.Push(4).Op(vm.CALLDATALOAD, vm.CALLER).Push(transferEvent.Bytes()).Push(32).Push(0).Op(vm.LOG3)
+	a.Push(1).Push(0).Op(vm.MSTORE)
+	a.Return(0, 32)
+	a.label("transfer:insufficient")
+	// revert ERC20InsufficientBalance(caller, balance, amount)
+	a.Push(68).Op(vm.MSTORE).Push(36).Op(vm.MSTORE)
+	a.Push(selectorWord("ERC20InsufficientBalance(address,uint256,uint256)")).Push(0).Op(vm.MSTORE)
+	a.Op(vm.CALLER).Push(4).Op(vm.MSTORE)
+	a.Push(100).Push(0).Op(vm.REVERT)
+
+	a.label("balanceOf(address)")
+	a.Push(4).Op(vm.CALLDATALOAD).Push(balanceFlag).Op(vm.OR, vm.SLOAD)
+	a.returnWord()
+
+	a.label("totalSupply()")
+	a.Push(0).Op(vm.SLOAD)
+	a.returnWord()
+
+	a.label("decimals()")
+	a.Push(18)
+	a.returnWord()
+
+	a.label("owner()")
+	a.Push(1).Op(vm.SLOAD)
+	a.returnWord()
+
+	return a.bytes()
+}
+
+// fixtureAsm adds forward-referencable jump labels to program.Program.
+type fixtureAsm struct {
+	*program.Program
+	labels map[string]int
+	refs   map[int]string
+}
+
+func newFixtureAsm() *fixtureAsm {
+	return &fixtureAsm{Program: program.New(), labels: map[string]int{}, refs: map[int]string{}}
+}
+
+// jumpIf jumps to label when the top of the stack is non-zero.
+func (a *fixtureAsm) jumpIf(label string) {
+	a.Op(vm.PUSH2)
+	a.refs[a.Size()] = label
+	a.Append([]byte{0, 0})
+	a.Op(vm.JUMPI)
+}
+
+func (a *fixtureAsm) label(name string) {
+	a.labels[name] = a.Size()
+	a.Op(vm.JUMPDEST)
+}
+
+// returnWord returns the top of the stack as a single ABI word.
+func (a *fixtureAsm) returnWord() {
+	a.Push(0).Op(vm.MSTORE)
+	a.Return(0, 32)
+}
+
+func (a *fixtureAsm) bytes() []byte {
+	code := a.Bytes()
+	for pos, label := range a.refs {
+		dest, ok := a.labels[label]
+		if !ok {
+			panic("fixture: undefined label " + label)
+		}
+		code[pos] = byte(dest >> 8)
+		code[pos+1] = byte(dest)
+	}
+	return code
+}
+
+func selector(signature string) []byte {
+	return crypto.Keccak256([]byte(signature))[:4]
+}
+
+// selectorWord is a selector left-aligned in a 32 byte word, ready to MSTORE
+// at the start of revert data.
+func selectorWord(signature string) []byte {
+	return common.RightPadBytes(selector(signature), 32)
+}
@@ -32,7 +32,7 @@ func SetupTestDB(t *testing.T) (*TestDB, func()) {
 	dbPassword := "testpassword"
 
 	req := testcontainers.ContainerRequest{
-		Image:        "postgres:15-alpine",
+		Image:        "postgis/postgis:15-3.4-alpine",
 		ExposedPorts: []string{"5432/tcp"},
 		Env: map[string]string{
 			"POSTGRES_DB":       dbName,
@@ -93,7 +93,9 @@ func SetupTestDB(t *testing.T) (*TestDB, func()) {
 			Address: "localhost:6379",
 		},
 		Auth: config.AuthConfig{
-			SecretKey: "test-secret",
+			SecretKey:            "test-secret",
+			WebhookSigningSecret: "whsec_bGVkZ2VyYS10ZXN0LXdlYmhvb2stc2VjcmV0",
+			OrganizationID:       "org_ledgera_test",
 		},
 	}
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/inventedsarawak/ledgera/internal/handler"
+	"github.com/inventedsarawak/ledgera/internal/lib/email"
 	"github.com/inventedsarawak/ledgera/internal/repository"
 	"github.com/inventedsarawak/ledgera/internal/router"
 	"github.com/inventedsarawak/ledgera/internal/server"
@@ -97,6 +98,22 @@ func ProjectRoot(t *testing.T) string {
 	}
 }
 
+// DryRunEmail renders the real email templates but keeps the messages in
+// memory. When srv is given, it replaces srv.Email so services send through it.
+func DryRunEmail(t *testing.T, srv *server.Server) (*email.Client, *email.DryRunSender) {
+	t.Helper()
+
+	sender := &email.DryRunSender{}
+	logger := zerolog.Nop()
+	client := email.NewClientWithSender(sender, email.Options{
+		TemplateDir: filepath.Join(ProjectRoot(t), email.DefaultTemplateDir),
+	}, &logger)
+	if srv != nil {
+		srv.Email = client
+	}
+	return client, sender
+}
+
 // Ptr returns a pointer to the given value
 // Useful for creating pointers to values for optional fields
 func Ptr[T any](v T) *T {
@@ -0,0 +1,76 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/inventedsarawak/ledgera/internal/lib/upload"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	testBucketName = "ledgera-test"
+	testBucketUser = "testminio"
+	testBucketPass = "testminiopassword"
+)
+
+// SetupTestBucket starts a MinIO container standing in for the R2 bucket and
+// returns an upload client for it. The container is terminated when the test
+// ends.
+func SetupTestBucket(t *testing.T) *upload.Client {
+	t.Helper()
+
+	ctx := context.Background()
+	req := testcontainers.ContainerRequest{
+		Image:        "minio/minio:latest",
+		ExposedPorts: []string{"9000/tcp"},
+		Cmd:          []string{"server", "/data"},
+		Env: map[string]string{
+			"MINIO_ROOT_USER":     testBucketUser,
+			"MINIO_ROOT_PASSWORD": testBucketPass,
+		},
+		WaitingFor: wait.ForHTTP("/minio/health/live").WithPort("9000/tcp").WithStartupTimeout(30 * time.Second),
+	}
+
+	minioContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	require.NoError(t, err, "failed to start minio container")
+
+	t.Cleanup(func() {
+		if err := minioContainer.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate container: %v", err)
+		}
+	})
+
+	host, err := minioContainer.Host(ctx)
+	require.NoError(t, err, "failed to get container host")
+
+	mappedPort, err := minioContainer.MappedPort(ctx, "9000")
+	require.NoError(t, err, "failed to get mapped port")
+	endpoint := fmt.Sprintf("http://%s:%d", host, mappedPort.Int())
+
+	client, err := upload.NewClient(ctx, endpoint, testBucketUser, testBucketPass, testBucketName, endpoint+"/"+testBucketName)
+	require.NoError(t, err, "failed to create upload client")
+
+	// The bucket is created through a plain client as the upload client only
+	// handles objects.
+	admin := s3.New(s3.Options{
+		BaseEndpoint: aws.String(endpoint),
+		Region:       "auto",
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider(testBucketUser, testBucketPass, ""),
+	})
+	_, err = admin.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(testBucketName)})
+	require.NoError(t, err, "failed to create bucket")
+
+	return client
+}
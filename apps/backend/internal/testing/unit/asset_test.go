@@ -0,0 +1,277 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hibiken/asynq"
+	"github.com/inventedsarawak/ledgera/internal/lib/job"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/token"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetDeployAndMint(t *testing.T) {
+	testDB, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	chain := itesting.SetupTestChain(t)
+	srv.Blockchain = chain.Client
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// Mock admin (bypass auth) and a supplier with a wallet
+	{
+		jsonBody := itesting.MustMarshalJSON(t, user.SyncUserPayload{Email: "admin@example.com"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sync-user", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	repos := repository.NewRepositories(srv)
+	wallet := crypto.PubkeyToAddress(chain.UserKey.PublicKey)
+	_, _, err := repos.User.UpsertUser(ctx, "user_asset_supplier", "supplier@example.com", user.RoleSupplier)
+	require.NoError(t, err)
+	_, err = testDB.Pool.Exec(ctx, `UPDATE users SET wallet_address = $1, wallet_verified_at = NOW() WHERE clerk_id = 'user_asset_supplier'`, wallet.Hex())
+	require.NoError(t, err)
+
+	p, err := repos.Project.Create(ctx, project.Project{
+		SupplierID:   "user_asset_supplier",
+		Title:        "Mangrove Restoration",
+		Description:  "Restoring mangrove ecosystems for carbon sequestration.",
+		ImageURL:     "https://example.com/mangrove.jpg",
+		CarbonAmount: 1000,
+		Status:       project.ProjectStatusApproved,
+	})
+	require.NoError(t, err)
+
+	post := func(path string, body any) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(itesting.MustMarshalJSON(t, body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	// DEPLOY (no job server in tests, so it completes inline)
+	rec := post("/api/v1/projects/"+p.ID.String()+"/deploy", map[string]string{})
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	deployed, err := repos.Project.FindByID(ctx, p.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, project.ProjectStatusDeployed, deployed.Status)
+	require.NotNil(t, deployed.ContractAddress)
+	require.NotNil(t, deployed.TokenSymbol)
+	assert.Equal(t, "MR"+strings.ToUpper(deployed.ID.String()[:4]), *deployed.TokenSymbol)
+
+	// MINT part of the verified amount
+	rec = post("/api/v1/projects/"+p.ID.String()+"/mint", map[string]float64{"tonnes": 600})
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var first token.Issuance
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &first))
+	assert.Equal(t, token.IssuanceStatusConfirmed, first.Status)
+	assert.Equal(t, wallet.Hex(), first.RecipientAddress)
+	assert.Equal(t, uint8(18), first.Decimals)
+	require.NotNil(t, first.TxHash)
+
+	// MINT more than what is left is refused
+	rec = post("/api/v1/projects/"+p.ID.String()+"/mint", map[string]float64{"tonnes": 500})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// MINT without tonnes issues the remainder
+	rec = post("/api/v1/projects/"+p.ID.String()+"/mint", map[string]float64{})
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	var second token.Issuance
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &second))
+	assert.Equal(t, 400.0, second.Tonnes)
+
+	// Fully issued now
+	rec = post("/api/v1/projects/"+p.ID.String()+"/mint", map[string]float64{})
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	tokenContract, err := chain.Client.Token(common.HexToAddress(*deployed.ContractAddress))
+	require.NoError(t, err)
+	balance, err := tokenContract.BalanceOf(ctx, wallet)
+	require.NoError(t, err)
+	want, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	assert.Equal(t, want.String(), balance.String())
+}
+
+func TestAssetDeployReverted(t *testing.T) {
+	testDB, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	chain := itesting.SetupTestChain(t)
+	srv.Blockchain = chain.Client
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	{
+		jsonBody := itesting.MustMarshalJSON(t, user.SyncUserPayload{Email: "admin@example.com"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sync-user", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	repos := repository.NewRepositories(srv)
+	p, err := repos.Project.Create(ctx, project.Project{
+		SupplierID:   "user_test_mock_123",
+		Title:        "Peat Swamp Rewetting",
+		Description:  "Rewetting drained peat swamp forest.",
+		ImageURL:     "https://example.com/peat.jpg",
+		CarbonAmount: 500,
+		Status:       project.ProjectStatusApproved,
+	})
+	require.NoError(t, err)
+
+	tx := sendRevertingTx(t, ctx, chain)
+	_, err = testDB.Pool.Exec(ctx, `UPDATE projects SET deploy_tx_hash = $1 WHERE id = $2`, tx.Hash().Hex(), p.ID)
+	require.NoError(t, err)
+
+	deploy := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+p.ID.String()+"/deploy", bytes.NewReader([]byte(`{}`)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "deploy", rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	// Resuming the reverted deployment fails and forgets its transaction
+	rec := deploy()
+	assert.NotEqual(t, http.StatusAccepted, rec.Code)
+
+	stuck, err := repos.Project.FindByID(ctx, p.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, project.ProjectStatusApproved, stuck.Status)
+	assert.Nil(t, stuck.DeployTxHash)
+
+	// so the next request deploys afresh
+	rec = deploy()
+	require.Equal(t, http.StatusAccepted, rec.Code)
+
+	deployed, err := repos.Project.FindByID(ctx, p.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, project.ProjectStatusDeployed, deployed.Status)
+	require.NotNil(t, deployed.DeployTxHash)
+	assert.NotEqual(t, tx.Hash().Hex(), *deployed.DeployTxHash)
+}
+
+func TestAssetDeployRetriedThroughQueue(t *testing.T) {
+	testDB, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	chain := itesting.SetupTestChain(t)
+	srv.Blockchain = chain.Client
+
+	// A real job server, so failed deploys end up archived as they do in
+	// production
+	cfg := *srv.Config
+	cfg.Redis.Address = itesting.SetupTestRedis(t).Options().Addr
+	srv.Job = job.NewJobService(srv.Logger, &cfg)
+	repos := repository.NewRepositories(srv)
+	assets := service.NewAssetService(srv, repos.Project, repos.User, repos.Token)
+	srv.Job.Register(job.TaskDeployProject, assets.HandleDeployProjectTask)
+	require.NoError(t, srv.Job.Start())
+	defer srv.Job.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	{
+		jsonBody := itesting.MustMarshalJSON(t, user.SyncUserPayload{Email: "admin@example.com"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sync-user", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	p, err := repos.Project.Create(ctx, project.Project{
+		SupplierID:   "user_test_mock_123",
+		Title:        "Peat Swamp Rewetting",
+		Description:  "Rewetting drained peat swamp forest.",
+		ImageURL:     "https://example.com/peat.jpg",
+		CarbonAmount: 500,
+		Status:       project.ProjectStatusApproved,
+	})
+	require.NoError(t, err)
+
+	tx := sendRevertingTx(t, ctx, chain)
+	_, err = testDB.Pool.Exec(ctx, `UPDATE projects SET deploy_tx_hash = $1 WHERE id = $2`, tx.Hash().Hex(), p.ID)
+	require.NoError(t, err)
+
+	deploy := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+p.ID.String()+"/deploy", bytes.NewReader([]byte(`{}`)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "deploy", rec.Code, rec.Body.Bytes())
+		return rec.Code
+	}
+
+	// The reverted deployment fails for good and its task is archived
+	require.Equal(t, http.StatusAccepted, deploy())
+	require.Eventually(t, func() bool {
+		info, err := srv.Job.Inspector.GetTaskInfo(job.DeployProjectQueue, job.DeployProjectTaskID(p.ID.String()))
+		return err == nil && info.State == asynq.TaskStateArchived
+	}, 30*time.Second, 200*time.Millisecond)
+
+	// Asking again replaces the archived task and deploys afresh
+	require.Equal(t, http.StatusAccepted, deploy())
+	require.Eventually(t, func() bool {
+		deployed, err := repos.Project.FindByID(ctx, p.ID.String())
+		return err == nil && deployed.Status == project.ProjectStatusDeployed
+	}, 30*time.Second, 200*time.Millisecond)
+}
+
+// sendRevertingTx sends a contract creation whose init code is REVERT(0, 0),
+// standing in for a createAsset that reverted.
+func sendRevertingTx(t *testing.T, ctx context.Context, chain *itesting.TestChain) *types.Transaction {
+	t.Helper()
+
+	eth := chain.Backend.Client()
+	from := crypto.PubkeyToAddress(chain.UserKey.PublicKey)
+	nonce, err := eth.PendingNonceAt(ctx, from)
+	require.NoError(t, err)
+	gasPrice, err := eth.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	tx, err := types.SignTx(
+		types.NewContractCreation(nonce, big.NewInt(0), 100000, gasPrice, []byte{0x60, 0x00, 0x60, 0x00, 0xfd}),
+		types.LatestSignerForChainID(big.NewInt(1337)),
+		chain.UserKey,
+	)
+	require.NoError(t, err)
+	require.NoError(t, eth.SendTransaction(ctx, tx))
+	return tx
+}
@@ -21,8 +21,9 @@ func logAuthResp(t *testing.T, label string, code int, body []byte) {
 }
 
 func TestAuth(t *testing.T) {
-	_, _, e, cleanup := itesting.SetupTest(t)
+	_, srv, e, cleanup := itesting.SetupTest(t)
 	defer cleanup()
+	_, sender := itesting.DryRunEmail(t, srv)
 
 	t.Run("SyncUser", func(t *testing.T) {
 		t.Log("Starting SyncUser test")
@@ -47,4 +48,23 @@ func TestAuth(t *testing.T) {
 		assert.Equal(t, "test@example.com", responseUser.Email)
 		assert.Equal(t, "user_test_mock_123", responseUser.ClerkID) // Default mock ID
 	})
+
+	t.Run("WelcomeEmailSentOnce", func(t *testing.T) {
+		// The first sync above inserted the user; syncing again must not
+		// welcome them a second time.
+		jsonBody := itesting.MustMarshalJSON(t, user.SyncUserPayload{Email: "test@example.com"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sync-user", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		messages := sender.Messages()
+		if assert.Len(t, messages, 1) {
+			assert.Equal(t, "test@example.com", messages[0].To)
+			assert.Equal(t, "Welcome to Ledgera!", messages[0].Subject)
+		}
+	})
 }
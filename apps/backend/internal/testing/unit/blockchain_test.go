@@ -0,0 +1,195 @@
+package unit
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/inventedsarawak/ledgera/internal/blockchain"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockchainCreateAsset(t *testing.T) {
+	chain := itesting.SetupTestChain(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	registry, err := chain.Client.Registry()
+	require.NoError(t, err)
+
+	t.Run("CreateAsset", func(t *testing.T) {
+		created, err := registry.CreateAsset(ctx, "Mangrove Restoration", "MR3F2A")
+		require.NoError(t, err)
+
+		assert.NotEqual(t, common.Address{}, created.AssetAddress)
+		assert.Equal(t, "Mangrove Restoration", created.Name)
+		assert.Equal(t, "MR3F2A", created.Symbol)
+		assert.NotEqual(t, common.Hash{}, created.TxHash)
+
+		code, err := chain.Backend.Client().CodeAt(ctx, created.AssetAddress, nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, code, "token contract should be deployed")
+	})
+
+	t.Run("WaitAssetCreated resumes from a tx hash", func(t *testing.T) {
+		txHash, err := registry.SendCreateAsset(ctx, "Forest Conservation", "FC0001")
+		require.NoError(t, err)
+
+		created, err := registry.WaitAssetCreated(ctx, txHash)
+		require.NoError(t, err)
+		assert.Equal(t, txHash, created.TxHash)
+		assert.Equal(t, "FC0001", created.Symbol)
+	})
+}
+
+func TestBlockchainToken(t *testing.T) {
+	chain := itesting.SetupTestChain(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	registry, err := chain.Client.Registry()
+	require.NoError(t, err)
+	created, err := registry.CreateAsset(ctx, "Peatland Rewetting", "PR0001")
+	require.NoError(t, err)
+
+	token, err := chain.Client.Token(created.AssetAddress)
+	require.NoError(t, err)
+
+	holder := crypto.PubkeyToAddress(chain.UserKey.PublicKey)
+	admin := chain.Client.Address()
+
+	t.Run("Mint", func(t *testing.T) {
+		_, err := token.Mint(ctx, admin, big.NewInt(1000))
+		require.NoError(t, err)
+
+		balance, err := token.BalanceOf(ctx, admin)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), balance.Int64())
+
+		supply, err := token.TotalSupply(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), supply.Int64())
+
+		decimals, err := token.Decimals(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint8(18), decimals)
+	})
+
+	t.Run("Transfer", func(t *testing.T) {
+		_, err := token.Transfer(ctx, holder, big.NewInt(400))
+		require.NoError(t, err)
+
+		balance, err := token.BalanceOf(ctx, holder)
+		require.NoError(t, err)
+		assert.Equal(t, int64(400), balance.Int64())
+
+		balance, err = token.BalanceOf(ctx, admin)
+		require.NoError(t, err)
+		assert.Equal(t, int64(600), balance.Int64())
+	})
+
+	t.Run("Transfer more than balance", func(t *testing.T) {
+		_, err := token.Transfer(ctx, holder, big.NewInt(10_000))
+		assert.ErrorIs(t, err, blockchain.ErrInsufficientBalance)
+	})
+
+	t.Run("Mint by non-owner", func(t *testing.T) {
+		stranger := chain.ClientFor(t, chain.UserKey)
+		strangerToken, err := stranger.Token(created.AssetAddress)
+		require.NoError(t, err)
+
+		_, err = strangerToken.Mint(ctx, holder, big.NewInt(1))
+		assert.ErrorIs(t, err, blockchain.ErrNotOwner)
+	})
+
+	t.Run("Invalid arguments", func(t *testing.T) {
+		_, err := token.Mint(ctx, common.Address{}, big.NewInt(1))
+		assert.ErrorIs(t, err, blockchain.ErrInvalidAddress)
+
+		_, err = token.Transfer(ctx, holder, big.NewInt(0))
+		assert.ErrorIs(t, err, blockchain.ErrInvalidAmount)
+	})
+
+	t.Run("No contract", func(t *testing.T) {
+		missing, err := chain.Client.Token(common.HexToAddress("0x000000000000000000000000000000000000dEaD"))
+		require.NoError(t, err)
+
+		_, err = missing.TotalSupply(ctx)
+		assert.ErrorIs(t, err, blockchain.ErrNoContract)
+	})
+}
+
+func TestBlockchainSignText(t *testing.T) {
+	chain := itesting.SetupTestChain(t)
+
+	message := []byte("0x3b9f6c2a1d8e4f7a0c5b2e9d6a3f1c8b7e4d2a9f6c3b0e7d4a1f8c5b2e9d6a3f")
+	sig, err := chain.Client.SignText(message)
+	require.NoError(t, err)
+	require.Len(t, sig, crypto.SignatureLength)
+	assert.Contains(t, []byte{27, 28}, sig[crypto.RecoveryIDOffset])
+
+	sig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(accounts.TextHash(message), sig)
+	require.NoError(t, err)
+	assert.Equal(t, chain.Client.Address(), crypto.PubkeyToAddress(*pub))
+}
+
+func TestToBaseUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		decimals uint8
+		want     string
+		wantErr  bool
+	}{
+		{name: "whole tonnes", amount: "1000", decimals: 18, want: "1000000000000000000000"},
+		{name: "fractional tonnes", amount: "12.50", decimals: 2, want: "1250"},
+		{name: "zero decimals", amount: "42", decimals: 0, want: "42"},
+		{name: "too precise", amount: "0.125", decimals: 2, wantErr: true},
+		{name: "zero", amount: "0", decimals: 18, wantErr: true},
+		{name: "negative", amount: "-1", decimals: 18, wantErr: true},
+		{name: "not a number", amount: "ten", decimals: 18, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := blockchain.ToBaseUnits(tt.amount, tt.decimals)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, blockchain.ErrInvalidAmount)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestFromBaseUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		decimals uint8
+		want     string
+	}{
+		{name: "whole tonnes", amount: "1000000000000000000000", decimals: 18, want: "1000"},
+		{name: "fractional tonnes", amount: "12500000000000000000", decimals: 18, want: "12.5"},
+		{name: "below one", amount: "5", decimals: 2, want: "0.05"},
+		{name: "zero decimals", amount: "42", decimals: 0, want: "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, ok := new(big.Int).SetString(tt.amount, 10)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, blockchain.FromBaseUnits(amount, tt.decimals))
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/certificate"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/token"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetirement(t *testing.T) {
+	testDB, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	chain := itesting.SetupTestChain(t)
+	chain.Client.Cfg.ReorgDepth = 1
+	srv.Blockchain = chain.Client
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	request := func(method, path string, body any) *httptest.ResponseRecorder {
+		var reader *bytes.Reader
+		if body != nil {
+			reader = bytes.NewReader(itesting.MustMarshalJSON(t, body))
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, method+" "+path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	holder := crypto.PubkeyToAddress(chain.UserKey.PublicKey)
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	tonnes := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), unit) }
+
+	rec := request(http.MethodPost, "/api/v1/auth/sync-user", user.SyncUserPayload{Email: "holder@example.com"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, err := testDB.Pool.Exec(ctx, `UPDATE users SET wallet_address = $1, wallet_verified_at = NOW() WHERE clerk_id = 'user_test_mock_123'`, holder.Hex())
+	require.NoError(t, err)
+
+	repos := repository.NewRepositories(srv)
+	indexer := service.NewIndexerService(srv, repos.Token, repos.Project)
+
+	_, _, err = repos.User.UpsertUser(ctx, "user_retire_supplier", "supplier@example.com", user.RoleSupplier)
+	require.NoError(t, err)
+	p, err := repos.Project.Create(ctx, project.Project{
+		SupplierID:   "user_retire_supplier",
+		Title:        "Rainforest Protection",
+		Description:  "Avoided deforestation in lowland rainforest.",
+		ImageURL:     "https://example.com/rainforest.jpg",
+		CarbonAmount: 1000,
+		Status:       project.ProjectStatusApproved,
+	})
+	require.NoError(t, err)
+
+	registry, err := chain.Client.Registry()
+	require.NoError(t, err)
+	created, err := registry.CreateAsset(ctx, p.Title, "RP0001")
+	require.NoError(t, err)
+	_, err = repos.Project.MarkDeployed(ctx, p.ID.String(), created.AssetAddress.Hex(), "RP0001", created.TxHash.Hex())
+	require.NoError(t, err)
+
+	adminToken, err := chain.Client.Token(created.AssetAddress)
+	require.NoError(t, err)
+	holderToken, err := chain.ClientFor(t, chain.UserKey).Token(created.AssetAddress)
+	require.NoError(t, err)
+
+	minted, err := adminToken.Mint(ctx, holder, tonnes(20))
+	require.NoError(t, err)
+	waitForBlock(t, ctx, chain, minted.BlockNumber.Uint64()+chain.Client.Cfg.ReorgDepth)
+	_, err = indexer.Sync(ctx)
+	require.NoError(t, err)
+
+	balance := func() string {
+		h, err := repos.Token.FindHolding(ctx, created.AssetAddress.Hex(), holder.Hex())
+		require.NoError(t, err)
+		require.NotNil(t, h)
+		return h.Balance
+	}
+	require.Equal(t, tonnes(20).String(), balance())
+
+	retirePath := "/api/v1/projects/" + p.ID.String() + "/retire"
+
+	t.Run("Transfers to a regular address are not retirements", func(t *testing.T) {
+		sent, err := holderToken.Transfer(ctx, chain.Client.Address(), tonnes(1))
+		require.NoError(t, err)
+
+		rec := request(http.MethodPost, retirePath, map[string]any{"txHash": sent.TxHash.Hex()})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	var locked common.Hash
+	t.Run("Retire records a certificate and deducts the holding", func(t *testing.T) {
+		receipt, err := holderToken.Transfer(ctx, common.HexToAddress(token.RetirementAddress), tonnes(5))
+		require.NoError(t, err)
+		locked = receipt.TxHash
+
+		rec := request(http.MethodPost, retirePath, map[string]any{
+			"txHash":           locked.Hex(),
+			"beneficiaryName":  "Acme Sdn Bhd",
+			"retirementReason": "FY2026 scope 1 offset",
+		})
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var cert certificate.Certificate
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cert))
+		assert.Equal(t, 5.0, cert.AmountRetired)
+		assert.Equal(t, "Acme Sdn Bhd", cert.BeneficiaryName)
+		assert.Equal(t, holder.Hex(), cert.WalletAddress)
+		assert.Equal(t, locked.Hex(), cert.TxHash)
+		// No storage in tests, so the PDF is never uploaded
+		assert.Nil(t, cert.PDFURL)
+
+		// 20 minted, 1 sent away (not yet indexed), 5 retired
+		assert.Equal(t, tonnes(15).String(), balance())
+
+		rec = request(http.MethodPost, retirePath, map[string]any{"txHash": locked.Hex()})
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("Indexing the retirement does not count it twice", func(t *testing.T) {
+		head, err := chain.Client.BlockNumber(ctx)
+		require.NoError(t, err)
+		waitForBlock(t, ctx, chain, head+chain.Client.Cfg.ReorgDepth)
+		_, err = indexer.Sync(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, tonnes(14).String(), balance())
+
+		sink, err := repos.Token.FindHolding(ctx, created.AssetAddress.Hex(), token.RetirementAddress)
+		require.NoError(t, err)
+		assert.Nil(t, sink)
+	})
+
+	t.Run("Public verification", func(t *testing.T) {
+		verify := func(path string) *httptest.ResponseRecorder {
+			// No X-Test-Auth: these routes are public
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			logResp(t, "GET "+path, rec.Code, rec.Body.Bytes())
+			return rec
+		}
+
+		cert, err := repos.Certificate.FindByTxHash(ctx, locked.Hex())
+		require.NoError(t, err)
+		require.NotNil(t, cert)
+
+		rec := verify("/api/v1/public/certificates/" + cert.ID.String())
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var v certificate.Verification
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &v))
+		assert.Equal(t, 5.0, v.AmountRetired)
+		assert.Equal(t, p.Title, v.Project.Title)
+		assert.Equal(t, certificate.ProofStatusConfirmed, v.Proof.Status)
+		assert.GreaterOrEqual(t, v.Proof.Confirmations, uint64(1))
+		assert.NotContains(t, rec.Body.String(), "user_test_mock_123")
+
+		// The signature recovers to the platform key over the content hash
+		assert.Equal(t, certificate.ContentHash(*cert), v.ContentHash)
+		sig, err := hexutil.Decode(v.Signature)
+		require.NoError(t, err)
+		sig[crypto.RecoveryIDOffset] -= 27
+		pub, err := crypto.SigToPub(accounts.TextHash([]byte(v.ContentHash)), sig)
+		require.NoError(t, err)
+		assert.Equal(t, chain.Client.Address(), crypto.PubkeyToAddress(*pub))
+		assert.Equal(t, chain.Client.Address().Hex(), v.Signer)
+
+		// Any edit to the printed details changes the hash
+		tampered := *cert
+		tampered.AmountRetired = 50
+		assert.NotEqual(t, v.ContentHash, certificate.ContentHash(tampered))
+
+		rec = verify("/api/v1/public/certificates/tx/" + locked.Hex())
+		require.Equal(t, http.StatusOK, rec.Code)
+		var byTx certificate.Verification
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &byTx))
+		assert.Equal(t, cert.ID, byTx.ID)
+
+		rec = verify("/api/v1/public/certificates/" + uuid.NewString())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("List my certificates", func(t *testing.T) {
+		rec := request(http.MethodGet, "/api/v1/certificates", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var page model.PaginatedResponse[certificate.Certificate]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Equal(t, 1, page.Total)
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, locked.Hex(), page.Data[0].TxHash)
+	})
+
+	t.Run("Without a beneficiary the wallet is named, not the email", func(t *testing.T) {
+		receipt, err := holderToken.Transfer(ctx, common.HexToAddress(token.RetirementAddress), tonnes(1))
+		require.NoError(t, err)
+
+		rec := request(http.MethodPost, retirePath, map[string]any{"txHash": receipt.TxHash.Hex()})
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "holder@example.com")
+
+		var cert certificate.Certificate
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cert))
+		assert.Equal(t, holder.Hex(), cert.BeneficiaryName)
+	})
+
+	t.Run("Only deployed projects can be retired against", func(t *testing.T) {
+		draft, err := repos.Project.Create(ctx, project.Project{
+			SupplierID:   "user_retire_supplier",
+			Title:        "Borrowed Token",
+			Description:  "A draft pointing at someone else's token.",
+			ImageURL:     "https://example.com/borrowed.jpg",
+			CarbonAmount: 10,
+			Status:       project.ProjectStatusDraft,
+		})
+		require.NoError(t, err)
+		_, err = testDB.Pool.Exec(ctx, `UPDATE projects SET contract_address = $1 WHERE id = $2`, created.AssetAddress.Hex(), draft.ID)
+		require.NoError(t, err)
+
+		receipt, err := holderToken.Transfer(ctx, common.HexToAddress(token.RetirementAddress), tonnes(1))
+		require.NoError(t, err)
+
+		rec := request(http.MethodPost, "/api/v1/projects/"+draft.ID.String()+"/retire", map[string]any{"txHash": receipt.TxHash.Hex()})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("An unverified wallet cannot retire", func(t *testing.T) {
+		// Linking a wallet is not proof of owning it
+		_, err := testDB.Pool.Exec(ctx, `UPDATE users SET wallet_verified_at = NULL WHERE clerk_id = 'user_test_mock_123'`)
+		require.NoError(t, err)
+
+		receipt, err := holderToken.Transfer(ctx, common.HexToAddress(token.RetirementAddress), tonnes(1))
+		require.NoError(t, err)
+
+		rec := request(http.MethodPost, retirePath, map[string]any{"txHash": receipt.TxHash.Hex()})
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -0,0 +1,194 @@
+package unit
+
+import (
+	"bufio"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/inventedsarawak/ledgera/internal/config"
+	"github.com/inventedsarawak/ledgera/internal/lib/email"
+	"github.com/inventedsarawak/ledgera/internal/lib/job"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailPreviewsRender(t *testing.T) {
+	client, sender := itesting.DryRunEmail(t, nil)
+
+	for name, data := range email.PreviewData {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, client.SendEmail("preview@example.com", "Preview", email.Template(name), data))
+
+			messages := sender.Messages()
+			msg := messages[len(messages)-1]
+			assert.Equal(t, "preview@example.com", msg.To)
+			for key, value := range data {
+				if key == "Feedback" || key == "VerifyURL" {
+					continue // rendered with escaping
+				}
+				assert.Contains(t, msg.HTML, value, key)
+			}
+		})
+	}
+}
+
+func TestEmailLifecycleMessages(t *testing.T) {
+	client, sender := itesting.DryRunEmail(t, nil)
+
+	require.NoError(t, client.SendProjectRejectedEmail("supplier@example.com", "Mangrove", "Area mismatch: too small", "[area] ADMIN: see annex"))
+	require.NoError(t, client.SendProjectDeployedEmail("supplier@example.com", "Mangrove", "MG0001", "0x5FbDB2315678afecb367f032d93F642f64180aa3"))
+
+	messages := sender.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, `Your project "Mangrove" needs changes`, messages[0].Subject)
+	assert.Contains(t, messages[0].HTML, "Area mismatch: too small")
+	assert.Contains(t, messages[0].HTML, "[area] ADMIN: see annex")
+	assert.Contains(t, messages[1].HTML, "0x5FbDB2315678afecb367f032d93F642f64180aa3")
+}
+
+func TestEmailWelcomeByRole(t *testing.T) {
+	client, sender := itesting.DryRunEmail(t, nil)
+
+	cases := map[string]string{
+		"SUPPLIER": "/dashboard/projects/new",
+		"BUYER":    "/marketplace",
+		"ADMIN":    "Get Started",
+	}
+	for role, want := range cases {
+		t.Run(role, func(t *testing.T) {
+			task, err := job.NewWelcomeEmailTask("user_"+role, job.WelcomeEmailPayload{To: "new@example.com", Role: role})
+			require.NoError(t, err)
+			require.NoError(t, job.SendEmailTask(client, task))
+
+			messages := sender.Messages()
+			msg := messages[len(messages)-1]
+			assert.Equal(t, "Welcome to Ledgera!", msg.Subject)
+			assert.Contains(t, msg.HTML, want)
+			assert.Contains(t, msg.HTML, "Hi there,")
+		})
+	}
+}
+
+func TestEmailFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	sender, err := email.NewSender(config.IntegrationConfig{EmailTransport: "file", EmailOutboxDir: dir})
+	require.NoError(t, err)
+
+	logger := zerolog.Nop()
+	client := email.NewClientWithSender(sender, email.Options{
+		FromName:    "Ledgera Registry",
+		FromAddress: "registry@ledgera.example",
+		ReplyTo:     "support@ledgera.example",
+		TemplateDir: filepath.Join(itesting.ProjectRoot(t), email.DefaultTemplateDir),
+	}, &logger)
+
+	require.NoError(t, client.SendProjectApprovedEmail("supplier@example.com", "Mangrove\r\nBcc: victim@example.com"))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), "supplier_example.com.eml"))
+
+	raw, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+
+	msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(string(raw)))).ReadMIMEHeader()
+	require.NoError(t, err)
+	assert.Equal(t, `"Ledgera Registry" <registry@ledgera.example>`, msg.Get("From"))
+	assert.Equal(t, "support@ledgera.example", msg.Get("Reply-To"))
+	assert.Equal(t, "supplier@example.com", msg.Get("To"))
+	assert.Empty(t, msg.Get("Bcc"), "user text must not inject headers")
+	assert.Contains(t, string(raw), "has been approved")
+}
+
+func TestEmailSMTPSender(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan smtpDelivery, 1)
+	go serveOneSMTP(ln, received)
+
+	addr := ln.Addr().(*net.TCPAddr)
+	sender, err := email.NewSender(config.IntegrationConfig{EmailTransport: "smtp", SMTPHost: "127.0.0.1", SMTPPort: addr.Port})
+	require.NoError(t, err)
+
+	err = sender.Send(email.Message{
+		From:    "Ledgera <noreply@ledgera.example>",
+		To:      "buyer@example.com",
+		Subject: "Certificate ready",
+		HTML:    "<p>Hello</p>",
+	})
+	require.NoError(t, err)
+
+	delivery := <-received
+	assert.Equal(t, "<noreply@ledgera.example>", delivery.from)
+	assert.Equal(t, []string{"<buyer@example.com>"}, delivery.to)
+	assert.Contains(t, delivery.data, "Subject: Certificate ready")
+	assert.Contains(t, delivery.data, "<p>Hello</p>")
+}
+
+func TestEmailNewSenderRejectsUnknownTransport(t *testing.T) {
+	_, err := email.NewSender(config.IntegrationConfig{EmailTransport: "pigeon"})
+	assert.Error(t, err)
+
+	sender, err := email.NewSender(config.IntegrationConfig{ResendAPIKey: "re_test"})
+	require.NoError(t, err)
+	assert.IsType(t, &email.ResendSender{}, sender)
+}
+
+type smtpDelivery struct {
+	from string
+	to   []string
+	data string
+}
+
+// serveOneSMTP speaks just enough SMTP to accept a single message.
+func serveOneSMTP(ln net.Listener, received chan<- smtpDelivery) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	var d smtpDelivery
+	_ = tp.PrintfLine("220 localhost ESMTP test")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250 localhost")
+		case "MAIL":
+			d.from = strings.TrimPrefix(arg, "FROM:")
+			_ = tp.PrintfLine("250 OK")
+		case "RCPT":
+			d.to = append(d.to, strings.TrimPrefix(arg, "TO:"))
+			_ = tp.PrintfLine("250 OK")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			d.data = string(data)
+			_ = tp.PrintfLine("250 OK")
+			received <- d
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("250 OK")
+		}
+	}
+}
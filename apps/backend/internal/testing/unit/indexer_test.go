@@ -0,0 +1,137 @@
+package unit
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexerSync(t *testing.T) {
+	_, srv, _, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	chain := itesting.SetupTestChain(t)
+	chain.Client.Cfg.ReorgDepth = 1
+	srv.Blockchain = chain.Client
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	repos := repository.NewRepositories(srv)
+	indexer := service.NewIndexerService(srv, repos.Token, repos.Project)
+
+	_, _, err := repos.User.UpsertUser(ctx, "user_indexer_supplier", "supplier@example.com", user.RoleSupplier)
+	require.NoError(t, err)
+	p, err := repos.Project.Create(ctx, project.Project{
+		SupplierID:   "user_indexer_supplier",
+		Title:        "Peatland Rewetting",
+		Description:  "Rewetting drained peat swamp forest.",
+		ImageURL:     "https://example.com/peat.jpg",
+		CarbonAmount: 1000,
+		Status:       project.ProjectStatusApproved,
+	})
+	require.NoError(t, err)
+
+	// Simulate a deploy job that submitted createAsset but died before
+	// recording the result: the indexer has to link the token on its own.
+	registry, err := chain.Client.Registry()
+	require.NoError(t, err)
+	txHash, err := registry.SendCreateAsset(ctx, p.Title, "PR0001")
+	require.NoError(t, err)
+	stored, err := repos.Project.SetDeployTxHash(ctx, p.ID.String(), txHash.Hex())
+	require.NoError(t, err)
+	require.True(t, stored)
+	created, err := registry.WaitAssetCreated(ctx, txHash)
+	require.NoError(t, err)
+
+	token, err := chain.Client.Token(created.AssetAddress)
+	require.NoError(t, err)
+	admin := chain.Client.Address()
+	holder := crypto.PubkeyToAddress(chain.UserKey.PublicKey)
+
+	_, err = token.Mint(ctx, admin, big.NewInt(1000))
+	require.NoError(t, err)
+	receipt, err := token.Transfer(ctx, holder, big.NewInt(400))
+	require.NoError(t, err)
+
+	waitForBlock(t, ctx, chain, receipt.BlockNumber.Uint64()+chain.Client.Cfg.ReorgDepth)
+
+	t.Run("Sync indexes tokens and transfers", func(t *testing.T) {
+		checkpoint, err := indexer.Sync(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, checkpoint)
+		assert.GreaterOrEqual(t, checkpoint.LastBlock, receipt.BlockNumber.Uint64())
+
+		linked, err := repos.Project.FindByID(ctx, p.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, project.ProjectStatusDeployed, linked.Status)
+		require.NotNil(t, linked.ContractAddress)
+		assert.Equal(t, created.AssetAddress.Hex(), *linked.ContractAddress)
+
+		adminHolding, err := repos.Token.FindHolding(ctx, created.AssetAddress.Hex(), admin.Hex())
+		require.NoError(t, err)
+		require.NotNil(t, adminHolding)
+		assert.Equal(t, "600", adminHolding.Balance)
+
+		holderHolding, err := repos.Token.FindHolding(ctx, created.AssetAddress.Hex(), holder.Hex())
+		require.NoError(t, err)
+		require.NotNil(t, holderHolding)
+		assert.Equal(t, "400", holderHolding.Balance)
+		assert.Equal(t, p.ID, *holderHolding.ProjectID)
+	})
+
+	t.Run("Deploy job finishing after the indexer linked succeeds", func(t *testing.T) {
+		client, sender := itesting.DryRunEmail(t, nil)
+		srv.Email = client
+		defer func() { srv.Email = nil }()
+
+		assets := service.NewAssetService(srv, repos.Project, repos.User, repos.Token)
+		require.NoError(t, assets.DeployProject(ctx, p.ID.String(), ""))
+
+		deployed, err := repos.Project.FindByID(ctx, p.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, project.ProjectStatusDeployed, deployed.Status)
+		assert.Equal(t, created.AssetAddress.Hex(), *deployed.ContractAddress)
+
+		// The indexer does not email; the job tells the supplier
+		messages := sender.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, "supplier@example.com", messages[0].To)
+		assert.Contains(t, messages[0].HTML, created.AssetAddress.Hex())
+	})
+
+	t.Run("Sync resumes from the checkpoint", func(t *testing.T) {
+		_, err := token.Transfer(ctx, holder, big.NewInt(100))
+		require.NoError(t, err)
+		head, err := chain.Client.BlockNumber(ctx)
+		require.NoError(t, err)
+		waitForBlock(t, ctx, chain, head+chain.Client.Cfg.ReorgDepth)
+
+		_, err = indexer.Sync(ctx)
+		require.NoError(t, err)
+
+		holderHolding, err := repos.Token.FindHolding(ctx, created.AssetAddress.Hex(), holder.Hex())
+		require.NoError(t, err)
+		require.NotNil(t, holderHolding)
+		assert.Equal(t, "500", holderHolding.Balance)
+	})
+}
+
+func waitForBlock(t *testing.T, ctx context.Context, chain *itesting.TestChain, number uint64) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		head, err := chain.Client.BlockNumber(ctx)
+		return err == nil && head >= number
+	}, 10*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/listing"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSellerWallet  = "0x1111111111111111111111111111111111111111"
+	otherSellerWallet = "0x2222222222222222222222222222222222222222"
+)
+
+func TestListingLifecycle(t *testing.T) {
+	testDB, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	request := func(method, path string, body any) *httptest.ResponseRecorder {
+		var reader *bytes.Reader
+		if body != nil {
+			reader = bytes.NewReader(itesting.MustMarshalJSON(t, body))
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, method+" "+path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	// Mock user with a linked wallet
+	rec := request(http.MethodPost, "/api/v1/auth/sync-user", user.SyncUserPayload{Email: "seller@example.com"})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// Without a wallet nothing can be listed yet
+	repos := repository.NewRepositories(srv)
+	_, _, err := repos.User.UpsertUser(ctx, "user_listing_supplier", "supplier@example.com", user.RoleSupplier)
+	require.NoError(t, err)
+	p, err := repos.Project.Create(ctx, project.Project{
+		SupplierID:   "user_listing_supplier",
+		Title:        "Forest Conservation",
+		Description:  "Protecting primary forest.",
+		ImageURL:     "https://example.com/forest.jpg",
+		CarbonAmount: 500,
+		Status:       project.ProjectStatusDeployed,
+	})
+	require.NoError(t, err)
+
+	rec = request(http.MethodPost, "/api/v1/listings", map[string]any{
+		"projectId": p.ID.String(), "pricePerToken": 12.5, "quantityAvailable": 100,
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, err = testDB.Pool.Exec(ctx, `UPDATE users SET wallet_address = $1, wallet_verified_at = NOW() WHERE clerk_id = 'user_test_mock_123'`, testSellerWallet)
+	require.NoError(t, err)
+
+	// CREATE
+	rec = request(http.MethodPost, "/api/v1/listings", map[string]any{
+		"projectId": p.ID.String(), "pricePerToken": 12.5, "quantityAvailable": 100,
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var created listing.Listing
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, testSellerWallet, created.SellerAddress)
+	assert.True(t, created.Active)
+
+	// Someone else's listing on the same project
+	other, err := repos.Listing.Create(ctx, listing.Listing{
+		ProjectID: p.ID, SellerAddress: otherSellerWallet, PricePerToken: 10, QuantityAvailable: 50,
+	})
+	require.NoError(t, err)
+
+	// BROWSE (cheapest first)
+	rec = request(http.MethodGet, "/api/v1/listings?projectId="+p.ID.String()+"&page=1&limit=1", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var page model.PaginatedResponse[listing.Listing]
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Equal(t, 2, page.Total)
+	assert.Equal(t, 2, page.TotalPages)
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, other.ID, page.Data[0].ID)
+
+	// UPDATE own listing
+	rec = request(http.MethodPatch, "/api/v1/listings/"+created.ID.String(), map[string]any{"pricePerToken": 11})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var updated listing.Listing
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, 11.0, updated.PricePerToken)
+	assert.Equal(t, 100.0, updated.QuantityAvailable)
+
+	// Ownership is checked against the seller wallet
+	rec = request(http.MethodPatch, "/api/v1/listings/"+other.ID.String(), map[string]any{"pricePerToken": 1})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = request(http.MethodDelete, "/api/v1/listings/"+other.ID.String(), nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// DEACTIVATE removes it from browsing
+	rec = request(http.MethodDelete, "/api/v1/listings/"+created.ID.String(), nil)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = request(http.MethodGet, "/api/v1/listings?projectId="+p.ID.String(), nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Equal(t, 1, page.Total)
+
+	rec = request(http.MethodPatch, "/api/v1/listings/"+created.ID.String(), map[string]any{"pricePerToken": 9})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,200 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/listing"
+	"github.com/inventedsarawak/ledgera/internal/model/order"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderLifecycle(t *testing.T) {
+	testDB, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	chain := itesting.SetupTestChain(t)
+	srv.Blockchain = chain.Client
+
+	ctx := context.Background()
+
+	request := func(method, path string, body any) *httptest.ResponseRecorder {
+		var reader *bytes.Reader
+		if body != nil {
+			reader = bytes.NewReader(itesting.MustMarshalJSON(t, body))
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, method+" "+path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	decodeOrder := func(rec *httptest.ResponseRecorder) order.Order {
+		var o order.Order
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &o))
+		return o
+	}
+
+	available := func(listingID string) float64 {
+		l, err := repository.NewListingRepository(srv).FindByID(ctx, listingID)
+		require.NoError(t, err)
+		return l.QuantityAvailable
+	}
+
+	// The chain admin is the seller, the second test account the buyer.
+	seller := chain.Client.Address()
+	buyer := crypto.PubkeyToAddress(chain.UserKey.PublicKey)
+
+	rec := request(http.MethodPost, "/api/v1/auth/sync-user", user.SyncUserPayload{Email: "buyer@example.com"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, err := testDB.Pool.Exec(ctx, `UPDATE users SET role = 'BUYER', wallet_address = $1 WHERE clerk_id = 'user_test_mock_123'`, buyer.Hex())
+	require.NoError(t, err)
+
+	repos := repository.NewRepositories(srv)
+	_, _, err = repos.User.UpsertUser(ctx, "user_order_supplier", "supplier@example.com", user.RoleSupplier)
+	require.NoError(t, err)
+	p, err := repos.Project.Create(ctx, project.Project{
+		SupplierID:   "user_order_supplier",
+		Title:        "Mangrove Restoration",
+		Description:  "Replanting coastal mangroves.",
+		ImageURL:     "https://example.com/mangrove.jpg",
+		CarbonAmount: 500,
+		Status:       project.ProjectStatusApproved,
+	})
+	require.NoError(t, err)
+
+	registry, err := chain.Client.Registry()
+	require.NoError(t, err)
+	created, err := registry.CreateAsset(ctx, p.Title, "MR0001")
+	require.NoError(t, err)
+	_, err = repos.Project.MarkDeployed(ctx, p.ID.String(), created.AssetAddress.Hex(), "MR0001", created.TxHash.Hex())
+	require.NoError(t, err)
+
+	l, err := repos.Listing.Create(ctx, listing.Listing{
+		ProjectID: p.ID, SellerAddress: seller.Hex(), PricePerToken: 10, QuantityAvailable: 50,
+	})
+	require.NoError(t, err)
+	listingID := l.ID.String()
+
+	t.Run("Place reserves quantity", func(t *testing.T) {
+		rec := request(http.MethodPost, "/api/v1/orders", map[string]any{"listingId": listingID, "quantity": 30})
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		placed := decodeOrder(rec)
+		assert.Equal(t, order.OrderStatusReserved, placed.Status)
+		assert.Equal(t, 300.0, placed.TotalPrice)
+		assert.Equal(t, buyer.Hex(), placed.BuyerAddress)
+		assert.Equal(t, 20.0, available(listingID))
+
+		// The rest of the listing cannot cover a second order this size
+		rec = request(http.MethodPost, "/api/v1/orders", map[string]any{"listingId": listingID, "quantity": 30})
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		// CANCEL gives the quantity back
+		rec = request(http.MethodPost, "/api/v1/orders/"+placed.ID.String()+"/cancel", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, order.OrderStatusCancelled, decodeOrder(rec).Status)
+		assert.Equal(t, 50.0, available(listingID))
+
+		rec = request(http.MethodPost, "/api/v1/orders/"+placed.ID.String()+"/cancel", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Expired reservations are released", func(t *testing.T) {
+		rec := request(http.MethodPost, "/api/v1/orders", map[string]any{"listingId": listingID, "quantity": 5})
+		require.Equal(t, http.StatusCreated, rec.Code)
+		placed := decodeOrder(rec)
+		assert.Equal(t, 45.0, available(listingID))
+
+		_, err := testDB.Pool.Exec(ctx, `UPDATE orders SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, placed.ID)
+		require.NoError(t, err)
+
+		orders := service.NewOrderService(srv, repos.Order, repos.Listing, repos.Project, repos.User)
+		expired, err := orders.ExpireReservations(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), expired)
+		assert.Equal(t, 50.0, available(listingID))
+
+		rec = request(http.MethodGet, "/api/v1/orders/"+placed.ID.String(), nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, order.OrderStatusExpired, decodeOrder(rec).Status)
+
+		rec = request(http.MethodPost, "/api/v1/orders/"+placed.ID.String()+"/pay", map[string]any{"paymentReference": "pi_late"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Pay and settle with the on-chain transfer", func(t *testing.T) {
+		rec := request(http.MethodPost, "/api/v1/orders", map[string]any{"listingId": listingID, "quantity": 10})
+		require.Equal(t, http.StatusCreated, rec.Code)
+		placed := decodeOrder(rec)
+		path := "/api/v1/orders/" + placed.ID.String()
+
+		// Settling before payment is rejected
+		rec = request(http.MethodPost, path+"/settle", map[string]any{"txHash": created.TxHash.Hex()})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = request(http.MethodPost, path+"/pay", map[string]any{"paymentReference": "pi_123"})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, order.OrderStatusPaid, decodeOrder(rec).Status)
+
+		token, err := chain.Client.Token(created.AssetAddress)
+		require.NoError(t, err)
+		unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+		_, err = token.Mint(ctx, seller, new(big.Int).Mul(big.NewInt(50), unit))
+		require.NoError(t, err)
+
+		// A transfer of the wrong amount does not settle the order
+		short, err := token.Transfer(ctx, buyer, new(big.Int).Mul(big.NewInt(3), unit))
+		require.NoError(t, err)
+		rec = request(http.MethodPost, path+"/settle", map[string]any{"txHash": short.TxHash.Hex()})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		delivery, err := token.Transfer(ctx, buyer, new(big.Int).Mul(big.NewInt(10), unit))
+		require.NoError(t, err)
+		rec = request(http.MethodPost, path+"/settle", map[string]any{"txHash": delivery.TxHash.Hex()})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		settled := decodeOrder(rec)
+		assert.Equal(t, order.OrderStatusSettled, settled.Status)
+		require.NotNil(t, settled.SettlementTxHash)
+		assert.Equal(t, delivery.TxHash.Hex(), *settled.SettlementTxHash)
+		assert.Equal(t, 40.0, available(listingID))
+
+		rec = request(http.MethodGet, "/api/v1/orders?status=SETTLED", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var page model.PaginatedResponse[order.Order]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Equal(t, 1, page.Total)
+
+		// The same transfer cannot settle a second order
+		rec = request(http.MethodPost, "/api/v1/orders", map[string]any{"listingId": listingID, "quantity": 10})
+		require.Equal(t, http.StatusCreated, rec.Code)
+		second := "/api/v1/orders/" + decodeOrder(rec).ID.String()
+		rec = request(http.MethodPost, second+"/pay", map[string]any{"paymentReference": "pi_456"})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rec = request(http.MethodPost, second+"/settle", map[string]any{"txHash": delivery.TxHash.Hex()})
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		rec = request(http.MethodGet, second, nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, order.OrderStatusPaid, decodeOrder(rec).Status)
+	})
+}
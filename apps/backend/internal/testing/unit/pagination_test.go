@@ -0,0 +1,171 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/listing"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	c := model.Cursor{
+		CreatedAt: time.Date(2026, 3, 4, 5, 6, 7, 123456000, time.UTC),
+		ID:        uuid.MustParse("6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"),
+	}
+
+	decoded, err := model.DecodeCursor(c.Encode())
+	require.NoError(t, err)
+	require.NotNil(t, decoded)
+	assert.True(t, c.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, c.ID, decoded.ID)
+
+	start, err := model.DecodeCursor("")
+	require.NoError(t, err)
+	assert.Nil(t, start)
+
+	for _, bad := range []string{"not base64!", "bm8tY29tbWE", "MjAyNi0wMy0wNCxub3QtYS11dWlk"} {
+		_, err := model.DecodeCursor(bad)
+		assert.ErrorIs(t, err, model.ErrInvalidCursor, bad)
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	testDB, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repos := repository.NewRepositories(srv)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "GET "+path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	// Two projects share a timestamp so the id tiebreaker is exercised
+	sameTime := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	var seeded []*project.Project
+	for i := 0; i < 5; i++ {
+		p, err := repos.Project.Create(ctx, project.Project{
+			SupplierID:   "user_test_mock_123",
+			Title:        "Feed Project",
+			Description:  "Paged through by cursor.",
+			ImageURL:     "https://example.com/p.jpg",
+			CarbonAmount: 100,
+			Status:       project.ProjectStatusDeployed,
+		})
+		require.NoError(t, err)
+		createdAt := sameTime.Add(-time.Duration(i) * time.Minute)
+		if i == 1 {
+			createdAt = sameTime
+		}
+		_, err = testDB.Pool.Exec(ctx, `UPDATE projects SET created_at = $1 WHERE id = $2`, createdAt, p.ID)
+		require.NoError(t, err)
+		seeded = append(seeded, p)
+	}
+
+	t.Run("ProjectsMine", func(t *testing.T) {
+		seen := map[uuid.UUID]bool{}
+		cursor := ""
+		pages := 0
+		for {
+			rec := get("/api/v1/projects/mine?limit=2&cursor=" + cursor)
+			require.Equal(t, http.StatusOK, rec.Code)
+			if pages == 0 {
+				assert.Equal(t, "5", rec.Header().Get("X-Total-Count"))
+			} else {
+				// Later pages are not counted again
+				assert.Empty(t, rec.Header().Get("X-Total-Count"))
+			}
+
+			var items []project.Project
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &items))
+			for _, p := range items {
+				assert.False(t, seen[p.ID], "duplicate %s", p.ID)
+				seen[p.ID] = true
+			}
+			pages++
+
+			// A project created mid-scroll is newer than the cursor and
+			// does not shift later pages
+			if pages == 1 {
+				_, err := repos.Project.Create(ctx, project.Project{
+					SupplierID: "user_test_mock_123", Title: "Late Project", Description: "Inserted mid-scroll.",
+					ImageURL: "https://example.com/p.jpg", CarbonAmount: 1, Status: project.ProjectStatusDraft,
+				})
+				require.NoError(t, err)
+			}
+
+			cursor = rec.Header().Get("X-Next-Cursor")
+			if cursor == "" {
+				break
+			}
+		}
+		assert.Equal(t, 3, pages)
+		for _, p := range seeded {
+			assert.True(t, seen[p.ID], "missing %s", p.ID)
+		}
+	})
+
+	t.Run("PublicBrowse", func(t *testing.T) {
+		rec := get("/api/v1/public/projects?limit=4&cursor=")
+		require.Equal(t, http.StatusOK, rec.Code)
+		next := rec.Header().Get("X-Next-Cursor")
+		require.NotEmpty(t, next)
+
+		rec = get("/api/v1/public/projects?limit=4&cursor=" + next)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var items []project.Project
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &items))
+		assert.Len(t, items, 1)
+		assert.Empty(t, rec.Header().Get("X-Next-Cursor"))
+
+		rec = get("/api/v1/public/projects?sort=title&cursor=")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		rec = get("/api/v1/public/projects?cursor=garbage")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Listings", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_, err := repos.Listing.Create(ctx, listing.Listing{
+				ProjectID: seeded[0].ID, SellerAddress: testSellerWallet, PricePerToken: float64(10 + i), QuantityAvailable: 5, Active: true,
+			})
+			require.NoError(t, err)
+		}
+
+		var page model.PaginatedResponse[listing.Listing]
+		rec := get("/api/v1/listings?limit=2&cursor=")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Len(t, page.Data, 2)
+		assert.Equal(t, 3, page.Total)
+		require.NotNil(t, page.NextCursor)
+
+		rec = get("/api/v1/listings?limit=2&cursor=" + *page.NextCursor)
+		require.Equal(t, http.StatusOK, rec.Code)
+		page = model.PaginatedResponse[listing.Listing]{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Len(t, page.Data, 1)
+		assert.Nil(t, page.NextCursor)
+
+		// Offset mode is unchanged
+		rec = get("/api/v1/listings?limit=2&page=2")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "nextCursor")
+	})
+}
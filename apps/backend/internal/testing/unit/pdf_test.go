@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/lib/pdf"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestRenderCertificate(t *testing.T) {
+	data := pdf.CertificateData{
+		CertificateID:   "6f1c2d3e-4a5b-4c6d-8e7f-901a2b3c4d5e",
+		BeneficiaryName: "Société Générale Malaysia",
+		Tonnes:          12.5,
+		ProjectTitle:    "Peatland Rewetting, Sarawak",
+		Reason:          "Offsetting FY2026 scope 1 emissions",
+		TxHash:          "0x9c1e4b0f3a5d7e2c8b6a4f1d3e5c7b9a2d4f6e8c0b1a3d5f7e9c2b4a6d8f0e1c",
+		WalletAddress:   "0x5B38Da6a701c568545dCfcB03FcB875f56beddC4",
+		RetiredAt:       time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC),
+		VerifyURL:       "https://api.ledgera.example/api/v1/public/certificates/6f1c2d3e-4a5b-4c6d-8e7f-901a2b3c4d5e",
+		ContentHash:     "0x3b9f6c2a1d8e4f7a0c5b2e9d6a3f1c8b7e4d2a9f6c3b0e7d4a1f8c5b2e9d6a3f",
+	}
+
+	out, err := pdf.RenderCertificate(data)
+	require.NoError(t, err)
+	assertGolden(t, "certificate.golden.pdf", out)
+
+	again, err := pdf.RenderCertificate(data)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(out, again), "rendering is not deterministic")
+
+	t.Run("without a verify URL", func(t *testing.T) {
+		data.VerifyURL = ""
+		plain, err := pdf.RenderCertificate(data)
+		require.NoError(t, err)
+		assert.Less(t, len(plain), len(out))
+	})
+}
+
+func TestRenderFactSheet(t *testing.T) {
+	symbol := "PR0001"
+	contract := "0xd9145CCE52D386f254917e481eB44e9943F39138"
+	p := project.Project{
+		Base: model.Base{
+			BaseWithId:        model.BaseWithId{ID: uuid.MustParse("0b7e3f52-1c2d-4e5f-a6b7-c8d9e0f1a2b3")},
+			BaseWithCreatedAt: model.BaseWithCreatedAt{CreatedAt: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+			BaseWithUpdatedAt: model.BaseWithUpdatedAt{UpdatedAt: time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)},
+		},
+		SupplierID:      "user_supplier",
+		Title:           "Peatland Rewetting, Sarawak",
+		Description:     "Blocking drainage canals to rewet 1,200 hectares of degraded peat swamp forest, cutting peat oxidation and fire risk.",
+		AuditReportURL:  "https://example.com/audit.pdf",
+		LocationLat:     2.30722,
+		LocationLng:     111.83099,
+		Area:            1200,
+		CarbonAmount:    45000,
+		PricePerTonne:   18.5,
+		ContractAddress: &contract,
+		TokenSymbol:     &symbol,
+		Status:          project.ProjectStatusDeployed,
+	}
+
+	out, err := pdf.RenderFactSheet(p)
+	require.NoError(t, err)
+	assertGolden(t, "factsheet.golden.pdf", out)
+}
+
+// assertGolden compares got with testdata/name. Run the tests with -update to
+// rewrite the file after an intentional layout change.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden file, run the test with -update")
+	assert.True(t, bytes.Equal(want, got), "%s does not match the golden file, run the test with -update if the change is intended", name)
+}
@@ -0,0 +1,215 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/inventedsarawak/ledgera/internal/lib/geo"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// squareGeoJSON is a 0.01° square near Kuching, about 123.88 ha.
+const squareGeoJSON = `{
+	"type": "Feature",
+	"properties": {"name": "Plot A"},
+	"geometry": {
+		"type": "Polygon",
+		"coordinates": [[[110.35, 1.50], [110.36, 1.50], [110.36, 1.51], [110.35, 1.51], [110.35, 1.50]]]
+	}
+}`
+
+const squareKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <Placemark>
+      <name>Plot A</name>
+      <Polygon>
+        <outerBoundaryIs>
+          <LinearRing>
+            <coordinates>
+              110.35,1.50,0 110.36,1.50,0 110.36,1.51,0 110.35,1.51,0 110.35,1.50,0
+            </coordinates>
+          </LinearRing>
+        </outerBoundaryIs>
+        <innerBoundaryIs>
+          <LinearRing>
+            <coordinates>110.352,1.502 110.354,1.502 110.354,1.504 110.352,1.504 110.352,1.502</coordinates>
+          </LinearRing>
+        </innerBoundaryIs>
+      </Polygon>
+    </Placemark>
+  </Document>
+</kml>`
+
+func TestBoundaryParsing(t *testing.T) {
+	t.Run("GeoJSON", func(t *testing.T) {
+		p, err := geo.Parse([]byte(squareGeoJSON))
+		require.NoError(t, err)
+		require.NoError(t, p.Validate())
+		assert.InDelta(t, 123.88, p.AreaHectares(), 0.01)
+		assert.JSONEq(t, `{"type":"Polygon","coordinates":[[[110.35,1.5],[110.36,1.5],[110.36,1.51],[110.35,1.51],[110.35,1.5]]]}`, string(p.GeoJSON()))
+	})
+
+	t.Run("KML", func(t *testing.T) {
+		p, err := geo.Parse([]byte(squareKML))
+		require.NoError(t, err)
+		require.Len(t, p, 2)
+		require.NoError(t, p.Validate())
+		// The hole is 0.002° square, about 4.96 ha.
+		assert.InDelta(t, 123.88-4.96, p.AreaHectares(), 0.01)
+	})
+
+	t.Run("RepeatedVerticesAreDropped", func(t *testing.T) {
+		p, err := geo.Parse([]byte(`{"type":"Polygon","coordinates":[[[110.35,1.5],[110.36,1.5],[110.36,1.5],[110.36,1.51],[110.35,1.51],[110.35,1.5]]]}`))
+		require.NoError(t, err)
+		assert.Len(t, p[0], 5)
+		assert.NoError(t, p.Validate())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cases := map[string]struct {
+			doc string
+			err error
+		}{
+			"NotADocument":   {`hello`, geo.ErrUnsupportedFormat},
+			"Point":          {`{"type":"Point","coordinates":[110.35,1.5]}`, geo.ErrUnsupportedGeometry},
+			"TwoFeatures":    {`{"type":"FeatureCollection","features":[` + squareGeoJSON + `,` + squareGeoJSON + `]}`, geo.ErrUnsupportedGeometry},
+			"Unclosed":       {`{"type":"Polygon","coordinates":[[[110.35,1.5],[110.36,1.5],[110.36,1.51],[110.35,1.51]]]}`, geo.ErrRingNotClosed},
+			"TooFew":         {`{"type":"Polygon","coordinates":[[[110.35,1.5],[110.36,1.5],[110.35,1.5]]]}`, geo.ErrTooFewPositions},
+			"OutOfRange":     {`{"type":"Polygon","coordinates":[[[190,1.5],[191,1.5],[191,1.51],[190,1.5]]]}`, geo.ErrOutOfRange},
+			"BowTie":         {`{"type":"Polygon","coordinates":[[[110.35,1.5],[110.36,1.51],[110.36,1.5],[110.35,1.51],[110.35,1.5]]]}`, geo.ErrSelfIntersection},
+			"Spike":          {`{"type":"Polygon","coordinates":[[[110.35,1.5],[110.36,1.5],[110.37,1.5],[110.36,1.5],[110.36,1.51],[110.35,1.5]]]}`, geo.ErrSelfIntersection},
+			"HoleOutside":    {`{"type":"Polygon","coordinates":[[[110.35,1.5],[110.36,1.5],[110.36,1.51],[110.35,1.51],[110.35,1.5]],[[110.40,1.5],[110.41,1.5],[110.41,1.51],[110.40,1.5]]]}`, geo.ErrHoleOutside},
+			"TooSmall":       {`{"type":"Polygon","coordinates":[[[110.35,1.5],[110.3501,1.5],[110.3501,1.5001],[110.35,1.5001],[110.35,1.5]]]}`, geo.ErrAreaOutOfRange},
+			"TooLarge":       {`{"type":"Polygon","coordinates":[[[100,0],[120,0],[120,10],[100,10],[100,0]]]}`, geo.ErrAreaOutOfRange},
+			"KMLWithoutRing": {`<kml><Placemark><Point><coordinates>110.35,1.5</coordinates></Point></Placemark></kml>`, geo.ErrUnsupportedGeometry},
+		}
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				p, err := geo.Parse([]byte(tc.doc))
+				if err == nil {
+					err = p.Validate()
+				}
+				assert.ErrorIs(t, err, tc.err)
+			})
+		}
+	})
+}
+
+func TestProjectBoundary(t *testing.T) {
+	_, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repos := repository.NewRepositories(srv)
+
+	seed := func(supplierID string, area float64, status project.ProjectStatus) *project.Project {
+		p, err := repos.Project.Create(ctx, project.Project{
+			SupplierID:   supplierID,
+			Title:        "Kuching Wetlands",
+			Description:  "Wetland restoration near Kuching.",
+			ImageURL:     "https://example.com/p.jpg",
+			LocationLat:  1.505,
+			LocationLng:  110.355,
+			Area:         area,
+			CarbonAmount: 100,
+			Status:       status,
+		})
+		require.NoError(t, err)
+		return p
+	}
+
+	upload := func(id string, fileName string, content string) *httptest.ResponseRecorder {
+		ct, body := createMultipartBody(t, nil, "boundary", fileName, []byte(content))
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/projects/"+id+"/boundary", bytes.NewReader(body))
+		req.Header.Set("Content-Type", ct)
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "PUT boundary", rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+id+"/boundary", nil)
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "GET boundary", rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	t.Run("MatchingArea", func(t *testing.T) {
+		p := seed("user_test_mock_123", 120, project.ProjectStatusDraft)
+
+		rec := get(p.ID.String())
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = upload(p.ID.String(), "plot.geojson", squareGeoJSON)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var updated project.Project
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+		require.NotNil(t, updated.BoundaryAreaHa)
+		assert.InDelta(t, 123.88, *updated.BoundaryAreaHa, 0.01)
+		assert.False(t, updated.AreaMismatch)
+
+		rec = get(p.ID.String())
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/geo+json", rec.Header().Get("Content-Type"))
+		var feature project.BoundaryFeature
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &feature))
+		assert.Equal(t, "Feature", feature.Type)
+		assert.Equal(t, p.ID, feature.ID)
+		assert.InDelta(t, 123.88, feature.Properties.AreaHa, 0.01)
+		assert.Equal(t, 120.0, feature.Properties.DeclaredAreaHa)
+
+		var geometry struct {
+			Type        string         `json:"type"`
+			Coordinates [][][2]float64 `json:"coordinates"`
+		}
+		require.NoError(t, json.Unmarshal(feature.Geometry, &geometry))
+		assert.Equal(t, "Polygon", geometry.Type)
+		assert.Len(t, geometry.Coordinates[0], 5)
+	})
+
+	t.Run("MismatchedArea", func(t *testing.T) {
+		p := seed("user_test_mock_123", 500, project.ProjectStatusRejected)
+
+		rec := upload(p.ID.String(), "plot.kml", squareKML)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var updated project.Project
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+		assert.True(t, updated.AreaMismatch)
+		assert.Equal(t, project.ProjectStatusDraft, updated.Status, "revising a rejected project reopens it")
+
+		// Correcting the declared area clears the flag.
+		area := 119.0
+		fixed, err := repos.Project.Update(ctx, p.ID.String(), project.UpdateProjectPayload{Area: &area})
+		require.NoError(t, err)
+		assert.False(t, fixed.AreaMismatch)
+	})
+
+	t.Run("Refused", func(t *testing.T) {
+		mine := seed("user_test_mock_123", 120, project.ProjectStatusDraft)
+		theirs := seed("user_someone_else", 120, project.ProjectStatusDraft)
+		pending := seed("user_test_mock_123", 120, project.ProjectStatusPending)
+
+		rec := upload(mine.ID.String(), "bowtie.geojson", `{"type":"Polygon","coordinates":[[[110.35,1.5],[110.36,1.51],[110.36,1.5],[110.35,1.51],[110.35,1.5]]]}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), geo.ErrSelfIntersection.Error())
+
+		rec = upload(theirs.ID.String(), "plot.geojson", squareGeoJSON)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		rec = upload(pending.ID.String(), "plot.geojson", squareGeoJSON)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
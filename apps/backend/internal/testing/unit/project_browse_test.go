@@ -0,0 +1,127 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowseProjects(t *testing.T) {
+	testDB, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repos := repository.NewRepositories(srv)
+
+	seed := func(title, description string, price, carbon float64, status project.ProjectStatus, age time.Duration) *project.Project {
+		p, err := repos.Project.Create(ctx, project.Project{
+			SupplierID:    "user_browse_supplier",
+			Title:         title,
+			Description:   description,
+			ImageURL:      "https://example.com/p.jpg",
+			CarbonAmount:  carbon,
+			PricePerTonne: price,
+			Status:        status,
+		})
+		require.NoError(t, err)
+		_, err = testDB.Pool.Exec(ctx, `UPDATE projects SET created_at = $1 WHERE id = $2`, time.Now().Add(-age), p.ID)
+		require.NoError(t, err)
+		return p
+	}
+
+	mangrove := seed("Mangrove Restoration", "Replanting mangroves along the Sarawak coast.", 12, 500, project.ProjectStatusDeployed, 72*time.Hour)
+	peat := seed("Peatland Rewetting", "Blocking drainage canals to restore peat swamp forest.", 25, 1200, project.ProjectStatusApproved, 48*time.Hour)
+	cookstoves := seed("Clean Cookstoves", "Efficient stoves for rural households near the mangrove belt.", 8, 300, project.ProjectStatusDeployed, 24*time.Hour)
+	seed("Mangrove Nursery", "A draft that is not public yet.", 5, 100, project.ProjectStatusDraft, time.Hour)
+	seed("Mangrove Extension", "Still waiting for review.", 5, 100, project.ProjectStatusPending, time.Hour)
+
+	browse := func(params url.Values) ([]project.Project, *httptest.ResponseRecorder) {
+		path := "/api/v1/public/projects?" + params.Encode()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "GET "+path, rec.Code, rec.Body.Bytes())
+
+		var items []project.Project
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &items))
+		}
+		return items, rec
+	}
+
+	ids := func(items []project.Project) []string {
+		out := make([]string, len(items))
+		for i, p := range items {
+			out[i] = p.ID.String()
+		}
+		return out
+	}
+
+	t.Run("OnlyReviewedProjects", func(t *testing.T) {
+		items, rec := browse(url.Values{})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "3", rec.Header().Get("X-Total-Count"))
+		assert.Equal(t, []string{cookstoves.ID.String(), peat.ID.String(), mangrove.ID.String()}, ids(items))
+
+		_, rec = browse(url.Values{"status": {"DRAFT"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		items, rec = browse(url.Values{"status": {"APPROVED"}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{peat.ID.String()}, ids(items))
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		items, rec := browse(url.Values{"search": {"mangroves"}, "sort": {"relevance"}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		// The title match ranks above the description-only match
+		assert.Equal(t, []string{mangrove.ID.String(), cookstoves.ID.String()}, ids(items))
+
+		items, rec = browse(url.Values{"search": {"peat -mangrove"}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{peat.ID.String()}, ids(items))
+	})
+
+	t.Run("Ranges", func(t *testing.T) {
+		items, rec := browse(url.Values{"minPrice": {"10"}, "maxPrice": {"20"}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{mangrove.ID.String()}, ids(items))
+
+		items, rec = browse(url.Values{"minCarbon": {"400"}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.ElementsMatch(t, []string{mangrove.ID.String(), peat.ID.String()}, ids(items))
+
+		items, rec = browse(url.Values{"createdAfter": {time.Now().Add(-60 * time.Hour).UTC().Format(time.RFC3339)}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.ElementsMatch(t, []string{peat.ID.String(), cookstoves.ID.String()}, ids(items))
+
+		_, rec = browse(url.Values{"minPrice": {"20"}, "maxPrice": {"10"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		_, rec = browse(url.Values{"createdAfter": {"yesterday"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Sort", func(t *testing.T) {
+		items, rec := browse(url.Values{"sort": {"price_per_tonne"}, "order": {"asc"}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{cookstoves.ID.String(), mangrove.ID.String(), peat.ID.String()}, ids(items))
+
+		items, rec = browse(url.Values{"sort": {"carbon_amount"}, "order": {"desc"}, "limit": {"1"}, "page": {"2"}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []string{mangrove.ID.String()}, ids(items))
+		assert.Equal(t, "3", rec.Header().Get("X-Total-Count"))
+
+		_, rec = browse(url.Values{"sort": {"supplier_id; DROP TABLE projects"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,175 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/lib/geo"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewWarnings(t *testing.T) {
+	submitted := uuid.MustParse("6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b")
+	other := uuid.MustParse("0a1b2c3d-4e5f-4061-8273-8495a6b7c8d9")
+	f := func(v float64) *float64 { return &v }
+
+	kinds := func(ws []project.ReviewWarning) []project.ReviewWarningKind {
+		out := []project.ReviewWarningKind{}
+		for _, w := range ws {
+			assert.Equal(t, submitted, w.ProjectID)
+			assert.Equal(t, other, w.ConflictingProjectID)
+			out = append(out, w.Kind)
+		}
+		return out
+	}
+
+	t.Run("OverlapHidesLocation", func(t *testing.T) {
+		ws := project.Conflict{ProjectID: other, Title: "Kuching Wetlands", OverlapHa: f(61.94), DistanceM: f(550), Contains: true, TitleSimilarity: 0.59}.Warnings(submitted)
+		assert.Equal(t, []project.ReviewWarningKind{project.WarningBoundaryOverlap, project.WarningSimilarTitle}, kinds(ws))
+		assert.Equal(t, `Boundary overlaps 61.94 ha of "Kuching Wetlands"`, ws[0].Message)
+		assert.Equal(t, `Title is 59% similar to "Kuching Wetlands"`, ws[1].Message)
+	})
+
+	t.Run("TouchingBoundaries", func(t *testing.T) {
+		ws := project.Conflict{ProjectID: other, Title: "Kuching Wetlands", OverlapHa: f(0), DistanceM: f(1200), Contains: true}.Warnings(submitted)
+		assert.Equal(t, []project.ReviewWarningKind{project.WarningLocationOverlap}, kinds(ws))
+		assert.Equal(t, `Location lies within the boundary of "Kuching Wetlands"`, ws[0].Message)
+	})
+
+	t.Run("Nearby", func(t *testing.T) {
+		ws := project.Conflict{ProjectID: other, Title: "Sibu Peatland", DistanceM: f(111.4)}.Warnings(submitted)
+		assert.Equal(t, []project.ReviewWarningKind{project.WarningLocationOverlap}, kinds(ws))
+		assert.Equal(t, `Location is 111 m from "Sibu Peatland"`, ws[0].Message)
+	})
+
+	t.Run("BelowThresholds", func(t *testing.T) {
+		ws := project.Conflict{ProjectID: other, Title: "Sibu Peatland", DistanceM: f(800), TitleSimilarity: 0.3}.Warnings(submitted)
+		assert.Empty(t, ws)
+	})
+}
+
+func TestProjectConflictWarnings(t *testing.T) {
+	_, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repos := repository.NewRepositories(srv)
+
+	// Ensure mock user exists (bypass auth sync)
+	{
+		payload := user.SyncUserPayload{Email: "admin@example.com"}
+		jsonBody := itesting.MustMarshalJSON(t, payload)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sync-user", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	square := func(minLng, minLat, size float64) []byte {
+		p := geo.Polygon{{
+			{minLng, minLat}, {minLng + size, minLat}, {minLng + size, minLat + size}, {minLng, minLat + size}, {minLng, minLat},
+		}}
+		require.NoError(t, p.Validate())
+		return p.GeoJSON()
+	}
+
+	seed := func(supplierID, title string, lat, lng float64, status project.ProjectStatus, boundary []byte) *project.Project {
+		p, err := repos.Project.Create(ctx, project.Project{
+			SupplierID:   supplierID,
+			Title:        title,
+			Description:  "A project somewhere in Sarawak.",
+			ImageURL:     "https://example.com/p.jpg",
+			LocationLat:  lat,
+			LocationLng:  lng,
+			Area:         100,
+			CarbonAmount: 100,
+			Status:       status,
+		})
+		require.NoError(t, err)
+		if boundary != nil {
+			_, err := repos.Project.SetBoundary(ctx, p.ID.String(), boundary, 100)
+			require.NoError(t, err)
+		}
+		return p
+	}
+
+	wetlands := seed("user_other_supplier", "Kuching Wetlands", 1.505, 110.355, project.ProjectStatusDeployed, square(110.35, 1.50, 0.01))
+	peatland := seed("user_other_supplier", "Sibu Peatland", 2.287, 111.8305, project.ProjectStatusApproved, nil)
+	// Drafts and pending projects are not checked against
+	seed("user_other_supplier", "Kuching Wetlands", 1.505, 110.355, project.ProjectStatusPending, square(110.35, 1.50, 0.01))
+
+	overlapping := seed("user_test_mock_123", "Kuching Wetlands Restoration", 1.505, 110.36, project.ProjectStatusDraft, square(110.355, 1.50, 0.01))
+	inside := seed("user_test_mock_123", "Santubong Forest", 1.503, 110.352, project.ProjectStatusDraft, nil)
+	nearby := seed("user_test_mock_123", "Rajang Delta Mangroves", 2.288, 111.8305, project.ProjectStatusDraft, nil)
+	distant := seed("user_test_mock_123", "Miri Coastal Mangroves", 4.3995, 113.9914, project.ProjectStatusDraft, nil)
+
+	for _, p := range []*project.Project{overlapping, inside, nearby, distant} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+p.ID.String()+"/submit", nil)
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "Submit "+p.Title, rec.Code, rec.Body.Bytes())
+		require.Equal(t, http.StatusAccepted, rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/review?page=1&limit=50", nil)
+	req.Header.Set("X-Test-Auth", "bypass")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	logResp(t, "ListPendingReview", rec.Code, rec.Body.Bytes())
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var pending []project.ProjectWithSupplier
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &pending))
+	warnings := map[uuid.UUID][]project.ReviewWarning{}
+	for _, p := range pending {
+		require.NotNil(t, p.Warnings, "warnings are always a list")
+		warnings[p.ID] = p.Warnings
+	}
+
+	t.Run("OverlappingBoundary", func(t *testing.T) {
+		ws := warnings[overlapping.ID]
+		require.Len(t, ws, 2)
+		assert.Equal(t, project.WarningBoundaryOverlap, ws[0].Kind)
+		assert.Equal(t, project.WarningSimilarTitle, ws[1].Kind)
+		for _, w := range ws {
+			assert.Equal(t, wetlands.ID, w.ConflictingProjectID)
+			assert.Equal(t, "Kuching Wetlands", w.ConflictingTitle)
+			assert.Equal(t, project.ProjectStatusDeployed, w.ConflictingStatus)
+		}
+		// Half of the 0.01° square, measured on the spheroid
+		assert.Regexp(t, `^Boundary overlaps 6[12]\.\d\d ha of "Kuching Wetlands"$`, ws[0].Message)
+	})
+
+	t.Run("LocationInsideBoundary", func(t *testing.T) {
+		ws := warnings[inside.ID]
+		require.Len(t, ws, 1)
+		assert.Equal(t, project.WarningLocationOverlap, ws[0].Kind)
+		assert.Equal(t, wetlands.ID, ws[0].ConflictingProjectID)
+		assert.Equal(t, `Location lies within the boundary of "Kuching Wetlands"`, ws[0].Message)
+	})
+
+	t.Run("NearbyLocation", func(t *testing.T) {
+		ws := warnings[nearby.ID]
+		require.Len(t, ws, 1)
+		assert.Equal(t, project.WarningLocationOverlap, ws[0].Kind)
+		assert.Equal(t, peatland.ID, ws[0].ConflictingProjectID)
+		assert.Contains(t, ws[0].Message, "m from \"Sibu Peatland\"")
+	})
+
+	t.Run("NoConflicts", func(t *testing.T) {
+		assert.Empty(t, warnings[distant.ID])
+	})
+}
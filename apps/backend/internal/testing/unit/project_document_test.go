@@ -0,0 +1,333 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/handler"
+	"github.com/inventedsarawak/ledgera/internal/lib/upload"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/router"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentType(t *testing.T) {
+	assert.True(t, project.DocumentImage.Accepts("image/png"))
+	assert.True(t, project.DocumentImage.Accepts("IMAGE/JPEG; charset=binary"))
+	assert.False(t, project.DocumentImage.Accepts("application/pdf"))
+	assert.True(t, project.DocumentLandTitle.Accepts("application/pdf"))
+	assert.True(t, project.DocumentLandTitle.Accepts("image/jpeg"), "scans are accepted")
+	assert.False(t, project.DocumentAuditReport.Accepts("text/html"))
+	assert.False(t, project.DocumentAuditReport.Accepts(""))
+
+	assert.Equal(t, "projects", project.DocumentImage.Folder())
+	assert.Equal(t, "documents", project.DocumentMethodology.Folder())
+	assert.Equal(t, int64(project.MaxImageSize), project.DocumentImage.MaxSize())
+	assert.Equal(t, int64(project.MaxDocumentSize), project.DocumentMonitoringReport.MaxSize())
+
+	assert.True(t, project.DocumentAuditReport.Reviewed())
+	assert.False(t, project.DocumentMonitoringReport.Reviewed())
+}
+
+func TestProjectDocuments(t *testing.T) {
+	testDB, _, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	// Ensure mock user exists (bypass auth sync)
+	{
+		payload := user.SyncUserPayload{Email: "test@example.com"}
+		jsonBody := itesting.MustMarshalJSON(t, payload)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sync-user", bytes.NewReader(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	do := func(method, path, contentType string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, method+" "+path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	ct, body := createMultipartBodyWithFiles(t, map[string]string{
+		"title":        "Mangrove Restoration",
+		"description":  "Restoring mangrove ecosystems for carbon sequestration.",
+		"locationLat":  "1.2345",
+		"locationLng":  "101.5678",
+		"area":         "123.45",
+		"carbonAmount": "1000",
+	}, map[string]struct {
+		name    string
+		content []byte
+	}{
+		"image":       {name: "cover.jpg", content: []byte("fake-image")},
+		"auditReport": {name: "audit.pdf", content: []byte("fake-audit-report")},
+	})
+	rec := do(http.MethodPost, "/api/v1/projects", ct, body)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created project.Project
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	base := "/api/v1/projects/" + created.ID.String()
+
+	list := func() []project.Document {
+		rec := do(http.MethodGet, base+"/documents", "", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var documents []project.Document
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &documents))
+		return documents
+	}
+
+	add := func(docType, fileName string) *httptest.ResponseRecorder {
+		ct, body := createMultipartBody(t, map[string]string{"type": docType}, "file", fileName, []byte("content of "+fileName))
+		return do(http.MethodPost, base+"/documents", ct, body)
+	}
+
+	imageURL := func() string {
+		var url string
+		require.NoError(t, testDB.Pool.QueryRow(t.Context(), `SELECT image_url FROM projects WHERE id = $1`, created.ID).Scan(&url))
+		return url
+	}
+
+	t.Run("CreatedWithProject", func(t *testing.T) {
+		documents := list()
+		require.Len(t, documents, 2)
+		assert.Equal(t, project.DocumentImage, documents[0].Type)
+		assert.Equal(t, "cover.jpg", documents[0].FileName)
+		assert.Equal(t, created.ImageURL, documents[0].URL)
+		require.NotNil(t, documents[0].ContentType)
+		assert.Equal(t, "image/jpeg", *documents[0].ContentType)
+		assert.Equal(t, project.DocumentAuditReport, documents[1].Type)
+		assert.Equal(t, created.AuditReportURL, documents[1].URL)
+		assert.Equal(t, "user_test_mock_123", documents[1].UploadedBy)
+	})
+
+	var gallery []project.Document
+	t.Run("AddAndReorder", func(t *testing.T) {
+		for _, name := range []string{"aerial.png", "nursery.webp"} {
+			rec := add("IMAGE", name)
+			require.Equal(t, http.StatusCreated, rec.Code)
+			var d project.Document
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &d))
+			gallery = append(gallery, d)
+		}
+		assert.Equal(t, 1, gallery[0].Position)
+		assert.Equal(t, 2, gallery[1].Position)
+
+		rec := add("LAND_TITLE", "title-deed.pdf")
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		rec = add("IMAGE", "notes.pdf")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "images must be images")
+
+		images := []project.Document{}
+		for _, d := range list() {
+			if d.Type == project.DocumentImage {
+				images = append(images, d)
+			}
+		}
+		require.Len(t, images, 3)
+
+		// The last image becomes the cover
+		order := map[string]any{"type": "IMAGE", "ids": []uuid.UUID{gallery[1].ID, images[0].ID, gallery[0].ID}}
+		rec = do(http.MethodPut, base+"/documents/order", "application/json", itesting.MustMarshalJSON(t, order))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var reordered []project.Document
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reordered))
+		require.Len(t, reordered, 3)
+		assert.Equal(t, gallery[1].ID, reordered[0].ID)
+		assert.Equal(t, gallery[1].URL, imageURL())
+
+		// Every image must be listed
+		order = map[string]any{"type": "IMAGE", "ids": []uuid.UUID{gallery[1].ID, images[0].ID}}
+		rec = do(http.MethodPut, base+"/documents/order", "application/json", itesting.MustMarshalJSON(t, order))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("UpdateReplacesCover", func(t *testing.T) {
+		ct, body := createMultipartBody(t, map[string]string{"title": "Mangrove Restoration"}, "image", "new-cover.jpg", []byte("new-image"))
+		rec := do(http.MethodPatch, base, ct, body)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var updated project.Project
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+		assert.Contains(t, updated.ImageURL, "new-cover.jpg")
+
+		images := []project.Document{}
+		for _, d := range list() {
+			if d.Type == project.DocumentImage {
+				images = append(images, d)
+			}
+		}
+		require.Len(t, images, 3, "the replaced cover is removed, not kept alongside")
+		assert.Equal(t, "new-cover.jpg", images[0].FileName)
+		for _, d := range images {
+			assert.NotEqual(t, gallery[1].ID, d.ID)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		rec := do(http.MethodDelete, base+"/documents/"+gallery[0].ID.String(), "", nil)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		rec = do(http.MethodDelete, base+"/documents/"+gallery[0].ID.String(), "", nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("FrozenAfterSubmission", func(t *testing.T) {
+		rec := do(http.MethodPost, base+"/submit", "", nil)
+		require.Equal(t, http.StatusAccepted, rec.Code)
+
+		rec = add("METHODOLOGY", "method.pdf")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = add("MONITORING_REPORT", "2026-q3.pdf")
+		assert.Equal(t, http.StatusCreated, rec.Code, "monitoring reports are not part of the review")
+	})
+}
+
+func TestUploadKeyIssuedTo(t *testing.T) {
+	key := "documents/20261018093000-user_abc-042917.pdf"
+	assert.True(t, upload.IssuedTo(key, "documents", "user_abc"))
+	assert.False(t, upload.IssuedTo(key, "projects", "user_abc"), "other folder")
+	assert.False(t, upload.IssuedTo(key, "documents", "user_ab"), "user id prefix")
+	assert.False(t, upload.IssuedTo(key, "documents", ""))
+	assert.False(t, upload.IssuedTo("documents/nested/20261018093000-user_abc-042917.pdf", "documents", "user_abc"))
+	assert.False(t, upload.IssuedTo("documents/user_abc-042917.pdf", "documents", "user_abc"))
+}
+
+func TestProjectDocumentDirectUpload(t *testing.T) {
+	testDB, dbCleanup := itesting.SetupTestDB(t)
+	defer dbCleanup()
+
+	logger := zerolog.New(zerolog.NewConsoleWriter()).With().Timestamp().Logger()
+	srv := itesting.CreateTestServer(&logger, testDB)
+	srv.Uploader = itesting.SetupTestBucket(t)
+
+	repos := repository.NewRepositories(srv)
+	services, err := service.NewServices(srv, repos)
+	require.NoError(t, err)
+	e := router.NewRouter(srv, handler.NewHandlers(srv, services), services)
+
+	request := func(method, path string, body any) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(itesting.MustMarshalJSON(t, body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, method+" "+path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+	require.Equal(t, http.StatusOK, request(http.MethodPost, "/api/v1/auth/sync-user", user.SyncUserPayload{Email: "test@example.com"}).Code)
+
+	ctx := context.Background()
+	p, err := repos.Project.Create(ctx, project.Project{
+		SupplierID:   "user_test_mock_123",
+		Title:        "Kuching Wetlands",
+		Description:  "Wetland restoration near Kuching.",
+		ImageURL:     "https://example.com/p.jpg",
+		LocationLat:  1.505,
+		LocationLng:  110.355,
+		Area:         120,
+		CarbonAmount: 100,
+		Status:       project.ProjectStatusDraft,
+	})
+	require.NoError(t, err)
+	base := "/api/v1/projects/" + p.ID.String() + "/documents/uploads"
+
+	// put sends content to a presigned URL the way a browser would
+	put := func(u upload.PresignedUpload, content []byte) int {
+		req, err := http.NewRequest(u.Method, u.URL, bytes.NewReader(content))
+		require.NoError(t, err)
+		for name, value := range u.Headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	content := []byte("%PDF-1.7 methodology")
+	presign := map[string]any{"type": "METHODOLOGY", "fileName": "method.pdf", "contentType": "application/pdf", "size": len(content)}
+
+	rec := request(http.MethodPost, base, presign)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var issued upload.PresignedUpload
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &issued))
+	assert.Equal(t, http.MethodPut, issued.Method)
+	assert.True(t, strings.HasPrefix(issued.Key, "documents/"))
+	assert.Equal(t, "application/pdf", issued.Headers["Content-Type"])
+
+	confirm := map[string]any{"type": "METHODOLOGY", "key": issued.Key, "fileName": "method.pdf"}
+
+	t.Run("NotUploadedYet", func(t *testing.T) {
+		rec := request(http.MethodPost, base+"/confirm", confirm)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("BucketEnforcesSignedSize", func(t *testing.T) {
+		assert.NotEqual(t, http.StatusOK, put(issued, append(content, make([]byte, 1<<10)...)))
+	})
+
+	var attached project.Document
+	t.Run("Confirm", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, put(issued, content))
+
+		rec := request(http.MethodPost, base+"/confirm", confirm)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &attached))
+		assert.Equal(t, project.DocumentMethodology, attached.Type)
+		assert.Equal(t, "method.pdf", attached.FileName)
+		assert.Equal(t, srv.Uploader.URL(issued.Key), attached.URL)
+		require.NotNil(t, attached.SizeBytes)
+		assert.Equal(t, int64(len(content)), *attached.SizeBytes)
+
+		rec = request(http.MethodPost, base+"/confirm", confirm)
+		assert.Equal(t, http.StatusConflict, rec.Code, "an upload is attached once")
+	})
+
+	t.Run("Refused", func(t *testing.T) {
+		rec := request(http.MethodPost, base, map[string]any{"type": "IMAGE", "fileName": "method.pdf", "contentType": "application/pdf", "size": 100})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = request(http.MethodPost, base, map[string]any{"type": "LAND_TITLE", "fileName": "deed.pdf", "contentType": "application/pdf", "size": project.MaxDocumentSize + 1})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		forged := map[string]any{"type": "METHODOLOGY", "key": "documents/20261018093000-user_someone_else-123456.pdf", "fileName": "theirs.pdf"}
+		rec = request(http.MethodPost, base+"/confirm", forged)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		wrongType := map[string]any{"type": "IMAGE", "key": issued.Key, "fileName": "method.pdf"}
+		rec = request(http.MethodPost, base+"/confirm", wrongType)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("DeleteRemovesObject", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/projects/"+p.ID.String()+"/documents/"+attached.ID.String(), nil)
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		_, err := srv.Uploader.Head(ctx, issued.Key)
+		assert.ErrorIs(t, err, upload.ErrObjectNotFound)
+	})
+}
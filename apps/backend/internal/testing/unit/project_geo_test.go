@@ -0,0 +1,134 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoJSON(t *testing.T) {
+	t.Run("ParseBBox", func(t *testing.T) {
+		b, err := project.ParseBBox("109.5, 1.2,111,2.5")
+		require.NoError(t, err)
+		assert.Equal(t, project.BBox{MinLng: 109.5, MinLat: 1.2, MaxLng: 111, MaxLat: 2.5}, b)
+
+		for _, bad := range []string{"", "1,2,3", "a,b,c,d", "111,1,109,2", "0,-91,1,0", "-181,0,0,1"} {
+			_, err := project.ParseBBox(bad)
+			assert.Error(t, err, bad)
+		}
+	})
+
+	t.Run("FeatureCollection", func(t *testing.T) {
+		p := project.Project{Title: "Mangrove Restoration", LocationLat: 1.55, LocationLng: 110.36, Status: project.ProjectStatusDeployed}
+		p.ID = uuid.MustParse("6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b")
+
+		body, err := json.Marshal(project.NewFeatureCollection([]project.Feature{project.NewFeature(p)}))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"type": "FeatureCollection",
+			"features": [{
+				"type": "Feature",
+				"id": "6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b",
+				"geometry": {"type": "Point", "coordinates": [110.36, 1.55]},
+				"properties": {
+					"title": "Mangrove Restoration", "status": "DEPLOYED", "imageUrl": "",
+					"area": 0, "carbonAmount": 0, "pricePerTonne": 0
+				}
+			}]
+		}`, string(body))
+
+		empty, err := json.Marshal(project.NewFeatureCollection(nil))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"FeatureCollection","features":[]}`, string(empty))
+	})
+}
+
+func TestGeoSearch(t *testing.T) {
+	_, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repos := repository.NewRepositories(srv)
+
+	seed := func(title string, lat, lng float64, status project.ProjectStatus) *project.Project {
+		p, err := repos.Project.Create(ctx, project.Project{
+			SupplierID:   "user_geo_supplier",
+			Title:        title,
+			Description:  "A project somewhere in Sarawak.",
+			ImageURL:     "https://example.com/p.jpg",
+			LocationLat:  lat,
+			LocationLng:  lng,
+			CarbonAmount: 100,
+			Status:       status,
+		})
+		require.NoError(t, err)
+		return p
+	}
+
+	kuching := seed("Kuching Wetlands", 1.5535, 110.3593, project.ProjectStatusDeployed)
+	sibu := seed("Sibu Peatland", 2.2870, 111.8305, project.ProjectStatusApproved)
+	miri := seed("Miri Mangroves", 4.3995, 113.9914, project.ProjectStatusDeployed)
+	seed("Kuching Draft", 1.5600, 110.3600, project.ProjectStatusDraft)
+
+	get := func(path string) (*project.FeatureCollection, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "GET "+path, rec.Code, rec.Body.Bytes())
+
+		if rec.Code != http.StatusOK {
+			return nil, rec
+		}
+		var fc project.FeatureCollection
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &fc))
+		return &fc, rec
+	}
+
+	ids := func(fc *project.FeatureCollection) []uuid.UUID {
+		out := make([]uuid.UUID, len(fc.Features))
+		for i, f := range fc.Features {
+			out[i] = f.ID
+		}
+		return out
+	}
+
+	t.Run("BBox", func(t *testing.T) {
+		fc, rec := get("/api/v1/public/projects/map?bbox=109.5,1.0,112.5,3.0")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/geo+json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "FeatureCollection", fc.Type)
+		assert.ElementsMatch(t, []uuid.UUID{kuching.ID, sibu.ID}, ids(fc))
+		assert.Equal(t, [2]float64{110.3593, 1.5535}, fc.Features[len(fc.Features)-1].Geometry.Coordinates)
+
+		_, rec = get("/api/v1/public/projects/map?bbox=112.5,1.0,109.5,3.0")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		_, rec = get("/api/v1/public/projects/map")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Radius", func(t *testing.T) {
+		fc, rec := get("/api/v1/public/projects/nearby?lat=1.5535&lng=110.3593&radiusKm=300")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []uuid.UUID{kuching.ID, sibu.ID}, ids(fc))
+		require.NotNil(t, fc.Features[1].Properties.DistanceKm)
+		assert.InDelta(t, 183, *fc.Features[1].Properties.DistanceKm, 10)
+
+		fc, rec = get("/api/v1/public/projects/nearby?lat=1.5535&lng=110.3593&radiusKm=1000")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []uuid.UUID{kuching.ID, sibu.ID, miri.ID}, ids(fc))
+
+		_, rec = get("/api/v1/public/projects/nearby?lat=1.5&lng=110.3")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		_, rec = get("/api/v1/public/projects/nearby?lat=100&lng=110.3&radiusKm=5")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
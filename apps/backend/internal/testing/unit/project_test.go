@@ -8,8 +8,11 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/inventedsarawak/ledgera/internal/model"
 	"github.com/inventedsarawak/ledgera/internal/model/project"
 	"github.com/inventedsarawak/ledgera/internal/model/user"
 	itesting "github.com/inventedsarawak/ledgera/internal/testing"
@@ -178,6 +181,8 @@ func TestProjectCRUDAndSubmission(t *testing.T) {
 	// UPDATE (allowed in DRAFT)
 	updateFields := map[string]string{
 		"title": "Mangrove + Coastal",
+		// Only deployment sets the contract address; suppliers cannot
+		"contractAddress": "0x5FbDB2315678afecb367f032d93F642f64180aa3",
 	}
 	ct, body = createMultipartBody(t, updateFields, "", "", nil)
 	req = httptest.NewRequest(http.MethodPatch, "/api/v1/projects/"+created.ID.String(), bytes.NewReader(body))
@@ -191,6 +196,7 @@ func TestProjectCRUDAndSubmission(t *testing.T) {
 	var updated project.Project
 	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
 	assert.Equal(t, "Mangrove + Coastal", updated.Title)
+	assert.Nil(t, updated.ContractAddress)
 
 	// SUBMIT FOR APPROVAL (transitions to PENDING)
 	req = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+created.ID.String()+"/submit", nil)
@@ -248,9 +254,11 @@ func TestProjectCRUDAndSubmission(t *testing.T) {
 }
 
 func TestAdminProjectWorkflow(t *testing.T) {
-	_, _, e, cleanup := itesting.SetupTest(t)
+	_, srv, e, cleanup := itesting.SetupTest(t)
 	defer cleanup()
 
+	_, mail := itesting.DryRunEmail(t, srv)
+
 	// Ensure mock user exists (bypass auth sync)
 	{
 		payload := user.SyncUserPayload{Email: "admin@example.com"}
@@ -327,6 +335,11 @@ func TestAdminProjectWorkflow(t *testing.T) {
 	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &approved))
 	assert.Equal(t, project.ProjectStatusApproved, approved.Status)
 
+	sent := mail.Messages()
+	require.NotEmpty(t, sent)
+	assert.Equal(t, "admin@example.com", sent[len(sent)-1].To)
+	assert.Equal(t, `Your project "Carbon Project Alpha" has been approved`, sent[len(sent)-1].Subject)
+
 	// 5. Test Reject workflow with a fresh project
 	// Create another project
 	ct2, body2 := createMultipartBodyWithFiles(t, map[string]string{
@@ -358,14 +371,167 @@ func TestAdminProjectWorkflow(t *testing.T) {
 	e.ServeHTTP(rec, req)
 	require.Equal(t, http.StatusAccepted, rec.Code)
 
+	reject := func(body any) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+created2.ID.String()+"/reject", bytes.NewReader(itesting.MustMarshalJSON(t, body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "Reject", rec.Code, rec.Body.Bytes())
+		return rec
+	}
+	rejection := map[string]any{
+		"code":    "AREA_MISMATCH",
+		"message": "The audit report only covers 40 hectares.",
+		"field":   "area",
+	}
+
+	// A reason is required
+	rec = reject(map[string]any{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
 	// Reject it
-	req = httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+created2.ID.String()+"/reject", nil)
-	req.Header.Set("X-Test-Auth", "bypass")
-	rec = httptest.NewRecorder()
-	e.ServeHTTP(rec, req)
+	rec = reject(rejection)
 	require.Equal(t, http.StatusOK, rec.Code)
 
 	var rejected project.Project
 	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rejected))
 	assert.Equal(t, project.ProjectStatusRejected, rejected.Status)
+
+	sent = mail.Messages()
+	rejectionMail := sent[len(sent)-1]
+	assert.Equal(t, `Your project "Carbon Project Beta" needs changes`, rejectionMail.Subject)
+	assert.Contains(t, rejectionMail.HTML, "Area mismatch: The audit report only covers 40 hectares.")
+
+	// Rejecting twice is not a transition
+	rec = reject(rejection)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// The reason opens a review thread the supplier can reply to
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+created2.ID.String(), nil)
+	req.Header.Set("X-Test-Auth", "bypass")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var detail project.ProjectDetail
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &detail))
+	require.Len(t, detail.ReviewComments, 1)
+	thread := detail.ReviewComments[0]
+	assert.Equal(t, "The audit report only covers 40 hectares.", thread.Body)
+	require.NotNil(t, thread.Field)
+	assert.Equal(t, "area", *thread.Field)
+
+	comment := func(body any) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/"+created2.ID.String()+"/comments", bytes.NewReader(itesting.MustMarshalJSON(t, body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "Comment", rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	rec = comment(map[string]any{"body": "The second annex covers the rest.", "parentId": thread.ID.String()})
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var reply project.ReviewComment
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reply))
+	require.NotNil(t, reply.ParentID)
+	assert.Equal(t, thread.ID, *reply.ParentID)
+	require.NotNil(t, reply.Field)
+	assert.Equal(t, "area", *reply.Field)
+
+	// Replying to a reply stays in the same thread
+	rec = comment(map[string]any{"body": "Thanks, please upload it.", "parentId": reply.ID.String()})
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+created2.ID.String()+"/comments", nil)
+	req.Header.Set("X-Test-Auth", "bypass")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var threads []project.ReviewComment
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &threads))
+	require.Len(t, threads, 1)
+	assert.Len(t, threads[0].Replies, 2)
+
+	// Editing a rejected project reopens it as a draft
+	ct3, body3 := createMultipartBody(t, map[string]string{"title": "Carbon Project Beta v2"}, "", "", nil)
+	req = httptest.NewRequest(http.MethodPatch, "/api/v1/projects/"+created2.ID.String(), bytes.NewReader(body3))
+	req.Header.Set("Content-Type", ct3)
+	req.Header.Set("X-Test-Auth", "bypass")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var reopened project.Project
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reopened))
+	assert.Equal(t, project.ProjectStatusDraft, reopened.Status)
+
+	// 6. Every transition is in the history, oldest first
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/projects/"+created2.ID.String()+"/history", nil)
+	req.Header.Set("X-Test-Auth", "bypass")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	logResp(t, "History", rec.Code, rec.Body.Bytes())
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var history []project.StatusChange
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	require.Len(t, history, 3)
+	assert.Equal(t, project.ProjectStatusDraft, history[0].FromStatus)
+	assert.Equal(t, project.ProjectStatusPending, history[0].ToStatus)
+	assert.Equal(t, project.ProjectStatusRejected, history[1].ToStatus)
+	assert.Equal(t, project.ProjectStatusDraft, history[2].ToStatus)
+	require.NotNil(t, history[1].ActorID)
+	assert.Equal(t, "user_test_mock_123", *history[1].ActorID)
+	require.NotNil(t, history[1].ReasonCode)
+	assert.Equal(t, project.RejectionAreaMismatch, *history[1].ReasonCode)
+
+	// Comments close once the project is a draft again
+	rec = comment(map[string]any{"body": "Too late for this."})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestThreadReviewComments(t *testing.T) {
+	root := uuid.New()
+	other := uuid.New()
+	comments := []project.ReviewComment{
+		{Base: model.Base{BaseWithId: model.BaseWithId{ID: root}}, Body: "first"},
+		{Base: model.Base{BaseWithId: model.BaseWithId{ID: other}}, Body: "second"},
+		{Base: model.Base{BaseWithId: model.BaseWithId{ID: uuid.New()}}, ParentID: &root, Body: "reply"},
+	}
+
+	threads := project.ThreadComments(comments)
+	require.Len(t, threads, 2)
+	assert.Equal(t, "first", threads[0].Body)
+	require.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, "reply", threads[0].Replies[0].Body)
+	assert.Empty(t, threads[1].Replies)
+
+	assert.Equal(t, "Area mismatch", project.RejectionAreaMismatch.Label())
+}
+
+func TestProjectStatusTransitions(t *testing.T) {
+	allowed := map[project.ProjectStatus][]project.ProjectStatus{
+		project.ProjectStatusDraft:    {project.ProjectStatusPending},
+		project.ProjectStatusPending:  {project.ProjectStatusApproved, project.ProjectStatusRejected},
+		project.ProjectStatusApproved: {project.ProjectStatusDeployed},
+		project.ProjectStatusRejected: {project.ProjectStatusDraft, project.ProjectStatusPending},
+		project.ProjectStatusDeployed: {},
+	}
+
+	for from, targets := range allowed {
+		for to := range allowed {
+			assert.Equal(t, slices.Contains(targets, to), from.CanTransitionTo(to), "%s -> %s", from, to)
+		}
+	}
+
+	assert.True(t, project.ProjectStatusDraft.Editable())
+	assert.True(t, project.ProjectStatusRejected.Editable())
+	assert.False(t, project.ProjectStatusPending.Editable())
+	assert.False(t, project.ProjectStatusApproved.Editable())
+	assert.False(t, project.ProjectStatusDeployed.Editable())
 }
@@ -0,0 +1,326 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/inventedsarawak/ledgera/internal/lib/upload"
+	"github.com/inventedsarawak/ledgera/internal/model"
+	"github.com/inventedsarawak/ledgera/internal/model/listing"
+	"github.com/inventedsarawak/ledgera/internal/model/project"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	"github.com/inventedsarawak/ledgera/internal/service"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteMe(t *testing.T) {
+	testDB, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+	srv.Uploader = itesting.SetupTestBucket(t)
+
+	ctx := context.Background()
+	repos := repository.NewRepositories(srv)
+
+	request := func(method, path string, body any) *httptest.ResponseRecorder {
+		var reader *bytes.Reader
+		if body != nil {
+			reader = bytes.NewReader(itesting.MustMarshalJSON(t, body))
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, method+" "+path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	rec := request(http.MethodPost, "/api/v1/auth/sync-user", user.SyncUserPayload{Email: "leaver@example.com"})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	newProject := func(supplierID string, status project.ProjectStatus) *project.Project {
+		p, err := repos.Project.Create(ctx, project.Project{
+			SupplierID:   supplierID,
+			Title:        "Peatland Rewetting",
+			Description:  "Blocking drainage canals.",
+			ImageURL:     "https://example.com/peat.jpg",
+			CarbonAmount: 200,
+			Status:       status,
+		})
+		require.NoError(t, err)
+		return p
+	}
+
+	// A project under review blocks deletion
+	pending := newProject("user_test_mock_123", project.ProjectStatusPending)
+	rec = request(http.MethodDelete, "/api/v1/me", nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	_, err := testDB.Pool.Exec(ctx, `UPDATE projects SET status = 'REJECTED' WHERE id = $1`, pending.ID)
+	require.NoError(t, err)
+	draft := newProject("user_test_mock_123", project.ProjectStatusDraft)
+
+	// So does an open listing
+	_, err = testDB.Pool.Exec(ctx, `UPDATE users SET wallet_address = $1 WHERE clerk_id = 'user_test_mock_123'`, testSellerWallet)
+	require.NoError(t, err)
+	_, _, err = repos.User.UpsertUser(ctx, "user_delete_supplier", "supplier@example.com", user.RoleSupplier)
+	require.NoError(t, err)
+	deployed := newProject("user_delete_supplier", project.ProjectStatusDeployed)
+	l, err := repos.Listing.Create(ctx, listing.Listing{
+		ProjectID: deployed.ID, SellerAddress: testSellerWallet, PricePerToken: 10, QuantityAvailable: 5, Active: true,
+	})
+	require.NoError(t, err)
+
+	rec = request(http.MethodDelete, "/api/v1/me", nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	_, err = repos.Listing.Deactivate(ctx, l.ID.String())
+	require.NoError(t, err)
+
+	store := func(folder, filename, contentType string) string {
+		key, err := srv.Uploader.Store(ctx, upload.UploadParams{
+			File:        strings.NewReader("%PDF-1.4"),
+			Folder:      folder,
+			Filename:    filename,
+			UserID:      "user_test_mock_123",
+			ContentType: contentType,
+			Size:        8,
+		})
+		require.NoError(t, err)
+		return key
+	}
+
+	// A document on the draft and a certificate naming the account's email
+	documentKey := store("documents", "audit.pdf", "application/pdf")
+	_, err = testDB.Pool.Exec(ctx, `
+		INSERT INTO project_documents (project_id, type, object_key, url, file_name, uploaded_by)
+		VALUES ($1, 'AUDIT_REPORT', $2, $3, 'audit.pdf', 'user_test_mock_123')`,
+		draft.ID, documentKey, srv.Uploader.URL(documentKey))
+	require.NoError(t, err)
+
+	pdfKey := store(service.CertificateFolder, "certificate.pdf", "application/pdf")
+	var certificateID string
+	require.NoError(t, testDB.Pool.QueryRow(ctx, `
+		INSERT INTO certificates (owner_id, project_id, tx_hash, amount_retired, wallet_address, beneficiary_name, pdf_url)
+		VALUES ('user_test_mock_123', $1, '0xdeleteme', 1, $2, 'leaver@example.com', $3)
+		RETURNING id::text`,
+		deployed.ID, testSellerWallet, srv.Uploader.URL(pdfKey)).Scan(&certificateID))
+
+	// DELETE soft-deletes and anonymizes
+	rec = request(http.MethodDelete, "/api/v1/me", nil)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	u, err := repos.User.FindByClerkID(ctx, "user_test_mock_123")
+	require.NoError(t, err)
+	assert.Nil(t, u)
+
+	var email string
+	var wallet *string
+	require.NoError(t, testDB.Pool.QueryRow(ctx,
+		`SELECT email, wallet_address FROM users WHERE clerk_id = 'user_test_mock_123'`).Scan(&email, &wallet))
+	assert.NotContains(t, email, "leaver")
+	assert.Nil(t, wallet)
+
+	// The certificate names the wallet instead and its PDF is withdrawn
+	var beneficiary string
+	var pdfURL *string
+	require.NoError(t, testDB.Pool.QueryRow(ctx,
+		`SELECT beneficiary_name, pdf_url FROM certificates WHERE id = $1`, certificateID).Scan(&beneficiary, &pdfURL))
+	assert.Equal(t, testSellerWallet, beneficiary)
+	assert.Nil(t, pdfURL)
+	_, err = srv.Uploader.Head(ctx, pdfKey)
+	assert.ErrorIs(t, err, upload.ErrObjectNotFound)
+
+	// The account stays deleted
+	rec = request(http.MethodPost, "/api/v1/auth/sync-user", user.SyncUserPayload{Email: "leaver@example.com"})
+	assert.Equal(t, http.StatusGone, rec.Code)
+	rec = request(http.MethodDelete, "/api/v1/me", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// PURGE only once the retention period has passed
+	users := service.NewUserService(srv, repos.User, repos.Project, repos.Listing)
+	purged, err := users.PurgeDeletedUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+
+	_, err = testDB.Pool.Exec(ctx, `UPDATE users SET deleted_at = $1 WHERE clerk_id = 'user_test_mock_123'`,
+		time.Now().Add(-service.DefaultAccountRetention-time.Hour))
+	require.NoError(t, err)
+
+	purged, err = users.PurgeDeletedUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var remaining int
+	require.NoError(t, testDB.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM users WHERE clerk_id = 'user_test_mock_123'`).Scan(&remaining))
+	assert.Equal(t, 0, remaining)
+
+	// Unsubmitted projects go with the account; other records stay
+	gone, err := repos.Project.FindByID(ctx, draft.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, gone)
+	kept, err := repos.Project.FindByID(ctx, deployed.ID.String())
+	require.NoError(t, err)
+	assert.NotNil(t, kept)
+
+	// and so do their documents in the bucket
+	_, err = srv.Uploader.Head(ctx, documentKey)
+	assert.ErrorIs(t, err, upload.ErrObjectNotFound)
+}
+
+// fakeClerk records the public metadata written through the Clerk API.
+type fakeClerk struct {
+	mu       sync.Mutex
+	fail     bool
+	metadata map[string]json.RawMessage
+}
+
+func (f *fakeClerk) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v1/users/"), "/metadata")
+	if r.Method != http.MethodPatch || !ok || f.fail {
+		http.Error(w, `{"errors":[{"code":"internal"}]}`, http.StatusInternalServerError)
+		return
+	}
+
+	var params struct {
+		PublicMetadata json.RawMessage `json:"public_metadata"`
+	}
+	body, _ := io.ReadAll(r.Body)
+	_ = json.Unmarshal(body, &params)
+	f.metadata[id] = params.PublicMetadata
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"object":"user","id":"` + id + `"}`))
+}
+
+func TestUserProfileAndDirectory(t *testing.T) {
+	_, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repos := repository.NewRepositories(srv)
+
+	fake := &fakeClerk{metadata: map[string]json.RawMessage{}}
+	clerkAPI := httptest.NewServer(fake)
+	defer clerkAPI.Close()
+	clerk.SetBackend(clerk.NewBackend(&clerk.BackendConfig{URL: clerk.String(clerkAPI.URL + "/v1")}))
+	defer clerk.SetBackend(clerk.NewBackend(&clerk.BackendConfig{}))
+
+	request := func(method, path string, body any) *httptest.ResponseRecorder {
+		var reader *bytes.Reader
+		if body != nil {
+			reader = bytes.NewReader(itesting.MustMarshalJSON(t, body))
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, method+" "+path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	rec := request(http.MethodPost, "/api/v1/auth/sync-user", user.SyncUserPayload{Email: "admin@example.com"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	var me user.User
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &me))
+
+	buyer, _, err := repos.User.UpsertUser(ctx, "user_directory_buyer", "buyer@example.com", user.RoleBuyer)
+	require.NoError(t, err)
+	_, _, err = repos.User.UpsertUser(ctx, "user_directory_supplier", "supplier@example.com", user.RoleSupplier)
+	require.NoError(t, err)
+
+	t.Run("GetAndUpdateMe", func(t *testing.T) {
+		rec := request(http.MethodGet, "/api/v1/me", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got user.User
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, "admin@example.com", got.Email)
+
+		rec = request(http.MethodPatch, "/api/v1/me", map[string]any{"walletAddress": testSellerWallet})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		require.NotNil(t, got.WalletAddress)
+		assert.Equal(t, testSellerWallet, *got.WalletAddress)
+
+		rec = request(http.MethodPatch, "/api/v1/me", map[string]any{"walletAddress": "not-a-wallet"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		// Someone else's wallet cannot be claimed
+		other := otherSellerWallet
+		_, err := repos.User.Update(ctx, buyer.ID.String(), &other, nil)
+		require.NoError(t, err)
+		rec = request(http.MethodPatch, "/api/v1/me", map[string]any{"walletAddress": otherSellerWallet})
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		var page model.PaginatedResponse[user.User]
+
+		rec := request(http.MethodGet, "/api/v1/users?role=BUYER", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, "buyer@example.com", page.Data[0].Email)
+
+		rec = request(http.MethodGet, "/api/v1/users?search=SUPPLIER", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, "user_directory_supplier", page.Data[0].ClerkID)
+
+		rec = request(http.MethodGet, "/api/v1/users?page=2&limit=2", nil)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		assert.Equal(t, 3, page.Total)
+		assert.Equal(t, 2, page.TotalPages)
+		assert.Len(t, page.Data, 1)
+
+		rec = request(http.MethodGet, "/api/v1/users?role=OWNER", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("RoleChangePushedToClerk", func(t *testing.T) {
+		path := "/api/v1/users/" + buyer.ID.String()
+
+		rec := request(http.MethodPatch, path, map[string]any{"role": "SUPPLIER"})
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got user.User
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, user.RoleSupplier, got.Role)
+		assert.JSONEq(t, `{"role":"SUPPLIER"}`, string(fake.metadata["user_directory_buyer"]))
+
+		// When Clerk cannot be updated the role stays as it was
+		fake.fail = true
+		rec = request(http.MethodPatch, path, map[string]any{"role": "ADMIN"})
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+		fake.fail = false
+
+		stored, err := repos.User.FindByClerkID(ctx, "user_directory_buyer")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleSupplier, stored.Role)
+
+		rec = request(http.MethodPatch, "/api/v1/users/"+me.ID.String(), map[string]any{"role": "BUYER"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,224 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/inventedsarawak/ledgera/internal/lib/siwe"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSIWE(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	issuedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	msg := siwe.Message{
+		Domain:         "ledgera.example.com",
+		Address:        address,
+		Statement:      "Link this wallet to your Ledgera account.",
+		URI:            "https://ledgera.example.com",
+		ChainID:        11155111,
+		Nonce:          "abc123DEF456ghi78",
+		IssuedAt:       issuedAt,
+		ExpirationTime: issuedAt.Add(5 * time.Minute),
+	}
+
+	t.Run("Format", func(t *testing.T) {
+		expected := "ledgera.example.com wants you to sign in with your Ethereum account:\n" +
+			address.Hex() + "\n\n" +
+			"Link this wallet to your Ledgera account.\n\n" +
+			"URI: https://ledgera.example.com\n" +
+			"Version: 1\n" +
+			"Chain ID: 11155111\n" +
+			"Nonce: abc123DEF456ghi78\n" +
+			"Issued At: 2026-01-02T03:04:05Z\n" +
+			"Expiration Time: 2026-01-02T03:09:05Z"
+		assert.Equal(t, expected, msg.String())
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		sig, err := siwe.Sign(msg.String(), key)
+		require.NoError(t, err)
+
+		signer, err := siwe.Recover(msg.String(), sig)
+		require.NoError(t, err)
+		assert.Equal(t, address, signer)
+		assert.NoError(t, siwe.Verify(msg.String(), sig, address))
+	})
+
+	t.Run("RecoveryIDWithoutOffset", func(t *testing.T) {
+		sig, err := siwe.Sign(msg.String(), key)
+		require.NoError(t, err)
+		raw := common.FromHex(sig)
+		raw[crypto.RecoveryIDOffset] -= 27
+
+		signer, err := siwe.Recover(msg.String(), "0x"+common.Bytes2Hex(raw))
+		require.NoError(t, err)
+		assert.Equal(t, address, signer)
+	})
+
+	t.Run("OtherMessage", func(t *testing.T) {
+		sig, err := siwe.Sign(msg.String(), key)
+		require.NoError(t, err)
+		other := msg
+		other.Nonce = "zzz123DEF456ghi78"
+		assert.ErrorIs(t, siwe.Verify(other.String(), sig, address), siwe.ErrAddressMismatch)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := siwe.Recover(msg.String(), "0x1234")
+		assert.ErrorIs(t, err, siwe.ErrInvalidSignature)
+		_, err = siwe.Recover(msg.String(), "not hex")
+		assert.ErrorIs(t, err, siwe.ErrInvalidSignature)
+	})
+
+	t.Run("Nonce", func(t *testing.T) {
+		a, err := siwe.NewNonce()
+		require.NoError(t, err)
+		b, err := siwe.NewNonce()
+		require.NoError(t, err)
+		assert.Len(t, a, 17)
+		assert.NotEqual(t, a, b)
+		assert.Regexp(t, "^[A-Za-z0-9]+$", a)
+	})
+}
+
+func TestWalletVerification(t *testing.T) {
+	_, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+	srv.Redis = itesting.SetupTestRedis(t)
+
+	ctx := context.Background()
+	repos := repository.NewRepositories(srv)
+
+	request := func(method, path string, body any) *httptest.ResponseRecorder {
+		var reader *bytes.Reader
+		if body != nil {
+			reader = bytes.NewReader(itesting.MustMarshalJSON(t, body))
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Auth", "bypass")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, method+" "+path, rec.Code, rec.Body.Bytes())
+		return rec
+	}
+
+	challenge := func(address common.Address) user.WalletChallenge {
+		rec := request(http.MethodPost, "/api/v1/me/wallet/challenge", map[string]string{"address": address.Hex()})
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var c user.WalletChallenge
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &c))
+		return c
+	}
+
+	rec := request(http.MethodPost, "/api/v1/auth/sync-user", user.SyncUserPayload{Email: "holder@example.com"})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	t.Run("TypedWalletIsUnverified", func(t *testing.T) {
+		rec := request(http.MethodPatch, "/api/v1/me", map[string]any{"walletAddress": address.Hex()})
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got user.User
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.False(t, got.HasVerifiedWallet())
+
+		rec = request(http.MethodPost, "/api/v1/listings", map[string]any{
+			"projectId": "8b0f1d0e-4a7e-4a43-9d7b-1f1c2f3e4d5a", "pricePerToken": 10, "quantityAvailable": 5,
+		})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Verify your wallet")
+	})
+
+	t.Run("WrongSigner", func(t *testing.T) {
+		c := challenge(address)
+		assert.Contains(t, c.Message, address.Hex())
+		assert.Contains(t, c.Message, "Nonce: "+c.Nonce)
+
+		other, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		sig, err := siwe.Sign(c.Message, other)
+		require.NoError(t, err)
+
+		rec := request(http.MethodPost, "/api/v1/me/wallet/verify", map[string]string{"nonce": c.Nonce, "signature": sig})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		// The challenge was used up by the failed attempt
+		sig, err = siwe.Sign(c.Message, key)
+		require.NoError(t, err)
+		rec = request(http.MethodPost, "/api/v1/me/wallet/verify", map[string]string{"nonce": c.Nonce, "signature": sig})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Verified", func(t *testing.T) {
+		c := challenge(address)
+		sig, err := siwe.Sign(c.Message, key)
+		require.NoError(t, err)
+
+		rec := request(http.MethodPost, "/api/v1/me/wallet/verify", map[string]string{"nonce": c.Nonce, "signature": sig})
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got user.User
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.True(t, got.HasVerifiedWallet())
+		assert.Equal(t, address.Hex(), *got.WalletAddress)
+
+		// Replaying the same signature is refused
+		rec = request(http.MethodPost, "/api/v1/me/wallet/verify", map[string]string{"nonce": c.Nonce, "signature": sig})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("ChangingWalletClearsVerification", func(t *testing.T) {
+		next := "0x" + strings.Repeat("3", 40)
+		rec := request(http.MethodPatch, "/api/v1/me", map[string]any{"walletAddress": next})
+		require.Equal(t, http.StatusOK, rec.Code)
+		var got user.User
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.False(t, got.HasVerifiedWallet())
+	})
+
+	t.Run("VerifiedOwnerReclaimsSquattedWallet", func(t *testing.T) {
+		squatterKey, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		squatted := crypto.PubkeyToAddress(squatterKey.PublicKey)
+
+		squatter, _, err := repos.User.UpsertUser(ctx, "user_wallet_squatter", "squatter@example.com", user.RoleBuyer)
+		require.NoError(t, err)
+		wallet := squatted.Hex()
+		_, err = repos.User.Update(ctx, squatter.ID.String(), &wallet, nil)
+		require.NoError(t, err)
+
+		c := challenge(squatted)
+		sig, err := siwe.Sign(c.Message, squatterKey)
+		require.NoError(t, err)
+		rec := request(http.MethodPost, "/api/v1/me/wallet/verify", map[string]string{"nonce": c.Nonce, "signature": sig})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		u, err := repos.User.FindByClerkID(ctx, "user_wallet_squatter")
+		require.NoError(t, err)
+		assert.Nil(t, u.WalletAddress)
+
+		// Once verified, the wallet cannot be taken over by another account
+		_, err = repos.User.VerifyWallet(ctx, "user_wallet_squatter", squatted.Hex())
+		assert.ErrorIs(t, err, repository.ErrWalletVerifiedElsewhere)
+	})
+}
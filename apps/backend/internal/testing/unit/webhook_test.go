@@ -0,0 +1,230 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/inventedsarawak/ledgera/internal/lib/webhook"
+	"github.com/inventedsarawak/ledgera/internal/model/user"
+	"github.com/inventedsarawak/ledgera/internal/repository"
+	itesting "github.com/inventedsarawak/ledgera/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func svixHeaders(v *webhook.SvixVerifier, id string, sent time.Time, body []byte) http.Header {
+	h := http.Header{}
+	h.Set(webhook.HeaderSvixID, id)
+	h.Set(webhook.HeaderSvixTimestamp, strconv.FormatInt(sent.Unix(), 10))
+	h.Set(webhook.HeaderSvixSignature, v.Sign(id, sent, body))
+	return h
+}
+
+func TestSvixVerifier(t *testing.T) {
+	secret := "whsec_" + base64.StdEncoding.EncodeToString([]byte("svix-test-secret"))
+	v, err := webhook.NewSvixVerifier(secret)
+	require.NoError(t, err)
+
+	body := []byte(`{"type":"user.created","data":{}}`)
+	now := time.Now()
+
+	t.Run("Valid", func(t *testing.T) {
+		id, err := v.Verify(svixHeaders(v, "msg_1", now, body), body)
+		require.NoError(t, err)
+		assert.Equal(t, "msg_1", id)
+	})
+
+	t.Run("RotatedSecret", func(t *testing.T) {
+		h := svixHeaders(v, "msg_1", now, body)
+		h.Set(webhook.HeaderSvixSignature, "v1,"+base64.StdEncoding.EncodeToString([]byte("old"))+" "+h.Get(webhook.HeaderSvixSignature))
+		_, err := v.Verify(h, body)
+		assert.NoError(t, err)
+	})
+
+	t.Run("TamperedBody", func(t *testing.T) {
+		_, err := v.Verify(svixHeaders(v, "msg_1", now, body), []byte(`{"type":"user.deleted","data":{}}`))
+		assert.ErrorIs(t, err, webhook.ErrInvalidSignature)
+	})
+
+	t.Run("OtherMessageID", func(t *testing.T) {
+		h := svixHeaders(v, "msg_1", now, body)
+		h.Set(webhook.HeaderSvixID, "msg_2")
+		_, err := v.Verify(h, body)
+		assert.ErrorIs(t, err, webhook.ErrInvalidSignature)
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		_, err := v.Verify(svixHeaders(v, "msg_1", now.Add(-time.Hour), body), body)
+		assert.ErrorIs(t, err, webhook.ErrInvalidTimestamp)
+	})
+
+	t.Run("MissingHeaders", func(t *testing.T) {
+		_, err := v.Verify(http.Header{}, body)
+		assert.ErrorIs(t, err, webhook.ErrMissingHeaders)
+	})
+
+	t.Run("InvalidSecret", func(t *testing.T) {
+		_, err := webhook.NewSvixVerifier("whsec_not base64")
+		assert.Error(t, err)
+	})
+}
+
+func TestClerkWebhook(t *testing.T) {
+	_, srv, e, cleanup := itesting.SetupTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repos := repository.NewRepositories(srv)
+	_, sender := itesting.DryRunEmail(t, srv)
+
+	v, err := webhook.NewSvixVerifier(srv.Config.Auth.WebhookSigningSecret)
+	require.NoError(t, err)
+
+	deliver := func(id string, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/clerk", bytes.NewReader([]byte(body)))
+		req.Header = svixHeaders(v, id, time.Now(), []byte(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		logResp(t, "POST /webhooks/clerk "+id, rec.Code, rec.Body.Bytes())
+		return rec.Code
+	}
+
+	clerkUser := func(eventType, metadata string) string {
+		return fmt.Sprintf(`{"type":%q,"object":"event","data":{
+			"id":"user_webhook_1","object":"user","first_name":"Aisha",
+			"primary_email_address_id":"idn_2",
+			"email_addresses":[
+				{"id":"idn_1","email_address":"old@example.com"},
+				{"id":"idn_2","email_address":"aisha@example.com"}
+			],
+			"public_metadata":%s}}`, eventType, metadata)
+	}
+
+	membership := func(eventType, orgID, role string) string {
+		return fmt.Sprintf(`{"type":%q,"object":"event","data":{
+			"id":"orgmem_1","object":"organization_membership","role":%q,
+			"organization":{"id":%q,"object":"organization"},
+			"public_user_data":{"user_id":"user_webhook_1","identifier":"aisha@example.com"}}}`, eventType, role, orgID)
+	}
+	orgID := srv.Config.Auth.OrganizationID
+
+	t.Run("RejectsBadSignature", func(t *testing.T) {
+		body := clerkUser(user.ClerkUserCreated, `{}`)
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/clerk", bytes.NewReader([]byte(body)))
+		req.Header = svixHeaders(v, "msg_forged", time.Now(), []byte(`{}`))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		u, err := repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Nil(t, u)
+	})
+
+	t.Run("UserCreated", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, deliver("msg_created", clerkUser(user.ClerkUserCreated, `{}`)))
+
+		u, err := repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		require.NotNil(t, u)
+		assert.Equal(t, "aisha@example.com", u.Email)
+		assert.Equal(t, user.RoleBuyer, u.Role)
+
+		messages := sender.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, "aisha@example.com", messages[0].To)
+		assert.Contains(t, messages[0].HTML, "Aisha")
+	})
+
+	t.Run("ReplayIgnored", func(t *testing.T) {
+		// Demote through the DB, then replay the original delivery: it must
+		// not be applied again.
+		_, err := repos.User.UpdateRole(ctx, "user_webhook_1", user.RoleSupplier)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusNoContent, deliver("msg_created", clerkUser(user.ClerkUserCreated, `{"role":"BUYER"}`)))
+
+		u, err := repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleSupplier, u.Role)
+		assert.Len(t, sender.Messages(), 1)
+	})
+
+	t.Run("UserUpdatedKeepsRoleWithoutMetadata", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, deliver("msg_updated_1", clerkUser(user.ClerkUserUpdated, `{}`)))
+
+		u, err := repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleSupplier, u.Role)
+	})
+
+	t.Run("UserUpdatedRoleFromMetadata", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, deliver("msg_updated_2", clerkUser(user.ClerkUserUpdated, `{"role":"buyer"}`)))
+
+		u, err := repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleBuyer, u.Role)
+	})
+
+	t.Run("Membership", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, deliver("msg_member_1", membership(user.ClerkMembershipCreated, orgID, "org:ledgera_supplier")))
+		u, err := repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleSupplier, u.Role)
+
+		// Unmapped organization roles leave the user alone
+		require.Equal(t, http.StatusNoContent, deliver("msg_member_2", membership(user.ClerkMembershipUpdated, orgID, "org:member")))
+		u, err = repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleSupplier, u.Role)
+
+		require.Equal(t, http.StatusNoContent, deliver("msg_member_3", membership(user.ClerkMembershipDeleted, orgID, "org:ledgera_supplier")))
+		u, err = repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleBuyer, u.Role)
+	})
+
+	t.Run("MembershipNeverGrantsAdmin", func(t *testing.T) {
+		// Anyone can create an organization and be its admin
+		require.Equal(t, http.StatusNoContent, deliver("msg_member_4", membership(user.ClerkMembershipCreated, "org_someone_else", "org:admin")))
+		require.Equal(t, http.StatusNoContent, deliver("msg_member_5", membership(user.ClerkMembershipCreated, "org_someone_else", "org:ledgera_supplier")))
+		require.Equal(t, http.StatusNoContent, deliver("msg_member_6", membership(user.ClerkMembershipCreated, orgID, "org:admin")))
+
+		u, err := repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleBuyer, u.Role)
+
+		// and admins keep their role whatever their memberships say
+		_, err = repos.User.UpdateRole(ctx, "user_webhook_1", user.RoleAdmin)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, deliver("msg_member_7", membership(user.ClerkMembershipCreated, orgID, "org:ledgera_buyer")))
+		require.Equal(t, http.StatusNoContent, deliver("msg_member_8", membership(user.ClerkMembershipDeleted, orgID, "org:ledgera_buyer")))
+
+		u, err = repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Equal(t, user.RoleAdmin, u.Role)
+	})
+
+	t.Run("UserDeleted", func(t *testing.T) {
+		body := `{"type":"user.deleted","object":"event","data":{"id":"user_webhook_1","object":"user","deleted":true}}`
+		require.Equal(t, http.StatusNoContent, deliver("msg_deleted", body))
+
+		u, err := repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Nil(t, u)
+
+		// A late update does not revive the account
+		require.Equal(t, http.StatusNoContent, deliver("msg_updated_late", clerkUser(user.ClerkUserUpdated, `{}`)))
+		u, err = repos.User.FindByClerkID(ctx, "user_webhook_1")
+		require.NoError(t, err)
+		assert.Nil(t, u)
+	})
+}
@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type DeployProjectRequest struct {
+	ID     string `param:"id" validate:"required,uuid"`
+	Symbol string `json:"symbol" validate:"omitempty,alphanum,min=2,max=11"`
+}
+
+func (r *DeployProjectRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	r.Symbol = strings.ToUpper(r.Symbol)
+	return nil
+}
+
+type MintProjectRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+	// Tonnes to issue; defaults to everything not issued yet.
+	Tonnes float64 `json:"tonnes" validate:"omitempty,gt=0"`
+}
+
+func (r *MintProjectRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type ListIssuancesRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *ListIssuancesRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -0,0 +1,63 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+type RetireRequest struct {
+	ProjectID        string  `param:"id" validate:"required,uuid"`
+	TxHash           string  `json:"txHash" validate:"required,len=66,startswith=0x,hexadecimal"`
+	BeneficiaryName  *string `json:"beneficiaryName" validate:"omitempty,min=1,max=200"`
+	RetirementReason *string `json:"retirementReason" validate:"omitempty,max=1000"`
+}
+
+func (r *RetireRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type GetCertificateRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *GetCertificateRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type ListCertificatesRequest struct {
+	Page  int `query:"page" validate:"omitempty,min=1"`
+	Limit int `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (r *ListCertificatesRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	return nil
+}
+
+type VerifyCertificateRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *VerifyCertificateRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type VerifyCertificateByTxRequest struct {
+	TxHash string `param:"txHash" validate:"required,len=66,startswith=0x,hexadecimal"`
+}
+
+func (r *VerifyCertificateByTxRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type CreateListingRequest struct {
+	ProjectID         string  `json:"projectId" validate:"required,uuid"`
+	PricePerToken     float64 `json:"pricePerToken" validate:"required,gt=0"`
+	QuantityAvailable float64 `json:"quantityAvailable" validate:"required,gt=0"`
+}
+
+func (r *CreateListingRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type GetListingRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *GetListingRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ListListingsRequest pages through open listings. Passing cursor, even
+// empty, switches to keyset pagination and page is ignored.
+type ListListingsRequest struct {
+	ProjectID string  `query:"projectId" validate:"omitempty,uuid"`
+	Page      int     `query:"page" validate:"omitempty,min=1"`
+	Limit     int     `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor    *string `query:"cursor" validate:"omitempty,max=200"`
+}
+
+func (r *ListListingsRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	return nil
+}
+
+// ProjectUUID returns the optional project filter.
+func (r *ListListingsRequest) ProjectUUID() *uuid.UUID {
+	if r.ProjectID == "" {
+		return nil
+	}
+	id := uuid.MustParse(r.ProjectID)
+	return &id
+}
+
+type UpdateListingRequest struct {
+	ID                string   `param:"id" validate:"required,uuid"`
+	PricePerToken     *float64 `json:"pricePerToken" validate:"omitempty,gt=0"`
+	QuantityAvailable *float64 `json:"quantityAvailable" validate:"omitempty,gte=0"`
+}
+
+func (r *UpdateListingRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type DeactivateListingRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *DeactivateListingRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
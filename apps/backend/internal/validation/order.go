@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/inventedsarawak/ledgera/internal/model/order"
+)
+
+type PlaceOrderRequest struct {
+	ListingID string  `json:"listingId" validate:"required,uuid"`
+	Quantity  float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+func (r *PlaceOrderRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type GetOrderRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *GetOrderRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type ListOrdersRequest struct {
+	Status string `query:"status" validate:"omitempty,oneof=RESERVED PAID SETTLED CANCELLED EXPIRED"`
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (r *ListOrdersRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	return nil
+}
+
+// StatusFilter returns the optional status filter.
+func (r *ListOrdersRequest) StatusFilter() *order.OrderStatus {
+	if r.Status == "" {
+		return nil
+	}
+	status := order.OrderStatus(r.Status)
+	return &status
+}
+
+type PayOrderRequest struct {
+	ID               string `param:"id" validate:"required,uuid"`
+	PaymentReference string `json:"paymentReference" validate:"required,max=255"`
+}
+
+func (r *PayOrderRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type SettleOrderRequest struct {
+	ID     string `param:"id" validate:"required,uuid"`
+	TxHash string `json:"txHash" validate:"required,len=66,startswith=0x,hexadecimal"`
+}
+
+func (r *SettleOrderRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type CancelOrderRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *CancelOrderRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -28,10 +28,12 @@ func (r *GetProjectRequest) Validate() error {
 	return validate.Struct(r)
 }
 
-// Empty request for ListMine
+// Empty request for ListMine. Passing cursor, even empty, switches to
+// keyset pagination and page is ignored.
 type ListProjectsRequest struct {
-	Page  int `query:"page" validate:"omitempty,min=1"`
-	Limit int `query:"limit" validate:"omitempty,min=1,max=100"`
+	Page   int     `query:"page" validate:"omitempty,min=1"`
+	Limit  int     `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor *string `query:"cursor" validate:"omitempty,max=200"`
 }
 
 func (r *ListProjectsRequest) Validate() error {
@@ -49,15 +51,13 @@ func (r *ListProjectsRequest) Validate() error {
 }
 
 type UpdateProjectRequest struct {
-	ID              uuid.UUID `param:"id" validate:"required,uuid"`
-	Title           *string   `json:"title" form:"title" validate:"omitempty,min=3,max=150"`
-	Description     *string   `json:"description" form:"description" validate:"omitempty,min=10"`
-	LocationLat     *float64  `json:"locationLat" form:"locationLat" validate:"omitempty,latitude"`
-	LocationLng     *float64  `json:"locationLng" form:"locationLng" validate:"omitempty,longitude"`
-	ContractAddress *string   `json:"contractAddress" form:"contractAddress" validate:"omitempty,eth_addr"`
-	Area            *float64  `json:"area" form:"area" validate:"omitempty,gt=0"`
-	Status          *string   `json:"status" form:"status" validate:"omitempty,oneof=DRAFT PENDING APPROVED DEPLOYED REJECTED"`
-	CarbonAmount    *float64  `json:"carbonAmount" form:"carbonAmount" validate:"omitempty,gt=0"`
+	ID           uuid.UUID `param:"id" validate:"required,uuid"`
+	Title        *string   `json:"title" form:"title" validate:"omitempty,min=3,max=150"`
+	Description  *string   `json:"description" form:"description" validate:"omitempty,min=10"`
+	LocationLat  *float64  `json:"locationLat" form:"locationLat" validate:"omitempty,latitude"`
+	LocationLng  *float64  `json:"locationLng" form:"locationLng" validate:"omitempty,longitude"`
+	Area         *float64  `json:"area" form:"area" validate:"omitempty,gt=0"`
+	CarbonAmount *float64  `json:"carbonAmount" form:"carbonAmount" validate:"omitempty,gt=0"`
 }
 
 func (r *UpdateProjectRequest) Validate() error {
@@ -74,6 +74,17 @@ func (r *DeleteProjectRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+// SetProjectBoundaryRequest carries the boundary as a multipart file named
+// "boundary".
+type SetProjectBoundaryRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *SetProjectBoundaryRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
 type SendProjectForApprovalRequest struct {
 	ID string `param:"id" validate:"required,uuid"`
 }
@@ -91,3 +102,94 @@ func (r *ReviewProjectRequest) Validate() error {
 	validate := validator.New()
 	return validate.Struct(r)
 }
+
+type RejectProjectRequest struct {
+	ID      string  `param:"id" validate:"required,uuid"`
+	Code    string  `json:"code" validate:"required,oneof=INCOMPLETE_DOCUMENTATION UNVERIFIABLE_CARBON_CLAIM LOCATION_MISMATCH AREA_MISMATCH DUPLICATE_PROJECT OTHER"`
+	Message string  `json:"message" validate:"required,min=10,max=2000"`
+	Field   *string `json:"field" validate:"omitempty,oneof=title description imageUrl auditReportUrl locationLat locationLng area carbonAmount pricePerTonne"`
+}
+
+func (r *RejectProjectRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type CreateReviewCommentRequest struct {
+	ID       string  `param:"id" validate:"required,uuid"`
+	Body     string  `json:"body" validate:"required,min=1,max=2000"`
+	Field    *string `json:"field" validate:"omitempty,oneof=title description imageUrl auditReportUrl locationLat locationLng area carbonAmount pricePerTonne"`
+	ParentID *string `json:"parentId" validate:"omitempty,uuid"`
+}
+
+func (r *CreateReviewCommentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type ListProjectDocumentsRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *ListProjectDocumentsRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// AddProjectDocumentRequest carries the document as a multipart file named
+// "file".
+type AddProjectDocumentRequest struct {
+	ID   string `param:"id" validate:"required,uuid"`
+	Type string `form:"type" validate:"required,oneof=IMAGE AUDIT_REPORT LAND_TITLE METHODOLOGY MONITORING_REPORT"`
+}
+
+func (r *AddProjectDocumentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type DeleteProjectDocumentRequest struct {
+	ID         string `param:"id" validate:"required,uuid"`
+	DocumentID string `param:"documentId" validate:"required,uuid"`
+}
+
+func (r *DeleteProjectDocumentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type ReorderProjectDocumentsRequest struct {
+	ID   string   `param:"id" validate:"required,uuid"`
+	Type string   `json:"type" validate:"required,oneof=IMAGE AUDIT_REPORT LAND_TITLE METHODOLOGY MONITORING_REPORT"`
+	IDs  []string `json:"ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+func (r *ReorderProjectDocumentsRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type PresignProjectDocumentRequest struct {
+	ID          string `param:"id" validate:"required,uuid"`
+	Type        string `json:"type" validate:"required,oneof=IMAGE AUDIT_REPORT LAND_TITLE METHODOLOGY MONITORING_REPORT"`
+	FileName    string `json:"fileName" validate:"required,max=255"`
+	ContentType string `json:"contentType" validate:"required,max=255"`
+	Size        int64  `json:"size" validate:"required,min=1"`
+}
+
+func (r *PresignProjectDocumentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+type ConfirmProjectDocumentRequest struct {
+	ID       string `param:"id" validate:"required,uuid"`
+	Type     string `json:"type" validate:"required,oneof=IMAGE AUDIT_REPORT LAND_TITLE METHODOLOGY MONITORING_REPORT"`
+	Key      string `json:"key" validate:"required,max=1024"`
+	FileName string `json:"fileName" validate:"required,max=255"`
+}
+
+func (r *ConfirmProjectDocumentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
@@ -0,0 +1,15 @@
+package validation
+
+// GetMeRequest is empty: the account is the caller's.
+type GetMeRequest struct{}
+
+func (r *GetMeRequest) Validate() error {
+	return nil
+}
+
+// DeleteMeRequest is empty: the account to delete is the caller's.
+type DeleteMeRequest struct{}
+
+func (r *DeleteMeRequest) Validate() error {
+	return nil
+}